@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/session"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
@@ -32,12 +34,17 @@ import (
 )
 
 var options struct {
-	high       bool
-	low        bool
-	assignment string
-	columns    string
-	incidentID bool
-	status     string
+	high            bool
+	low             bool
+	assignment      string
+	columns         string
+	highlight       string
+	ageBuckets      string
+	incidentID      bool
+	status          string
+	limit           uint
+	refreshInterval time.Duration
+	timeZone        string
 }
 
 var Cmd = &cobra.Command{
@@ -53,16 +60,56 @@ func init() {
 	Cmd.Flags().StringVar(
 		&options.assignment,
 		"assigned-to",
-		"self",
-		"View alerts assigned to your logged in PagerDuty user account (default), selected team or Silent Test.",
+		"",
+		"View alerts assigned to your logged in PagerDuty user account (self), selected team (team) or Silent Test (silentTest).\nDefaults to the assigned_to setting of 'kite config', self when not set.",
 	)
 
 	// Columns displayed
 	Cmd.Flags().StringVar(
 		&options.columns,
 		"columns",
-		"incident.id,alert.id,cluster.name,alert,cluster.id,status,severity",
-		"Specify which columns to display separated by commas without any space in between",
+		"",
+		"Specify which columns to display separated by commas without any space in between.\nDefaults to the columns setting of 'kite config': "+constants.AlertColumns,
+	)
+
+	// Number of incidents fetched
+	Cmd.Flags().UintVar(
+		&options.limit,
+		"limit",
+		0,
+		"Number of incidents fetched, defaults to the incidents_limit setting of 'kite config'",
+	)
+
+	// Automatic refresh
+	Cmd.Flags().DurationVar(
+		&options.refreshInterval,
+		"refresh-interval",
+		0,
+		"Refresh the alerts at this interval, e.g. --refresh-interval=2m, defaults to the refresh_interval setting of 'kite config'",
+	)
+
+	// Time zone of the dates
+	Cmd.Flags().StringVar(
+		&options.timeZone,
+		"time-zone",
+		"",
+		"Time zone of the dates, e.g. --time-zone=Europe/Paris, defaults to the time_zone setting of 'kite config'",
+	)
+
+	// Color coding of the alerts table
+	Cmd.Flags().StringVar(
+		&options.highlight,
+		"highlight",
+		"severity,status,cluster,age",
+		"Specify which rules are used to color code the alerts table separated by commas: severity, status, cluster, age",
+	)
+
+	// Age buckets displayed in the age column
+	Cmd.Flags().StringVar(
+		&options.ageBuckets,
+		"age-buckets",
+		"1h,4h,24h",
+		"Specify the upper bounds of the alert age buckets in ascending order separated by commas",
 	)
 }
 
@@ -82,10 +129,35 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 		tui ui.TUI
 	)
 
+	highlight, err := pdcli.ParseHighlightRules(options.highlight, options.ageBuckets)
+
+	if err != nil {
+		return err
+	}
+
+	// The settings of the config file and the environment are overridden by the flags
+	settings, err := config.LoadSettings(cmd.Flags())
+
+	if err != nil {
+		return err
+	}
+
+	utils.TimeZone = settings.TimeZone
+
+	if settings.Terminal != "" {
+		utils.Emulator = settings.Terminal
+	}
+
 	// Setup TUI
 	tui.Init()
 	utils.InfoLogger.Print("Initialized terminal UI")
 
+	err = session.Apply(cmd, args, &tui)
+
+	if err != nil {
+		return err
+	}
+
 	if utils.Emulator != "" {
 		utils.InfoLogger.Printf("Terminal emulator for cluster login set to: %s", utils.Emulator)
 	} else {
@@ -112,7 +184,9 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 	// UI internals
 	tui.Client = client
 	tui.Username = user.Name
-	tui.Columns = options.columns
+	tui.Columns = settings.Columns
+	tui.TriggeredLimit = settings.TriggeredIncidentsLimit
+	tui.Highlight = highlight
 	tui.Role = user.Role
 
 	// Check for incident ID argument
@@ -158,19 +232,12 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 	utils.InfoLogger.Printf("Incidents urgency set to: %s, %s", constants.StatusLow, constants.StatusHigh)
 	incidentOpts.Urgencies = []string{constants.StatusLow, constants.StatusHigh}
 
-	// Check the assigned-to flag
-	switch options.assignment {
+	// Check the assigned-to setting
+	switch settings.AssignedTo {
 
 	case "team":
-		// Load the configuration file
-		cfg, err := config.Load()
-
-		if err != nil {
-			return err
-		}
-
-		teamID := cfg.TeamID
-		tui.AssignedTo = cfg.Team
+		teamID := settings.TeamID
+		tui.AssignedTo = settings.Team
 
 		if teamID == "" {
 			return fmt.Errorf("no team selected, please run 'kite teams' to set a team")
@@ -178,7 +245,7 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 
 		// Fetch incidents belonging to a specific team
 		incidentOpts.TeamIDs = append(teams, teamID)
-		utils.InfoLogger.Printf("Retrieving incidents assigned to team: %s", cfg.Team)
+		utils.InfoLogger.Printf("Retrieving incidents assigned to team: %s", settings.Team)
 
 		// Fetch incidents with the following statuses
 		incidentOpts.Statuses = append(status, constants.StatusTriggered, constants.StatusAcknowledged)
@@ -209,8 +276,8 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 	}
 
 	// Set the limit on incidents fetched
-	utils.InfoLogger.Printf("Incidents limit set to: %d", constants.IncidentsLimit)
-	incidentOpts.Limit = constants.IncidentsLimit
+	utils.InfoLogger.Printf("Incidents limit set to: %d", settings.IncidentsLimit)
+	incidentOpts.Limit = settings.IncidentsLimit
 
 	// Fetch incidents
 	utils.InfoLogger.Printf("GET: fetching incidents")
@@ -238,6 +305,7 @@ func alertsHandler(cmd *cobra.Command, args []string) error {
 
 	tui.InitAlertsUI(tui.Alerts, ui.AlertsTableTitle, ui.AlertsPageTitle)
 	tui.InitAlertsSecondaryView()
+	tui.StartAutoRefresh(settings.RefreshInterval)
 	// Start TUI
 	err = tui.StartApp()
 
@@ -0,0 +1,150 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package attach
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+// Time given to a new server to create its socket
+const startTimeout = 10 * time.Second
+
+var Cmd = &cobra.Command{
+	Use:   "attach [name] [-- command [args]]",
+	Short: "Attaches the terminal to a kite server, the server is started if it isn't running.",
+	Long: `Displays the slides of the kite server with the given name, default by default.
+If the server isn't running it is started in the background with the given kite command, alerts by default.
+Detach with the detach key binding, the slides keep running until kite is quit.`,
+	Example: "kite attach work -- alerts --assigned-to=team",
+	RunE:    attachHandler,
+}
+
+func attachHandler(cmd *cobra.Command, args []string) error {
+	name := server.DefaultName
+	var cmdArgs []string
+
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		cmdArgs = args[dash:]
+		args = args[:dash]
+	}
+
+	if len(args) > 1 {
+		return fmt.Errorf("accepts at most 1 server name, received %d", len(args))
+	}
+
+	if len(args) == 1 {
+		name = args[0]
+	}
+
+	if !server.IsRunning(name) {
+		profile, _ := cmd.Flags().GetString(config.ProfileFlag)
+		err := startServer(name, profile, cmdArgs)
+
+		if err != nil {
+			return err
+		}
+	} else if len(cmdArgs) > 0 {
+		fmt.Printf("kite server '%s' is already running, ignoring 'kite %s'\n", name, strings.Join(cmdArgs, " "))
+	}
+
+	err := server.Attach(name, os.Stdin, os.Stdout)
+
+	if err != nil {
+		return err
+	}
+
+	if server.IsRunning(name) {
+		fmt.Printf("[detached from kite server '%s']\n", name)
+	} else {
+		fmt.Printf("[kite server '%s' exited]\n", name)
+	}
+
+	return nil
+}
+
+// startServer starts the kite server with the given name in the background and waits for its socket.
+// The output of the server is written to a log file next to its socket.
+// The server uses the given profile, the one of the environment or of the config file when empty.
+func startServer(name string, profile string, args []string) error {
+	executable, err := os.Executable()
+
+	if err != nil {
+		return err
+	}
+
+	socket, err := server.SocketPath(name)
+
+	if err != nil {
+		return err
+	}
+
+	logPath := filepath.Join(filepath.Dir(socket), name+".log")
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+
+	if err != nil {
+		return err
+	}
+
+	defer logFile.Close()
+
+	serverArgs := []string{"server", "--name", name}
+
+	if profile != "" {
+		serverArgs = append(serverArgs, "--"+config.ProfileFlag, profile)
+	}
+
+	serverCmd := exec.Command(executable, append(append(serverArgs, "--"), args...)...)
+	serverCmd.Stdout = logFile
+	serverCmd.Stderr = logFile
+	serverCmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	err = serverCmd.Start()
+
+	if err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+
+	go func() {
+		serverCmd.Wait()
+		close(exited)
+	}()
+
+	deadline := time.After(startTimeout)
+
+	for !server.IsRunning(name) {
+		select {
+		case <-exited:
+			return fmt.Errorf("kite server '%s' exited, see %s", name, logPath)
+		case <-deadline:
+			return fmt.Errorf("kite server '%s' did not start in %s, see %s", name, startTimeout, logPath)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	kiteconfig "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// Printed instead of the secrets
+const hiddenSecret = "********"
+
+var reveal bool
+
+var Cmd = &cobra.Command{
+	Use:   "config",
+	Short: "Views and changes the settings of kite.",
+	Long: `Views and changes the settings of kite, each one is layered from the lowest to the highest precedence:
+its default, the config file, its KITE_* environment variable, e.g. KITE_REFRESH_INTERVAL, and the flag of the commands having it.
+The credentials, the team and the terminal are the ones of the profile selected by --profile.`,
+	Args: cobra.NoArgs,
+	RunE: viewHandler,
+}
+
+var viewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Lists the settings with their value and where it comes from.",
+	Args:  cobra.NoArgs,
+	RunE:  viewHandler,
+}
+
+var getCmd = &cobra.Command{
+	Use:     "get <key>",
+	Short:   "Prints the value of the setting.",
+	Example: "kite config get refresh_interval",
+	Args:    cobra.ExactArgs(1),
+	RunE:    getHandler,
+}
+
+var setCmd = &cobra.Command{
+	Use:     "set <key> [value]",
+	Short:   "Validates the value of the setting and saves it to the config file, the default is restored without value.",
+	Example: "kite config set refresh_interval 2m",
+	Args:    cobra.RangeArgs(1, 2),
+	RunE:    setHandler,
+}
+
+func init() {
+	getCmd.Flags().BoolVar(
+		&reveal,
+		"reveal",
+		false,
+		"Print the value of the secret settings, e.g. api_key",
+	)
+
+	Cmd.AddCommand(viewCmd)
+	Cmd.AddCommand(getCmd)
+	Cmd.AddCommand(setCmd)
+}
+
+// readConfig reads the config file, an empty configuration is returned when there is none
+func readConfig() (*kiteconfig.Config, error) {
+	cfg, err := kiteconfig.Read()
+
+	if os.IsNotExist(err) {
+		return kiteconfig.New(), nil
+	}
+
+	return cfg, err
+}
+
+// display returns the value of the setting as it is printed, the secrets are hidden
+func display(setting kiteconfig.Setting, value string) string {
+	if setting.Secret && value != "" && !reveal {
+		return hiddenSecret
+	}
+
+	return value
+}
+
+func viewHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := readConfig()
+
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE\tDESCRIPTION")
+
+	for _, setting := range kiteconfig.AllSettings() {
+		value, source, _ := cfg.Value(setting.Key)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", setting.Key, display(setting, value), source, setting.Description)
+	}
+
+	return w.Flush()
+}
+
+func getHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := readConfig()
+
+	if err != nil {
+		return err
+	}
+
+	setting, err := kiteconfig.FindSetting(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	value, _, _ := cfg.Value(setting.Key)
+	fmt.Println(display(setting, value))
+
+	return nil
+}
+
+func setHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := readConfig()
+
+	if err != nil {
+		return err
+	}
+
+	setting, err := kiteconfig.FindSetting(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	value := ""
+
+	if len(args) > 1 {
+		value = args[1]
+	}
+
+	err = cfg.Set(setting.Key, value)
+
+	if err != nil {
+		return err
+	}
+
+	err = kiteconfig.Save(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	if override := os.Getenv(setting.Env()); override != "" {
+		fmt.Fprintf(os.Stderr, "The %s environment variable overrides the %s setting.\n", setting.Env(), setting.Key)
+	}
+
+	if value == "" {
+		fmt.Printf("The %s setting is reset.\n", setting.Key)
+	} else {
+		fmt.Printf("The %s setting is set to %s.\n", setting.Key, strings.TrimSpace(display(setting, value)))
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -28,12 +29,15 @@ import (
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secret"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 var loginArgs struct {
 	apiKey      string
 	accessToken string
+	secrets     string
 }
 
 var Cmd = &cobra.Command{
@@ -46,22 +50,33 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	AddFlags(Cmd)
+}
+
+// AddFlags adds the flags of the login to the command, e.g. kite profile add.
+func AddFlags(cmd *cobra.Command) {
 
-	Cmd.Flags().StringVar(
+	cmd.Flags().StringVar(
 		&loginArgs.apiKey,
 		"api-key",
 		"",
 		"Access API key/token generated from "+constants.APIKeyURL+"\nUse this option to overwrite the existing API key.",
 	)
-	Cmd.Flags().StringVar(
+	cmd.Flags().StringVar(
 		&loginArgs.accessToken,
 		"access-token",
 		"",
 		"GitHub Personal Access Token generated from "+constants.AccessTokenURL+"\nUse this option to overwrite the existing Access Token.",
 	)
+	cmd.Flags().StringVar(
+		&loginArgs.secrets,
+		"secrets",
+		"",
+		"Storage of the API key and the Access Token: "+secret.Plain+" in the config file, "+secret.Keyring+" or an encrypted "+secret.File+".\nThe current storage is kept when not set.",
+	)
 }
 
-// loginHandler handles the login flow into kite.
+// loginHandler handles the login flow into kite, the credentials and the team are saved to the profile selected by --profile.
 func loginHandler(cmd *cobra.Command, args []string) error {
 
 	var user string
@@ -73,9 +88,21 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 	// If no config file can be located
 	// Or if the config file has errors
 	// Or if this is the first time a user is trying to login
-	// A new configuration struct is initialized on login
+	// A new configuration struct is initialized on login, the preferences of a config file with an invalid API key are kept
 	if err != nil {
-		cfg = new(config.Config)
+		cfg, err = config.Read()
+
+		if err != nil {
+			cfg = config.New()
+		}
+	}
+
+	if loginArgs.secrets != "" {
+		if _, err = secret.New(loginArgs.secrets, ""); err != nil {
+			return err
+		}
+
+		cfg.Secrets = loginArgs.secrets
 	}
 
 	// Set PagerDuty API key from cmd args or ask interactively
@@ -102,6 +129,15 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// The GitHub token is optional, only the SOPs need it
+	if cfg.AccessToken != "" {
+		err = utils.ValidateGitHubToken(strings.TrimSpace(cfg.AccessToken))
+
+		if err != nil {
+			return fmt.Errorf("%v\nLeave the GitHub token empty to use kite without the private SOPs", err)
+		}
+	}
+
 	// Save the config
 	err = config.Save(cfg)
 
@@ -167,23 +203,30 @@ func generateNewKey(cfg *config.Config) (err error) {
 	return nil
 }
 
-// generateNewKey prompts the user to create a new API key and saves it to the config file.
+// generateNewAccessToken prompts the user for an optional GitHub token, the current token is kept when none is entered.
 func generateNewAccessToken(cfg *config.Config) (err error) {
-	//prompts the user to generate an API Key
-	fmt.Println("\nIn order to view SOP it is mandatory to provide an GitHub Access Token.\nThe recommended way is to generate a token via: " + constants.AccessTokenURL)
+	//prompts the user to generate an access token
+	fmt.Println("\nThe SOPs of the private GitHub repositories, e.g. openshift/ops-sop, need a GitHub Access Token.\nThe recommended way is to generate a token via: " + constants.AccessTokenURL)
 
 	//Takes standard input from the user and stores it in a variable
 	reader := bufio.NewReader(os.Stdin)
 
-	fmt.Print("GitHub Access Token: ")
+	if cfg.AccessToken != "" {
+		fmt.Print("GitHub Access Token (press Enter to keep the current one): ")
+	} else {
+		fmt.Print("GitHub Access Token (optional, press Enter to skip): ")
+	}
 
-	cfg.AccessToken, err = reader.ReadString('\n')
-	cfg.AccessToken = strings.TrimSuffix(cfg.AccessToken, "\n")
+	token, err := reader.ReadString('\n')
 
-	if err != nil {
+	if err != nil && !errors.Is(err, io.EOF) {
 		return err
 	}
 
+	if token = strings.TrimSpace(token); token != "" {
+		cfg.AccessToken = token
+	}
+
 	return nil
 }
 
@@ -18,13 +18,20 @@ import (
 	"strings"
 
 	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/session"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/oncall"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
+var options struct {
+	scheduleIDs string
+	timeZone    string
+}
+
 var Cmd = &cobra.Command{
 	Use:   "oncall",
 	Short: "oncall to the PagerDuty CLI",
@@ -33,6 +40,25 @@ var Cmd = &cobra.Command{
 	RunE:  oncallHandler,
 }
 
+func init() {
+
+	// Schedules of the on-call layers
+	Cmd.Flags().StringVar(
+		&options.scheduleIDs,
+		"schedule-ids",
+		"",
+		"PagerDuty schedules of the on-call layers separated by commas, defaults to the schedule_ids setting of 'kite config'",
+	)
+
+	// Time zone of the dates
+	Cmd.Flags().StringVar(
+		&options.timeZone,
+		"time-zone",
+		"",
+		"Time zone of the dates, e.g. --time-zone=Europe/Paris, defaults to the time_zone setting of 'kite config'",
+	)
+}
+
 // oncallHandler is the main handler for kite oncall.
 func oncallHandler(cmd *cobra.Command, args []string) (err error) {
 	var (
@@ -44,10 +70,25 @@ func oncallHandler(cmd *cobra.Command, args []string) (err error) {
 		tui            ui.TUI
 	)
 
+	// The settings of the config file and the environment are overridden by the flags
+	settings, err := config.LoadSettings(cmd.Flags())
+
+	if err != nil {
+		return err
+	}
+
+	utils.TimeZone = settings.TimeZone
+
 	// Initialize TUI
 	tui.Init()
 	utils.InfoLogger.Print("Initialized terminal UI")
 
+	err = session.Apply(cmd, args, &tui)
+
+	if err != nil {
+		return err
+	}
+
 	// Establish a secure connection with the PagerDuty API
 	utils.InfoLogger.Print("Connecting to PagerDuty API")
 	client, err := client.NewClient().Connect()
@@ -69,11 +110,16 @@ func oncallHandler(cmd *cobra.Command, args []string) (err error) {
 
 	// Fetch oncall data from Platform-SRE team
 	utils.InfoLogger.Print("GET: fetching on-call data of current user team")
-	onCallLayers, err = pdcli.TeamSREOnCall(client)
+	onCallLayers, err = pdcli.TeamSREOnCall(client, settings.ScheduleIDs)
 	if err != nil {
 		return err
 	}
 
+	// The current on-call layer is the third one, see initOnCallFirstPage
+	if len(onCallLayers) < 3 {
+		return fmt.Errorf("no current on-call layer found in the schedules %s, check the schedule_ids setting of 'kite config'", strings.Join(settings.ScheduleIDs, ","))
+	}
+
 	for _, v := range onCallLayers[2].Users {
 		if strings.Contains(v.OncallRole, "Primary") {
 			primary = v.Name
@@ -0,0 +1,147 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package profile
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/login"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manages the profiles of the PagerDuty accounts and teams.",
+	Long: `Manages the profiles, each with its own PagerDuty API key, GitHub token, team and terminal, e.g. for a staging account or another rotation.
+The profile is selected with the --profile flag of any command, the KITE_PROFILE environment variable, or 'kite profile use'.
+The top-level credentials of the config file are the default profile.`,
+	Args: cobra.NoArgs,
+	RunE: listHandler,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the profiles, the active profile is marked with a star.",
+	Args:  cobra.NoArgs,
+	RunE:  listHandler,
+}
+
+var useCmd = &cobra.Command{
+	Use:     "use <name>",
+	Short:   "Uses the profile when no --profile flag is given.",
+	Example: "kite profile use staging",
+	Args:    cobra.ExactArgs(1),
+	RunE:    useHandler,
+}
+
+var addCmd = &cobra.Command{
+	Use:     "add <name>",
+	Short:   "Adds a profile, its credentials and team are asked as with 'kite login'.",
+	Example: "kite profile add staging --api-key <api-key>",
+	Args:    cobra.ExactArgs(1),
+	RunE:    addHandler,
+}
+
+var deleteCmd = &cobra.Command{
+	Use:     "delete <name>",
+	Short:   "Deletes the profile and its secrets.",
+	Example: "kite profile delete staging",
+	Args:    cobra.ExactArgs(1),
+	RunE:    deleteHandler,
+}
+
+func init() {
+	login.AddFlags(addCmd)
+
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(useCmd)
+	Cmd.AddCommand(addCmd)
+	Cmd.AddCommand(deleteCmd)
+}
+
+func listHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Read()
+
+	if err != nil {
+		return fmt.Errorf("cannot read the configuration, run the 'kite login' command: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tNAME\tTEAM\tTERMINAL")
+
+	for _, name := range cfg.ProfileNames() {
+		profile, _ := cfg.GetProfile(name)
+		active := ""
+
+		if name == cfg.ActiveProfile() {
+			active = "*"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", active, name, profile.Team, profile.Terminal)
+	}
+
+	return w.Flush()
+}
+
+func useHandler(cmd *cobra.Command, args []string) error {
+	err := config.UseProfile(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The %s profile is used.\n", args[0])
+
+	return nil
+}
+
+func addHandler(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if cfg, err := config.Read(); err == nil {
+		if _, ok := cfg.GetProfile(name); ok {
+			return fmt.Errorf("the profile '%s' already exists, log into it with 'kite login --profile %s'", name, name)
+		}
+	}
+
+	// The login saves the credentials and the team to the selected profile
+	config.SetProfile(name)
+
+	err := login.Cmd.RunE(cmd, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The %s profile is added, use it with --profile %s or 'kite profile use %s'.\n", name, name, name)
+
+	return nil
+}
+
+func deleteHandler(cmd *cobra.Command, args []string) error {
+	err := config.DeleteProfile(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The %s profile is deleted.\n", args[0])
+
+	return nil
+}
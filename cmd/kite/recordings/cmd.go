@@ -0,0 +1,117 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package recordings
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/recording"
+	"github.com/spf13/cobra"
+)
+
+var (
+	speed     float64
+	idleLimit time.Duration
+	wait      bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "recordings",
+	Short: "Lists the asciicast recordings of the terminal slides.",
+	Long: `Lists the recordings of the terminal slides, started and stopped with the toggle recording key.
+The recordings are asciicast v2 files, they can be replayed with 'kite recordings play' or asciinema.`,
+	Args: cobra.NoArgs,
+	RunE: listHandler,
+}
+
+var playCmd = &cobra.Command{
+	Use:     "play <name>",
+	Short:   "Replays a recording in the terminal.",
+	Example: "kite recordings play Q1ABCDEF_my-cluster_20240102-150405 --speed 2",
+	Args:    cobra.ExactArgs(1),
+	RunE:    playHandler,
+}
+
+func init() {
+	playCmd.Flags().Float64Var(
+		&speed,
+		"speed",
+		1,
+		"Speed of the replay, e.g. --speed=2 replays twice as fast",
+	)
+
+	playCmd.Flags().DurationVar(
+		&idleLimit,
+		"idle-limit",
+		2*time.Second,
+		"Longest pause of the replay, 0 keeps the pauses of the recording, e.g. --idle-limit=1s",
+	)
+
+	playCmd.Flags().BoolVar(
+		&wait,
+		"wait",
+		false,
+		"Wait for Enter once the replay ends",
+	)
+
+	Cmd.AddCommand(playCmd)
+}
+
+func listHandler(cmd *cobra.Command, args []string) error {
+	recordings, err := recording.List()
+
+	if err != nil {
+		return err
+	}
+
+	if len(recordings) == 0 {
+		fmt.Println("No recordings found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTARTED\tDURATION\tTITLE")
+
+	for _, rec := range recordings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			rec.Name,
+			rec.Start().Format(time.RFC822),
+			rec.Duration.Round(time.Second),
+			rec.Header.Title,
+		)
+	}
+
+	return w.Flush()
+}
+
+func playHandler(cmd *cobra.Command, args []string) error {
+	err := recording.Play(args[0], os.Stdout, speed, idleLimit)
+
+	if err != nil {
+		return err
+	}
+
+	if wait {
+		fmt.Print("\r\n[Replay ended, press Enter to close]")
+		_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+	}
+
+	return nil
+}
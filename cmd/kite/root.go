@@ -16,11 +16,22 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
+
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/attach"
+	configcmd "github.com/openshift/pagerduty-short-circuiter/cmd/kite/config"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/login"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/oncall"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/profile"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/recordings"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/secrets"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/server"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/session"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/sop"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/teams"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/terminal"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 
 	"github.com/spf13/cobra"
 )
@@ -30,7 +41,37 @@ var rootCmd = &cobra.Command{
 	Use:           "kite",
 	Short:         "An all-in-one incident response tool called kite.",
 	Long:          `It can be used reduce the time taken, from the time, SRE receives a PD alert to the time where troubleshooting on the cluster actually begins. `,
+	Example:       "kite --restore [name]",
+	Args:          cobra.MaximumNArgs(1),
+	RunE:          rootHandler,
 	SilenceErrors: true,
+
+	// The profile is selected before the commands read the config
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		config.SetProfile(profileName)
+	},
+}
+
+// profileName is the profile of the --profile flag
+var profileName string
+
+// rootHandler restores a saved session, e.g. kite --restore work
+// Without the restore flag the help is displayed.
+func rootHandler(cmd *cobra.Command, args []string) error {
+	if !cmd.Flags().Changed(session.RestoreFlag) {
+		if len(args) > 0 {
+			return fmt.Errorf("unknown command \"%s\" for \"%s\"", args[0], cmd.CommandPath())
+		}
+		return cmd.Help()
+	}
+
+	name, _ := cmd.Flags().GetString(session.RestoreFlag)
+
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	return session.Restore(cmd, name)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -45,6 +86,21 @@ func init() {
 	rootCmd.AddCommand(oncall.Cmd)
 	rootCmd.AddCommand(teams.Cmd)
 	rootCmd.AddCommand(terminal.Cmd)
+	rootCmd.AddCommand(server.Cmd)
+	rootCmd.AddCommand(attach.Cmd)
+	rootCmd.AddCommand(recordings.Cmd)
+	rootCmd.AddCommand(sop.Cmd)
+	rootCmd.AddCommand(secrets.Cmd)
+	rootCmd.AddCommand(profile.Cmd)
+	rootCmd.AddCommand(configcmd.Cmd)
+	session.AddFlags(rootCmd)
+
+	rootCmd.PersistentFlags().StringVar(
+		&profileName,
+		config.ProfileFlag,
+		"",
+		"Profile of the credentials, team and terminal, e.g. --profile staging.\nThe "+config.ProfileEnv+" environment variable, or else the profile of 'kite profile use', is used when not set.",
+	)
 
 	//Do not provide the default completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
@@ -0,0 +1,92 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secret"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Lists where the API key, the GitHub token and the SOP host tokens are read from.",
+	Long: `Lists where the PagerDuty API key, the GitHub token and the tokens of the SOP hosts are read from, without printing them:
+the KITE_API_KEY and KITE_GH_TOKEN environment variables, the commands of secret_commands in the config file,
+or the secret storage, i.e. the config file as plain text, the keyring or the encrypted secret file.`,
+	Args: cobra.NoArgs,
+	RunE: listHandler,
+}
+
+var migrateCmd = &cobra.Command{
+	Use:       "migrate <" + secret.Plain + "|" + secret.Keyring + "|" + secret.File + ">",
+	Short:     "Moves the API keys and the GitHub tokens of all the profiles, and the SOP host tokens, to another secret storage.",
+	Example:   "kite secrets migrate " + secret.Keyring,
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{secret.Plain, secret.Keyring, secret.File},
+	RunE:      migrateHandler,
+}
+
+func init() {
+	Cmd.AddCommand(migrateCmd)
+}
+
+func listHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Read()
+
+	if err != nil {
+		return fmt.Errorf("cannot read the configuration, run the 'kite login' command: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SECRET\tSOURCE\tERROR")
+
+	for _, name := range append(config.SecretNames(), cfg.SOPHostSecretNames()...) {
+		message := ""
+
+		if err := cfg.SecretError(name); err != nil {
+			message = err.Error()
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, cfg.SecretSource(name), message)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if cfg.HasPlainSecrets() {
+		fmt.Printf("\nSecrets are left as plain text in the config file, run 'kite secrets migrate %s' to move them to the %s secret storage.\n", cfg.Secrets, cfg.Secrets)
+	}
+
+	return nil
+}
+
+func migrateHandler(cmd *cobra.Command, args []string) error {
+	err := config.MoveSecrets(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The secrets are stored in the %s secret storage.\n", args[0])
+
+	return nil
+}
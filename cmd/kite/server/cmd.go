@@ -0,0 +1,52 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server
+
+import (
+	"os/signal"
+	"syscall"
+
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/session"
+	kiteserver "github.com/openshift/pagerduty-short-circuiter/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+var name string
+
+var Cmd = &cobra.Command{
+	Use:   "server [-- command [args]]",
+	Short: "Runs kite in the background, its slides are displayed in the terminal with 'kite attach'.",
+	Long: `Runs the given kite command, alerts by default, without a terminal.
+The slides and their programs keep running when the terminal attached with 'kite attach' is detached or closed.`,
+	Example: "kite server --name work -- alerts --assigned-to=team",
+	RunE:    serverHandler,
+}
+
+func init() {
+	Cmd.Flags().StringVar(
+		&name,
+		"name",
+		kiteserver.DefaultName,
+		"Name of the server, e.g. --name=work",
+	)
+}
+
+func serverHandler(cmd *cobra.Command, args []string) error {
+	// The server outlives the terminal it was started from
+	signal.Ignore(syscall.SIGHUP)
+
+	return session.Serve(cmd.Root(), name, args)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package session
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/server"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// RestoreFlag is the name of the flag restoring a saved session.
+const RestoreFlag = "restore"
+
+// DefaultArgs are the kite arguments used to restore a session saved without any.
+var DefaultArgs = []string{"alerts"}
+
+var restore string
+
+// serverName is the name of the kite server the TUI is shared by, empty when kite runs in the terminal
+var serverName string
+
+// AddFlags adds the session flags to the given command and its subcommands.
+func AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&restore,
+		RestoreFlag,
+		"",
+		"Restore the slides of the saved session with the given name, e.g. --restore=work",
+	)
+	cmd.PersistentFlags().Lookup(RestoreFlag).NoOptDefVal = ui.DefaultSession
+}
+
+// Apply sets the session the TUI is saved to, and the session it is restored from if the restore flag is set.
+func Apply(cmd *cobra.Command, args []string, tui *ui.TUI) error {
+	tui.SessionName = ui.DefaultSession
+	tui.SessionArgs = commandArgs(cmd, args)
+
+	if serverName != "" {
+		s, err := server.New(serverName)
+
+		if err != nil {
+			return err
+		}
+
+		err = s.Listen()
+
+		if err != nil {
+			return err
+		}
+
+		tui.Server = s
+	}
+
+	if !cmd.Flags().Changed(RestoreFlag) {
+		return nil
+	}
+
+	session, err := Load(restore)
+
+	if err != nil {
+		return err
+	}
+
+	tui.SessionName = session.Name
+	tui.RestoredSession = session
+
+	return nil
+}
+
+// Load reads the saved session with the given name, the error lists the saved sessions if it doesn't exist.
+func Load(name string) (*ui.Session, error) {
+	session, err := ui.LoadSession(name)
+
+	if err == nil {
+		return session, nil
+	}
+
+	if names, _ := ui.ListSessions(); len(names) > 0 {
+		return nil, fmt.Errorf("%v\navailable sessions: %s", err, strings.Join(names, ", "))
+	}
+
+	return nil, err
+}
+
+// Restore runs the kite command the session with the given name was saved from and restores its slides.
+func Restore(root *cobra.Command, name string) error {
+	session, err := Load(name)
+
+	if err != nil {
+		return err
+	}
+
+	args := session.Args
+
+	if len(args) == 0 {
+		args = DefaultArgs
+	}
+
+	err = Run(root, args, fmt.Sprintf("--%s=%s", RestoreFlag, name))
+
+	if err != nil {
+		return fmt.Errorf("session '%s' cannot be restored: %v", name, err)
+	}
+
+	return nil
+}
+
+// Serve runs the kite command with the given arguments in the kite server with the given name.
+func Serve(root *cobra.Command, name string, args []string) error {
+	if len(args) == 0 {
+		args = DefaultArgs
+	}
+
+	serverName = name
+
+	return Run(root, args)
+}
+
+// Run runs the kite command with the given arguments, followed by the extra flags.
+// The profile of the arguments replaces the one kite was started with, e.g. kite server -- alerts --profile staging.
+func Run(root *cobra.Command, args []string, flags ...string) error {
+	cmd, cmdArgs, err := root.Find(args)
+
+	if err != nil {
+		return err
+	}
+
+	if cmd == root || cmd.RunE == nil {
+		return fmt.Errorf("cannot run 'kite %s'", strings.Join(args, " "))
+	}
+
+	err = cmd.ParseFlags(append(cmdArgs, flags...))
+
+	if err != nil {
+		return err
+	}
+
+	// The root command selected the profile before the arguments were parsed
+	if flag := cmd.Flags().Lookup(config.ProfileFlag); flag != nil && flag.Changed {
+		config.SetProfile(flag.Value.String())
+	}
+
+	return cmd.RunE(cmd, cmd.Flags().Args())
+}
+
+// commandArgs returns the arguments running the given command again, without the session flags.
+func commandArgs(cmd *cobra.Command, args []string) []string {
+	cmdArgs := strings.Fields(cmd.CommandPath())[1:]
+
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name != RestoreFlag {
+			cmdArgs = append(cmdArgs, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+		}
+	})
+
+	return append(cmdArgs, args...)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sop
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
+	"github.com/spf13/cobra"
+)
+
+// Number of matches listed by kite sop search
+const searchLimit = 20
+
+var repository string
+
+var Cmd = &cobra.Command{
+	Use:   "sop",
+	Short: "Manages the local copy of the SOP repository.",
+	Long: `The SOPs are read from the local copy of the SOP repository when it has been synchronized with 'kite sop sync',
+they are fetched from GitHub otherwise.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var syncCmd = &cobra.Command{
+	Use:     "sync",
+	Short:   "Clones or updates the local copy of the SOP repository.",
+	Example: "kite sop sync --repo openshift/ops-sop",
+	Args:    cobra.NoArgs,
+	RunE:    syncHandler,
+}
+
+var searchCmd = &cobra.Command{
+	Use:     "search <words>",
+	Short:   "Lists the SOPs containing all the given words.",
+	Example: "kite sop search etcd quorum",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    searchHandler,
+}
+
+func init() {
+	Cmd.PersistentFlags().StringVar(
+		&repository,
+		"repo",
+		sop.DefaultOwner+"/"+sop.DefaultRepo,
+		"GitHub repository of the SOPs, e.g. --repo=openshift/ops-sop",
+	)
+
+	Cmd.AddCommand(syncCmd)
+	Cmd.AddCommand(searchCmd)
+}
+
+// ownerAndRepo splits the repo flag into the owner and the repository name.
+func ownerAndRepo() (string, string, error) {
+	owner, repo, found := strings.Cut(repository, "/")
+
+	if !found || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("invalid repository '%s', expected owner/name", repository)
+	}
+
+	return owner, repo, nil
+}
+
+func syncHandler(cmd *cobra.Command, args []string) error {
+	owner, repo, err := ownerAndRepo()
+
+	if err != nil {
+		return err
+	}
+
+	// The GitHub token grants access to the private repositories, the public ones are synchronized without it
+	var token string
+
+	if cfg, err := config.Read(); err == nil {
+		token = cfg.AccessToken
+	}
+
+	fmt.Printf("Synchronizing %s/%s...\n", owner, repo)
+
+	err = sop.Sync(owner, repo, token, os.Stdout)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("SOPs successfully synchronized.")
+
+	return nil
+}
+
+func searchHandler(cmd *cobra.Command, args []string) error {
+	owner, repo, err := ownerAndRepo()
+
+	if err != nil {
+		return err
+	}
+
+	matches, err := sop.Search(owner, repo, strings.Join(args, " "), searchLimit)
+
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		fmt.Println("No SOPs found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOP\tLINE\tTEXT")
+
+	for _, match := range matches {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", match.Path, match.Line, match.Text)
+	}
+
+	return w.Flush()
+}
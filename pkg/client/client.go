@@ -18,6 +18,7 @@ package client
 
 import (
 	"fmt"
+	"os"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
@@ -51,20 +52,16 @@ func (pd *PDClient) Connect() (client *PDClient, err error) {
 
 	if pd.cfg == nil {
 
-		// Load the configuration file
+		// Load the configuration file, only the PagerDuty API key is needed
 		pd.cfg, err = config.Load()
-		if err != nil {
-			err = fmt.Errorf("configuration file not found, run the 'kite login' command")
-			return nil, err
-		}
 
-		if pd.cfg == nil {
+		if os.IsNotExist(err) {
 			err = fmt.Errorf("not logged in, run the 'kite login' command")
 			return nil, err
 		}
 
 		if err != nil {
-			err = fmt.Errorf("invalid API key, run the 'kite login' command")
+			err = fmt.Errorf("%v, run the 'kite login' command", err)
 			return nil, err
 		}
 
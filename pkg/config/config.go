@@ -17,7 +17,6 @@ limitations under the License.
 package config
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -25,9 +24,8 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/google/go-github/v50/github"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
-	"golang.org/x/oauth2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secret"
 )
 
 // Configuration struct to store user configuration.
@@ -37,6 +35,108 @@ type Config struct {
 	TeamID      string `json:"team_id,omitempty"`
 	Team        string `json:"team,omitempty"`
 	Terminal    string `json:"terminal,omitempty"`
+
+	// Keymap holds the user overrides for the TUI key bindings.
+	Keymap *KeymapConfig `json:"keymap,omitempty"`
+
+	// Theme is the name of a built-in theme or of a theme file in the themes config directory.
+	Theme string `json:"theme,omitempty"`
+
+	// Mouse enables the mouse support in the TUI, it is enabled when not set.
+	Mouse *bool `json:"mouse,omitempty"`
+
+	// OSC52 enables copying the text selected in the terminals to the clipboard of the terminal kite runs in,
+	// with the OSC 52 escape sequence. It is enabled when not set.
+	OSC52 *bool `json:"osc52,omitempty"`
+
+	// LaunchTemplates customize the programs run in the slides opened while an alert is selected.
+	LaunchTemplates []LaunchTemplate `json:"launch_templates,omitempty"`
+
+	// Launchers are the programs which can be run in new slides besides the shell and ocm-container.
+	Launchers []Launcher `json:"launchers,omitempty"`
+
+	// SOPHosts configure the servers the SOPs are fetched from besides github.com and gitlab.com.
+	SOPHosts []SOPHost `json:"sop_hosts,omitempty"`
+
+	// Settings of the commands, overridden by their KITE_* environment variable and their flag, see kite config view.
+	// RefreshInterval is a duration, e.g. "2m", and TimeZone a location, e.g. "Europe/Paris".
+	Columns                 string   `json:"columns,omitempty"`
+	AssignedTo              string   `json:"assigned_to,omitempty"`
+	RefreshInterval         string   `json:"refresh_interval,omitempty"`
+	TimeZone                string   `json:"time_zone,omitempty"`
+	IncidentsLimit          uint     `json:"incidents_limit,omitempty"`
+	TriggeredIncidentsLimit uint     `json:"triggered_incidents_limit,omitempty"`
+	ScheduleIDs             []string `json:"schedule_ids,omitempty"`
+
+	// Secrets is where the API key and the GitHub token are stored: "plain" in this file when not set, "keyring" or "file".
+	Secrets string `json:"secrets,omitempty"`
+
+	// SecretCommands print the secrets instead of storing them, by secret name, e.g. {"api_key": "pass show pagerduty"}
+	SecretCommands map[string]string `json:"secret_commands,omitempty"`
+
+	// Profile is the profile used when no --profile flag is given, the top-level credentials, team and terminal when not set.
+	Profile string `json:"profile,omitempty"`
+
+	// Profiles are the named credentials, teams and terminals, e.g. of a staging PagerDuty account or of another rotation.
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+
+	// The profile applied to the configuration, and the top-level fields it replaced
+	active string
+	base   Profile
+
+	// Where the secrets were read from, the errors reading them, and their plain-text value in this file
+	secretSources map[string]string
+	secretErrors  map[string]error
+	fileSecrets   map[string]string
+	plainSecrets  bool
+}
+
+// SOPHost is a server the SOPs are fetched from, e.g. a GitHub Enterprise or a GitLab instance, or an internal documentation site.
+type SOPHost struct {
+	// Host of the SOP URLs, with the port if it isn't the default one, e.g. "gitlab.example.com"
+	Host string `json:"host"`
+
+	// Type is the kind of server: "github", "gitlab" or "http" for the HTML and markdown pages served as is
+	Type string `json:"type"`
+
+	// Token authenticates the requests to the server, the GitHub token of kite login is used for github.com when not set
+	Token string `json:"token,omitempty"`
+}
+
+// LaunchTemplate customizes the program run in a kind of slide for the alerts matching it.
+// The arguments and the environment values are Go templates executed with the selected alert, e.g. "{{.ClusterID}}".
+type LaunchTemplate struct {
+	// Slide is the kind of slide customized: "cluster", "ocm-container" or "shell"
+	Slide string `json:"slide"`
+
+	// Alert is a regular expression matched against the alert name, the template matches all the alerts when empty
+	Alert string `json:"alert,omitempty"`
+
+	// Command replaces the program of the slide, the arguments are only replaced when set
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// Launcher is a program run in a new slide, with its key or from the launcher list.
+// The arguments and the environment values are Go templates executed with the selected alert, e.g. "{{.ClusterID}}".
+type Launcher struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+
+	// Key runs the launcher, e.g. "Alt+1", the launcher is only in the launcher list when not set
+	Key string `json:"key,omitempty"`
+}
+
+// KeymapConfig stores the TUI key bindings configured by the user.
+// Bindings maps an action name to a key such as "Ctrl+N", "Esc" or "r".
+// When Prefix is set, the terminal multiplexer bindings are only active
+// after the prefix key has been pressed.
+type KeymapConfig struct {
+	Prefix   string            `json:"prefix,omitempty"`
+	Bindings map[string]string `json:"bindings,omitempty"`
 }
 
 // Find returns the pdcli configuration filepath.
@@ -60,6 +160,17 @@ func Find() (string, error) {
 	return configPath, nil
 }
 
+// Dir returns the kite configuration directory, i.e the directory containing the config file.
+func Dir() (string, error) {
+	configFile, err := Find()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Dir(configFile), nil
+}
+
 // Save saves the given configuration data to the config file.
 // It creates a new directory to store the config file.
 func Save(cfg *Config) error {
@@ -77,12 +188,8 @@ func Save(cfg *Config) error {
 		return err
 	}
 
-	// Check if the GitHub key is valid
-	cfg.AccessToken, err = validateGHToken(cfg.AccessToken)
-
-	if err != nil {
-		return err
-	}
+	// The GitHub token is optional, it is validated by kite login and when the SOPs are fetched
+	cfg.AccessToken = strings.TrimSpace(cfg.AccessToken)
 
 	if cfg.TeamID != "" {
 
@@ -94,9 +201,31 @@ func Save(cfg *Config) error {
 		}
 	}
 
+	// The secrets are only written to the file with the plain storage
+	saved, err := cfg.storeSecrets(filepath.Dir(file))
+
+	if err != nil {
+		return err
+	}
+
+	// The credentials and the team of a named profile are saved to the profile
+	saved.unapplyProfile()
+
+	err = writeFile(file, saved)
+
+	if err != nil {
+		return err
+	}
+
+	cfg.plainSecrets = false
+
+	return nil
+}
+
+// writeFile writes the configuration to the config file as is, it creates the config directory.
+func writeFile(file string, cfg *Config) error {
 	// Create a new directory to store config file
-	dir := filepath.Dir(file)
-	err = os.MkdirAll(dir, os.FileMode(0755))
+	err := os.MkdirAll(filepath.Dir(file), os.FileMode(0755))
 
 	if err != nil {
 		return err
@@ -117,8 +246,42 @@ func Save(cfg *Config) error {
 	return nil
 }
 
-// Load loads the configuration file and parses it.
+// Load loads the configuration file, parses it and validates the format of the PagerDuty API key and the settings.
+// No request is made: the GitHub token is optional, it is only needed by the SOPs and validated when they are fetched.
+// The plain-text secrets left in the file aren't moved to the secret storage, kite secrets migrate moves them.
 func Load() (config *Config, err error) {
+	config, err = Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.hasProfile(config.ActiveProfile()) {
+		return nil, fmt.Errorf("unknown profile '%s', add it with the 'kite profile add %s' command", config.ActiveProfile(), config.ActiveProfile())
+	}
+
+	if err = config.SecretError(secret.APIKey); err != nil {
+		return nil, err
+	}
+
+	_, err = validateKey(config.ApiKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Read loads the configuration file and parses it without validating the credentials.
+// It is used to look up user preferences which do not need API access.
+// The credentials, the team and the terminal are the ones of the selected profile.
+// The secrets are read from the environment, their command or the secret storage, the errors are returned by SecretError.
+func Read() (config *Config, err error) {
 	//Locate the config filepath
 	configFile, err := Find()
 
@@ -126,6 +289,20 @@ func Load() (config *Config, err error) {
 		return nil, err
 	}
 
+	config, err = readFile(configFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.applyProfile(config.selectProfile())
+	config.resolveSecrets(filepath.Dir(configFile))
+
+	return config, nil
+}
+
+// readFile parses the config file as is, without applying the profile nor reading the secrets.
+func readFile(configFile string) (config *Config, err error) {
 	_, err = os.Stat(configFile)
 
 	if os.IsNotExist(err) {
@@ -153,18 +330,6 @@ func Load() (config *Config, err error) {
 		return nil, err
 	}
 
-	_, err = validateKey(config.ApiKey)
-
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = validateGHToken(config.AccessToken)
-
-	if err != nil {
-		return nil, err
-	}
-
 	return config, nil
 }
 
@@ -194,21 +359,3 @@ func validateTeamID(teamID string) (string, error) {
 
 	return teamID, nil
 }
-
-// Validate Access of GH Token to ops-sop
-func validateGHToken(ghToken string) (string, error) {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: ghToken},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-
-	client := github.NewClient(tc)
-
-	_, _, err := client.Repositories.Get(ctx, "openshift", "ops-sop")
-
-	if err != nil {
-		return "", err
-	}
-	return ghToken, nil
-}
@@ -0,0 +1,275 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secret"
+)
+
+const (
+	// DefaultProfile is the name of the top-level credentials, team and terminal of the config file
+	DefaultProfile = "default"
+
+	// ProfileEnv selects the profile when no --profile flag is given
+	ProfileEnv = "KITE_PROFILE"
+
+	// ProfileFlag is the name of the flag selecting the profile of a kite command
+	ProfileFlag = "profile"
+)
+
+// Profile holds the credentials, the team and the terminal of a PagerDuty account or rotation.
+type Profile struct {
+	ApiKey         string            `json:"api_key,omitempty"`
+	AccessToken    string            `json:"gh_token,omitempty"`
+	TeamID         string            `json:"team_id,omitempty"`
+	Team           string            `json:"team,omitempty"`
+	Terminal       string            `json:"terminal,omitempty"`
+	SecretCommands map[string]string `json:"secret_commands,omitempty"`
+}
+
+// The profile selected by the --profile flag
+var selectedProfile string
+
+// SetProfile selects the profile read by Read and Load, and saved by Save, the profile of the config file when empty.
+func SetProfile(name string) {
+	selectedProfile = name
+}
+
+// New returns an empty configuration of the selected profile, e.g. for the first login.
+func New() *Config {
+	config := &Config{}
+	config.applyProfile(config.selectProfile())
+
+	return config
+}
+
+// selectProfile returns the name of the profile selected by the --profile flag, the environment or the config file
+func (c *Config) selectProfile() string {
+	if selectedProfile != "" {
+		return selectedProfile
+	}
+
+	if name := os.Getenv(ProfileEnv); name != "" {
+		return name
+	}
+
+	return c.Profile
+}
+
+// ActiveProfile returns the name of the profile the configuration was read with.
+func (c *Config) ActiveProfile() string {
+	if c.active == "" {
+		return DefaultProfile
+	}
+
+	return c.active
+}
+
+// ProfileNames returns the names of the profiles, the default profile first.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles)+1)
+
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return append([]string{DefaultProfile}, names...)
+}
+
+// GetProfile returns the profile with the given name, as it is saved.
+func (c *Config) GetProfile(name string) (Profile, bool) {
+	switch {
+	case name == c.ActiveProfile():
+		return c.fields(), true
+	case name == DefaultProfile:
+		return c.base, true
+	}
+
+	profile, ok := c.Profiles[name]
+
+	if !ok || profile == nil {
+		return Profile{}, false
+	}
+
+	return *profile, true
+}
+
+// hasProfile reports whether the profile is in the config file
+func (c *Config) hasProfile(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+
+	_, ok := c.Profiles[name]
+
+	return ok
+}
+
+// fields returns the top-level fields of the configuration replaced by the profiles
+func (c *Config) fields() Profile {
+	return Profile{
+		ApiKey:         c.ApiKey,
+		AccessToken:    c.AccessToken,
+		TeamID:         c.TeamID,
+		Team:           c.Team,
+		Terminal:       c.Terminal,
+		SecretCommands: c.SecretCommands,
+	}
+}
+
+// setFields replaces the top-level fields of the configuration by the profile ones
+func (c *Config) setFields(profile Profile) {
+	c.ApiKey = profile.ApiKey
+	c.AccessToken = profile.AccessToken
+	c.TeamID = profile.TeamID
+	c.Team = profile.Team
+	c.Terminal = profile.Terminal
+	c.SecretCommands = profile.SecretCommands
+}
+
+// applyProfile replaces the top-level fields by the ones of the named profile, they are restored by unapplyProfile
+func (c *Config) applyProfile(name string) {
+	if name == "" || name == DefaultProfile {
+		return
+	}
+
+	c.active = name
+	c.base = c.fields()
+
+	profile := Profile{}
+
+	if p := c.Profiles[name]; p != nil {
+		profile = *p
+	}
+
+	c.setFields(profile)
+}
+
+// unapplyProfile moves the top-level fields back to the applied profile, and restores the default ones.
+// A profile which isn't in the config file is only added when it has fields, e.g. after kite login.
+func (c *Config) unapplyProfile() {
+	if c.active == "" {
+		return
+	}
+
+	profile := c.fields()
+	profiles := make(map[string]*Profile, len(c.Profiles)+1)
+
+	for name, p := range c.Profiles {
+		profiles[name] = p
+	}
+
+	if _, ok := profiles[c.active]; ok || profile.ApiKey != "" || profile.TeamID != "" || profile.Terminal != "" || len(profile.SecretCommands) > 0 {
+		profiles[c.active] = &profile
+	}
+
+	c.Profiles = profiles
+	c.setFields(c.base)
+	c.active = ""
+}
+
+// profileSecrets returns the secret fields of the named profile of the config file as is, by secret name
+func (c *Config) profileSecrets(name string) (map[string]*string, map[string]string) {
+	if name == DefaultProfile {
+		return c.secretFields(), c.SecretCommands
+	}
+
+	profile := c.Profiles[name]
+
+	if profile == nil {
+		return nil, nil
+	}
+
+	return map[string]*string{
+		secret.APIKey:      &profile.ApiKey,
+		secret.GitHubToken: &profile.AccessToken,
+	}, profile.SecretCommands
+}
+
+// storedName returns the name of the secret of the profile in the secret storage, e.g. "staging/api_key".
+// The secrets shared by the profiles are stored once, by their name.
+func storedName(profile string, name string) string {
+	if profile == "" || profile == DefaultProfile || sharedSecret(name) {
+		return name
+	}
+
+	return profile + "/" + name
+}
+
+// UseProfile makes the profile with the given name the one used when no --profile flag is given.
+func UseProfile(name string) error {
+	file, err := Find()
+
+	if err != nil {
+		return err
+	}
+
+	cfg, err := readFile(file)
+
+	if err != nil {
+		return err
+	}
+
+	if !cfg.hasProfile(name) {
+		return fmt.Errorf("unknown profile '%s', add it with the 'kite profile add %s' command", name, name)
+	}
+
+	cfg.Profile = name
+
+	if name == DefaultProfile {
+		cfg.Profile = ""
+	}
+
+	return writeFile(file, cfg)
+}
+
+// DeleteProfile removes the profile with the given name and its secrets, the default profile cannot be deleted.
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the %s profile cannot be deleted", DefaultProfile)
+	}
+
+	file, err := Find()
+
+	if err != nil {
+		return err
+	}
+
+	cfg, err := readFile(file)
+
+	if err != nil {
+		return err
+	}
+
+	if !cfg.hasProfile(name) {
+		return fmt.Errorf("unknown profile '%s'", name)
+	}
+
+	store, err := secret.New(cfg.storage(), filepath.Dir(file))
+
+	if err != nil {
+		return err
+	}
+
+	if store != nil {
+		for _, secretName := range SecretNames() {
+			if err := store.Delete(storedName(name, secretName)); err != nil {
+				return fmt.Errorf("cannot delete the %s of the profile from the %s secret storage: %v", secretName, cfg.storage(), err)
+			}
+			setCachedSecret(secretKey(cfg.storage(), filepath.Dir(file), storedName(name, secretName)), "")
+		}
+	}
+
+	delete(cfg.Profiles, name)
+
+	if cfg.Profile == name {
+		cfg.Profile = ""
+	}
+
+	return writeFile(file, cfg)
+}
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secret"
+)
+
+// Sources of the secrets, besides the storages
+const (
+	SecretFromEnv     = "environment"
+	SecretFromCommand = "command"
+	SecretNotSet      = "not set"
+)
+
+// sopHostSecret prefixes the names of the tokens of the SOP hosts, e.g. "sop_host/gitlab.example.com"
+const sopHostSecret = "sop_host/"
+
+var (
+	// The secrets read from the stores and the commands, the keyring and the secret file are only read once per run
+	secretCache     = map[string]string{}
+	secretCacheLock sync.Mutex
+)
+
+// secretFields returns the fields of the configuration holding the secrets, by name
+func (c *Config) secretFields() map[string]*string {
+	fields := map[string]*string{
+		secret.APIKey:      &c.ApiKey,
+		secret.GitHubToken: &c.AccessToken,
+	}
+
+	for i := range c.SOPHosts {
+		fields[SOPHostSecret(c.SOPHosts[i].Host)] = &c.SOPHosts[i].Token
+	}
+
+	return fields
+}
+
+// SecretNames returns the names of the secrets of the profiles, sorted.
+func SecretNames() []string {
+	names := make([]string, 0, 2)
+
+	for name := range (&Config{}).secretFields() {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// SOPHostSecret returns the name of the token of the SOP host, e.g. "sop_host/gitlab.example.com".
+func SOPHostSecret(host string) string {
+	return sopHostSecret + host
+}
+
+// SOPHostSecretNames returns the names of the tokens of the SOP hosts of the configuration, in the config file order.
+// The SOP hosts are shared by the profiles, so are their tokens.
+func (c *Config) SOPHostSecretNames() []string {
+	names := make([]string, 0, len(c.SOPHosts))
+
+	for _, host := range c.SOPHosts {
+		names = append(names, SOPHostSecret(host.Host))
+	}
+
+	return names
+}
+
+// sharedSecret reports whether the secret is shared by the profiles, e.g. the token of a SOP host
+func sharedSecret(name string) bool {
+	return strings.HasPrefix(name, sopHostSecret)
+}
+
+// SecretSource returns where the secret with the given name was read from: the environment, a command, a storage or nowhere.
+func (c *Config) SecretSource(name string) string {
+	if source, ok := c.secretSources[name]; ok {
+		return source
+	}
+
+	return SecretNotSet
+}
+
+// HasPlainSecrets reports whether secrets are left as plain text in the config file while another secret storage is configured.
+func (c *Config) HasPlainSecrets() bool {
+	return c.plainSecrets
+}
+
+// SecretError returns the error reading the secret with the given name, nil if it was read or isn't set.
+func (c *Config) SecretError(name string) error {
+	return c.secretErrors[name]
+}
+
+// storage returns the storage of the secrets, plain-text in the config file by default
+func (c *Config) storage() string {
+	if c.Secrets == "" {
+		return secret.Plain
+	}
+
+	return c.Secrets
+}
+
+// external reports whether the secret is given by an environment variable or a command, it is never saved then
+func (c *Config) external(name string) bool {
+	return os.Getenv(secret.EnvVar(name)) != "" || c.SecretCommands[name] != ""
+}
+
+// secretKey returns the key of the secret in the cache, the secret files of the config directories are different stores
+func secretKey(storage string, dir string, name string) string {
+	return storage + ":" + dir + ":" + name
+}
+
+// cachedSecret returns the secret cached under the key, it is read and cached if it isn't yet
+func cachedSecret(key string, read func() (string, error)) (string, error) {
+	secretCacheLock.Lock()
+	defer secretCacheLock.Unlock()
+
+	if value, ok := secretCache[key]; ok {
+		return value, nil
+	}
+
+	value, err := read()
+
+	if err != nil {
+		return "", err
+	}
+
+	secretCache[key] = value
+
+	return value, nil
+}
+
+// setCachedSecret replaces the cached secret, an empty value removes it
+func setCachedSecret(key string, value string) {
+	secretCacheLock.Lock()
+	defer secretCacheLock.Unlock()
+
+	if value == "" {
+		delete(secretCache, key)
+		return
+	}
+
+	secretCache[key] = value
+}
+
+// resolveSecrets reads the secrets of the configuration from the first of: their environment variable, their command,
+// the config file and the secret storage. The errors are kept by secret, so that only the features needing a secret fail without it.
+func (c *Config) resolveSecrets(dir string) {
+	c.secretSources = map[string]string{}
+	c.secretErrors = map[string]error{}
+	c.fileSecrets = map[string]string{}
+
+	store, storeErr := secret.New(c.storage(), dir)
+
+	for name, field := range c.secretFields() {
+		c.fileSecrets[name] = *field
+
+		if value := os.Getenv(secret.EnvVar(name)); value != "" {
+			*field = value
+			c.secretSources[name] = fmt.Sprintf("%s (%s)", SecretFromEnv, secret.EnvVar(name))
+			continue
+		}
+
+		if command := c.SecretCommands[name]; command != "" {
+			value, err := cachedSecret(SecretFromCommand+":"+command, func() (string, error) {
+				return secret.FromCommand(command)
+			})
+
+			*field = value
+			c.secretSources[name] = SecretFromCommand
+			c.secretErrors[name] = err
+			continue
+		}
+
+		// The plain-text secrets of the config file are moved to the storage when it is saved, or migrated
+		if *field != "" {
+			c.secretSources[name] = secret.Plain
+			c.plainSecrets = c.plainSecrets || c.storage() != secret.Plain
+			continue
+		}
+
+		if storeErr != nil {
+			c.secretErrors[name] = storeErr
+			continue
+		}
+
+		if store == nil {
+			continue
+		}
+
+		stored := storedName(c.active, name)
+
+		value, err := cachedSecret(secretKey(c.storage(), dir, stored), func() (string, error) {
+			return store.Get(stored)
+		})
+
+		if errors.Is(err, secret.ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			c.secretErrors[name] = fmt.Errorf("cannot read the %s from the %s secret storage: %v", name, c.storage(), err)
+			continue
+		}
+
+		*field = value
+		c.secretSources[name] = c.storage()
+	}
+}
+
+// storeSecrets saves the secrets to the secret storage and returns the configuration written to the config file,
+// whose secrets are removed unless they are stored as plain text. The secrets given by the environment or a command aren't saved.
+func (c *Config) storeSecrets(dir string) (*Config, error) {
+	store, err := secret.New(c.storage(), dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	saved := *c
+
+	// The tokens are removed from the saved SOP hosts only
+	saved.SOPHosts = append([]SOPHost(nil), c.SOPHosts...)
+
+	if c.secretSources == nil {
+		c.secretSources = map[string]string{}
+	}
+
+	for name, field := range saved.secretFields() {
+		value := *field
+		stored := storedName(c.active, name)
+		*field = ""
+
+		switch {
+		case c.external(name):
+			// The plain-text secret of the file is kept as is
+			if store == nil {
+				*field = c.fileSecrets[name]
+			}
+		case store == nil:
+			*field = value
+			if value != "" {
+				c.secretSources[name] = secret.Plain
+			}
+		case value == "":
+			if err := store.Delete(stored); err != nil {
+				return nil, fmt.Errorf("cannot delete the %s from the %s secret storage: %v", name, c.storage(), err)
+			}
+			setCachedSecret(secretKey(c.storage(), dir, stored), "")
+			delete(c.secretSources, name)
+		default:
+			if err := store.Set(stored, value); err != nil {
+				return nil, fmt.Errorf("cannot save the %s to the %s secret storage: %v", name, c.storage(), err)
+			}
+			setCachedSecret(secretKey(c.storage(), dir, stored), value)
+			c.secretSources[name] = c.storage()
+		}
+	}
+
+	return &saved, nil
+}
+
+// MoveSecrets moves the secrets of all the profiles to the given storage, and saves the storage to the config file.
+// The secrets are deleted from the previous storage once they are saved to the new one,
+// the secrets given by a command aren't stored and are left as is. The secrets left as plain text in the config file
+// are moved too, they are the ones read before the stored ones, even when the storage doesn't change.
+func MoveSecrets(storage string) error {
+	file, err := Find()
+
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(file)
+	cfg, err := readFile(file)
+
+	if err != nil {
+		return err
+	}
+
+	if storage == secret.Plain {
+		storage = ""
+	}
+
+	from, err := secret.New(cfg.storage(), dir)
+
+	if err != nil {
+		return err
+	}
+
+	to, err := secret.New(storage, dir)
+
+	if err != nil {
+		return err
+	}
+
+	var moved []string
+
+	for _, profile := range cfg.ProfileNames() {
+		fields, commands := cfg.profileSecrets(profile)
+
+		for name, field := range fields {
+			if commands[name] != "" {
+				continue
+			}
+
+			stored := storedName(profile, name)
+			value := *field
+
+			if from != nil && cfg.Secrets != storage {
+				fromValue, err := from.Get(stored)
+
+				switch {
+				case errors.Is(err, secret.ErrNotFound):
+				case err != nil:
+					return fmt.Errorf("cannot read the %s of the %s profile from the %s secret storage: %v", name, profile, cfg.storage(), err)
+				default:
+					moved = append(moved, stored)
+
+					if value == "" {
+						value = fromValue
+					}
+				}
+			}
+
+			// The secrets which aren't set in the config file aren't stored empty
+			if value == "" {
+				continue
+			}
+
+			if to == nil {
+				*field = value
+				continue
+			}
+
+			if err := to.Set(stored, value); err != nil {
+				return fmt.Errorf("cannot save the %s of the %s profile to the %s secret storage: %v", name, profile, storage, err)
+			}
+
+			*field = ""
+		}
+	}
+
+	previousStorage := cfg.storage()
+	cfg.Secrets = storage
+
+	if err := writeFile(file, cfg); err != nil {
+		return err
+	}
+
+	secretCacheLock.Lock()
+	secretCache = map[string]string{}
+	secretCacheLock.Unlock()
+
+	for _, stored := range moved {
+		if err := from.Delete(stored); err != nil {
+			return fmt.Errorf("the secrets are saved to the %s secret storage, but cannot be deleted from the %s storage: %v", cfg.storage(), previousStorage, err)
+		}
+	}
+
+	return nil
+}
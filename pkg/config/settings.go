@@ -0,0 +1,426 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secret"
+	"github.com/spf13/pflag"
+)
+
+// Sources of the settings, the secret settings have the sources of the secrets
+const (
+	SettingFromDefault = "default"
+	SettingFromFile    = "config file"
+	SettingFromEnv     = "environment"
+	SettingFromFlag    = "flag"
+	SettingNotSet      = "not set"
+)
+
+// The shortest refresh interval, the PagerDuty API is rate limited
+const minRefreshInterval = 10 * time.Second
+
+// Setting is a value of the configuration, layered from the lowest to the highest precedence:
+// its default, the config file, its KITE_* environment variable and the flag of the commands having it.
+type Setting struct {
+	// Key of the setting in the config file, e.g. "refresh_interval"
+	Key         string
+	Description string
+	Default     string
+
+	// Flag overrides the setting in the commands which have it, e.g. "refresh-interval"
+	Flag string
+
+	// Secret settings are kept in the secret storage and aren't printed
+	Secret bool
+
+	validate func(value string) error
+	get      func(c *Config) string
+	set      func(c *Config, value string)
+}
+
+// Env returns the environment variable overriding the setting, e.g. KITE_REFRESH_INTERVAL.
+// The secret settings are overridden by the variable of their secret, e.g. KITE_API_KEY, when the secrets are read.
+func (s Setting) Env() string {
+	if s.Secret {
+		return secret.EnvVar(s.Key)
+	}
+
+	return "KITE_" + strings.ToUpper(s.Key)
+}
+
+// Settings are the values of the settings, resolved from their layers.
+type Settings struct {
+	TeamID                  string
+	Team                    string
+	Columns                 string
+	AssignedTo              string
+	RefreshInterval         time.Duration
+	TimeZone                *time.Location
+	Terminal                string
+	IncidentsLimit          uint
+	TriggeredIncidentsLimit uint
+	ScheduleIDs             []string
+}
+
+var settings = []Setting{
+	{
+		Key:         secret.APIKey,
+		Description: "PagerDuty API key",
+		Secret:      true,
+		validate:    func(value string) error { _, err := validateKey(value); return err },
+		get:         func(c *Config) string { return c.ApiKey },
+		set:         func(c *Config, value string) { c.ApiKey = value },
+	},
+	{
+		Key:         secret.GitHubToken,
+		Description: "GitHub token of the SOPs",
+		Secret:      true,
+		validate:    func(value string) error { return nil },
+		get:         func(c *Config) string { return c.AccessToken },
+		set:         func(c *Config, value string) { c.AccessToken = value },
+	},
+	{
+		Key:         "team_id",
+		Description: "ID of the PagerDuty team of the team alerts",
+		validate:    optional(func(value string) error { _, err := validateTeamID(value); return err }),
+		get:         func(c *Config) string { return c.TeamID },
+		set:         func(c *Config, value string) { c.TeamID = value },
+	},
+	{
+		Key:         "team",
+		Description: "Name of the PagerDuty team",
+		validate:    func(value string) error { return nil },
+		get:         func(c *Config) string { return c.Team },
+		set:         func(c *Config, value string) { c.Team = value },
+	},
+	{
+		Key:         "columns",
+		Description: "Columns of the alerts table, separated by commas",
+		Default:     constants.AlertColumns,
+		Flag:        "columns",
+		validate:    validateColumns,
+		get:         func(c *Config) string { return c.Columns },
+		set:         func(c *Config, value string) { c.Columns = value },
+	},
+	{
+		Key:         "assigned_to",
+		Description: "Alerts listed by default: self, team or silentTest",
+		Default:     "self",
+		Flag:        "assigned-to",
+		validate:    validateAssignment,
+		get:         func(c *Config) string { return c.AssignedTo },
+		set:         func(c *Config, value string) { c.AssignedTo = value },
+	},
+	{
+		Key:         "refresh_interval",
+		Description: "Interval of the automatic refresh of the alerts, e.g. 2m, 0 disables it",
+		Default:     "0s",
+		Flag:        "refresh-interval",
+		validate:    validateRefreshInterval,
+		get:         func(c *Config) string { return c.RefreshInterval },
+		set:         func(c *Config, value string) { c.RefreshInterval = value },
+	},
+	{
+		Key:         "time_zone",
+		Description: "Time zone of the dates, e.g. Europe/Paris or Local",
+		Default:     "UTC",
+		Flag:        "time-zone",
+		validate:    func(value string) error { _, err := time.LoadLocation(value); return err },
+		get:         func(c *Config) string { return c.TimeZone },
+		set:         func(c *Config, value string) { c.TimeZone = value },
+	},
+	{
+		Key:         "terminal",
+		Description: "Terminal emulator of the cluster logins, see kite terminal",
+		validate:    func(value string) error { return nil },
+		get:         func(c *Config) string { return c.Terminal },
+		set:         func(c *Config, value string) { c.Terminal = value },
+	},
+	{
+		Key:         "incidents_limit",
+		Description: "Number of incidents fetched for the alerts",
+		Default:     strconv.Itoa(constants.IncidentsLimit),
+		Flag:        "limit",
+		validate:    validateLimit,
+		get:         func(c *Config) string { return formatUint(c.IncidentsLimit) },
+		set:         func(c *Config, value string) { c.IncidentsLimit = parseUint(value) },
+	},
+	{
+		Key:         "triggered_incidents_limit",
+		Description: "Number of incidents fetched for the triggered incidents view",
+		Default:     strconv.Itoa(constants.TrigerredIncidentsLimit),
+		validate:    validateLimit,
+		get:         func(c *Config) string { return formatUint(c.TriggeredIncidentsLimit) },
+		set:         func(c *Config, value string) { c.TriggeredIncidentsLimit = parseUint(value) },
+	},
+	{
+		Key:         "schedule_ids",
+		Description: "PagerDuty schedules of the on-call layers, separated by commas",
+		Default: strings.Join([]string{
+			constants.PrimaryScheduleID,
+			constants.SecondaryScheduleID,
+			constants.OncallManager,
+			constants.OncallIDWeekend,
+			constants.InvestigatorID,
+		}, ","),
+		Flag:     "schedule-ids",
+		validate: validateScheduleIDs,
+		get:      func(c *Config) string { return strings.Join(c.ScheduleIDs, ",") },
+		set:      func(c *Config, value string) { c.ScheduleIDs = splitList(value) },
+	},
+}
+
+// AllSettings returns the settings, in the order of kite config view.
+func AllSettings() []Setting {
+	return append([]Setting{}, settings...)
+}
+
+// FindSetting returns the setting with the given key.
+func FindSetting(key string) (Setting, error) {
+	for _, s := range settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+
+	return Setting{}, fmt.Errorf("unknown setting '%s', see 'kite config view'", key)
+}
+
+// Value returns the value of the setting and where it comes from: its environment variable, the config file or its default.
+func (c *Config) Value(key string) (value string, source string, err error) {
+	s, err := FindSetting(key)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	// The secrets are resolved from the environment, their command and the storage by Read
+	if s.Secret {
+		return s.get(c), c.SecretSource(key), nil
+	}
+
+	if value := os.Getenv(s.Env()); value != "" {
+		return value, fmt.Sprintf("%s (%s)", SettingFromEnv, s.Env()), nil
+	}
+
+	if value := s.get(c); value != "" {
+		return value, SettingFromFile, nil
+	}
+
+	if s.Default == "" {
+		return "", SettingNotSet, nil
+	}
+
+	return s.Default, SettingFromDefault, nil
+}
+
+// Set validates the value of the setting and sets it in the configuration, an empty value restores the default.
+func (c *Config) Set(key string, value string) error {
+	s, err := FindSetting(key)
+
+	if err != nil {
+		return err
+	}
+
+	value = strings.TrimSpace(value)
+
+	if value != "" {
+		if err := s.validate(value); err != nil {
+			return fmt.Errorf("invalid %s '%s': %v", key, value, err)
+		}
+	}
+
+	s.set(c, value)
+
+	return nil
+}
+
+// Validate checks the settings of the config file against their schema.
+func (c *Config) Validate() error {
+	for _, s := range settings {
+		if value := s.get(c); value != "" && !s.Secret {
+			if err := s.validate(value); err != nil {
+				return fmt.Errorf("invalid %s '%s' in the config file: %v, fix it with 'kite config set %s <value>'", s.Key, value, err, s.Key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadSettings resolves the settings from their default, the config file, their environment variable and the given flags,
+// only the flags which are set override the settings. The config file is optional, e.g. before the first login.
+func LoadSettings(flags *pflag.FlagSet) (*Settings, error) {
+	cfg, err := Read()
+
+	if os.IsNotExist(err) {
+		cfg = New()
+	} else if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+
+	for _, s := range settings {
+		if s.Secret {
+			continue
+		}
+
+		value, source, _ := cfg.Value(s.Key)
+
+		if f := lookupFlag(flags, s.Flag); f != nil && f.Changed {
+			value, source = f.Value.String(), fmt.Sprintf("%s (--%s)", SettingFromFlag, s.Flag)
+		}
+
+		if value != "" {
+			if err := s.validate(value); err != nil {
+				return nil, fmt.Errorf("invalid %s '%s' from the %s: %v", s.Key, value, source, err)
+			}
+		}
+
+		values[s.Key] = value
+	}
+
+	// The values are valid
+	refreshInterval, _ := time.ParseDuration(values["refresh_interval"])
+	timeZone, _ := time.LoadLocation(values["time_zone"])
+
+	return &Settings{
+		TeamID:                  values["team_id"],
+		Team:                    values["team"],
+		Columns:                 values["columns"],
+		AssignedTo:              values["assigned_to"],
+		RefreshInterval:         refreshInterval,
+		TimeZone:                timeZone,
+		Terminal:                values["terminal"],
+		IncidentsLimit:          parseUint(values["incidents_limit"]),
+		TriggeredIncidentsLimit: parseUint(values["triggered_incidents_limit"]),
+		ScheduleIDs:             splitList(values["schedule_ids"]),
+	}, nil
+}
+
+// lookupFlag returns the flag with the given name, nil if the command doesn't have it
+func lookupFlag(flags *pflag.FlagSet, name string) *pflag.Flag {
+	if flags == nil || name == "" {
+		return nil
+	}
+
+	return flags.Lookup(name)
+}
+
+// optional accepts the empty values of the settings without default
+func optional(validate func(value string) error) func(value string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+
+		return validate(value)
+	}
+}
+
+// splitList returns the values of a list separated by commas, without blanks
+func splitList(value string) []string {
+	var values []string
+
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func formatUint(value uint) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+func parseUint(value string) uint {
+	n, _ := strconv.ParseUint(value, 10, 0)
+	return uint(n)
+}
+
+func validateColumns(value string) error {
+	columns := splitList(value)
+
+	if len(columns) == 0 {
+		return fmt.Errorf("no column")
+	}
+
+	for _, column := range columns {
+		if !contains(splitList(constants.AlertColumns), column) {
+			return fmt.Errorf("unknown column '%s', expected %s", column, constants.AlertColumns)
+		}
+	}
+
+	return nil
+}
+
+func validateAssignment(value string) error {
+	if !contains([]string{"self", "team", "silentTest"}, value) {
+		return fmt.Errorf("expected self, team or silentTest")
+	}
+
+	return nil
+}
+
+func validateRefreshInterval(value string) error {
+	interval, err := time.ParseDuration(value)
+
+	if err != nil {
+		return err
+	}
+
+	if interval < 0 || (interval > 0 && interval < minRefreshInterval) {
+		return fmt.Errorf("expected 0 or at least %s", minRefreshInterval)
+	}
+
+	return nil
+}
+
+func validateLimit(value string) error {
+	limit, err := strconv.ParseUint(value, 10, 0)
+
+	if err != nil || limit == 0 || limit > constants.MaxIncidentsLimit {
+		return fmt.Errorf("expected a number from 1 to %d", constants.MaxIncidentsLimit)
+	}
+
+	return nil
+}
+
+func validateScheduleIDs(value string) error {
+	ids := splitList(value)
+
+	if len(ids) == 0 {
+		return fmt.Errorf("no schedule ID")
+	}
+
+	for _, id := range ids {
+		if match, _ := regexp.MatchString(constants.ScheduleIdRegex, id); !match {
+			return fmt.Errorf("invalid schedule ID '%s'", id)
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
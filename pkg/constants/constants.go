@@ -29,6 +29,7 @@ const (
 	APIKeyRegex     = "^[a-z|A-Z0-9+_-]{20}$"
 	IncidentIdRegex = "^[A-Z0-9]{7,14}$"
 	TeamIdRegex     = "^[A-Z0-9]{7}$"
+	ScheduleIdRegex = "^[A-Z0-9]{7}$"
 
 	// Sample API key for testing
 	SampleKey = "y_NbAkKc66ryYTWUXYEu"
@@ -36,6 +37,10 @@ const (
 	// Set limit to number of incidents fetched from pagerduty
 	IncidentsLimit          = 10
 	TrigerredIncidentsLimit = 25
+	MaxIncidentsLimit       = 100
+
+	// Columns of the alerts table, all displayed by default
+	AlertColumns = "incident.id,alert.id,cluster.name,alert,cluster.id,status,severity,age"
 
 	// PagerDuty IDs
 	TeamID     = "PASPK4G"
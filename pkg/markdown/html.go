@@ -0,0 +1,326 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	spaces      = regexp.MustCompile(`\s+`)
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+	destination = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+	special     = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, "#", `\#`)
+)
+
+// FromHTML converts the HTML page to markdown, so that it is rendered like the markdown SOPs.
+// The structure of the page is kept: headings, paragraphs, lists, links, code and tables. The scripts and the styles are dropped.
+func FromHTML(source string) (string, error) {
+	root, err := html.Parse(strings.NewReader(source))
+
+	if err != nil {
+		return "", fmt.Errorf("cannot parse the HTML page: %v", err)
+	}
+
+	// The content of the page is in its body, the head only holds metadata
+	body := root
+	if found := findElement(root, atom.Body); found != nil {
+		body = found
+	}
+
+	return strings.TrimSpace(blankLines.ReplaceAllString(convertChildren(body), "\n\n")) + "\n", nil
+}
+
+// FromText converts the plain text document to markdown, the line breaks are kept and nothing is interpreted as markdown.
+func FromText(source string) string {
+	var paragraphs []string
+
+	for _, paragraph := range regexp.MustCompile(`\n\s*\n`).Split(strings.ReplaceAll(source, "\r\n", "\n"), -1) {
+		var lines []string
+
+		for _, line := range strings.Split(strings.TrimSpace(paragraph), "\n") {
+			lines = append(lines, special.Replace(strings.TrimSpace(line)))
+		}
+
+		if paragraph := strings.Join(lines, "\\\n"); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+
+	return strings.Join(paragraphs, "\n\n") + "\n"
+}
+
+// findElement returns the first element of the given type in the tree, depth first
+func findElement(node *html.Node, element atom.Atom) *html.Node {
+	if node.Type == html.ElementNode && node.DataAtom == element {
+		return node
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, element); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// attribute returns the value of the attribute of the element, empty if it isn't set
+func attribute(node *html.Node, name string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+// convertChildren returns the markdown of the children of the node
+func convertChildren(node *html.Node) string {
+	var out strings.Builder
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		out.WriteString(convert(child))
+	}
+
+	return out.String()
+}
+
+// block returns the markdown of a block element, separated from the blocks around it by blank lines
+func block(content string) string {
+	content = strings.TrimSpace(content)
+
+	if content == "" {
+		return ""
+	}
+
+	return "\n\n" + content + "\n\n"
+}
+
+// inline returns the inline markdown of the children of the node, on a single line
+func inline(node *html.Node) string {
+	return strings.TrimSpace(spaces.ReplaceAllString(convertChildren(node), " "))
+}
+
+// convert returns the markdown of the node and its children
+func convert(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return special.Replace(spaces.ReplaceAllString(node.Data, " "))
+	case html.ElementNode:
+	default:
+		return convertChildren(node)
+	}
+
+	switch node.DataAtom {
+	case atom.Head, atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg, atom.Button, atom.Form:
+		return ""
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(node.Data[1] - '0')
+		return block(strings.Repeat("#", level) + " " + inline(node))
+	case atom.Br:
+		return "\\\n"
+	case atom.Hr:
+		return block("---")
+	case atom.Strong, atom.B:
+		return wrap(inline(node), "**")
+	case atom.Em, atom.I:
+		return wrap(inline(node), "*")
+	case atom.Del, atom.S, atom.Strike:
+		return wrap(inline(node), "~~")
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		return codeSpan(node)
+	case atom.A:
+		text := inline(node)
+		href := attribute(node, "href")
+
+		if href == "" || text == "" {
+			return text
+		}
+
+		return fmt.Sprintf("[%s](%s)", text, destination.Replace(href))
+	case atom.Img:
+		if src := attribute(node, "src"); src != "" {
+			return fmt.Sprintf("![%s](%s)", special.Replace(attribute(node, "alt")), destination.Replace(src))
+		}
+		return ""
+	case atom.Pre:
+		return codeBlock(node)
+	case atom.Ul, atom.Ol:
+		return list(node)
+	case atom.Blockquote:
+		content := strings.TrimSpace(blankLines.ReplaceAllString(convertChildren(node), "\n\n"))
+		return block("> " + strings.ReplaceAll(content, "\n", "\n> "))
+	case atom.Table:
+		return table(node)
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Footer, atom.Aside, atom.Nav,
+		atom.Figure, atom.Figcaption, atom.Details, atom.Summary, atom.Dl, atom.Dt, atom.Dd, atom.Li:
+		return block(convertChildren(node))
+	}
+
+	return convertChildren(node)
+}
+
+// wrap surrounds the text with the emphasis delimiter, empty text isn't emphasized
+func wrap(text string, delimiter string) string {
+	if text == "" {
+		return ""
+	}
+
+	return delimiter + text + delimiter
+}
+
+// codeSpan returns the inline code, delimited by more backticks than it contains
+func codeSpan(node *html.Node) string {
+	code := spaces.ReplaceAllString(textContent(node), " ")
+
+	if strings.TrimSpace(code) == "" {
+		return ""
+	}
+
+	delimiter := "`"
+	for strings.Contains(code, delimiter) {
+		delimiter += "`"
+	}
+
+	return delimiter + code + delimiter
+}
+
+// codeBlock returns the fenced code block of the preformatted text, with the language of its class if it has one
+func codeBlock(node *html.Node) string {
+	code := strings.Trim(textContent(node), "\n")
+	language := ""
+
+	for _, element := range []*html.Node{node, findElement(node, atom.Code)} {
+		if element == nil {
+			continue
+		}
+
+		for _, class := range strings.Fields(attribute(element, "class")) {
+			if name, ok := strings.CutPrefix(class, "language-"); ok {
+				language = name
+			}
+		}
+	}
+
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+
+	return block(fence + language + "\n" + code + "\n" + fence)
+}
+
+// list returns the items of the list, the content of an item is indented under its marker
+func list(node *html.Node) string {
+	var items []string
+	number := 1
+
+	if start := attribute(node, "start"); start != "" {
+		fmt.Sscanf(start, "%d", &number)
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if node.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		// The nested lists follow the text of the item directly, so that the list stays tight
+		var item strings.Builder
+
+		for grandchild := child.FirstChild; grandchild != nil; grandchild = grandchild.NextSibling {
+			if grandchild.Type == html.ElementNode && (grandchild.DataAtom == atom.Ul || grandchild.DataAtom == atom.Ol) {
+				item.WriteString("\n" + strings.TrimSpace(list(grandchild)) + "\n")
+				continue
+			}
+			item.WriteString(convert(grandchild))
+		}
+
+		content := strings.TrimSpace(blankLines.ReplaceAllString(item.String(), "\n\n"))
+		indent := "\n" + strings.Repeat(" ", len(marker))
+		items = append(items, marker+strings.ReplaceAll(content, "\n", indent))
+	}
+
+	return block(strings.Join(items, "\n"))
+}
+
+// table returns the rows of the table, the first one being the header
+func table(node *html.Node) string {
+	var rows [][]string
+	columns := 0
+
+	var collect func(*html.Node)
+	collect = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+
+			if child.DataAtom != atom.Tr {
+				collect(child)
+				continue
+			}
+
+			var cells []string
+
+			for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+					cells = append(cells, strings.ReplaceAll(inline(cell), "|", `\|`))
+				}
+			}
+
+			rows = append(rows, cells)
+			columns = max(columns, len(cells))
+		}
+	}
+	collect(node)
+
+	if len(rows) == 0 || columns == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+
+		out.WriteString("| " + strings.Join(row, " | ") + " |\n")
+
+		if i == 0 {
+			out.WriteString(strings.Repeat("|---", columns) + "|\n")
+		}
+	}
+
+	return block(out.String())
+}
+
+// textContent returns the text of the node and its children, as is
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	var out strings.Builder
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.DataAtom == atom.Br {
+			out.WriteString("\n")
+			continue
+		}
+		out.WriteString(textContent(child))
+	}
+
+	return out.String()
+}
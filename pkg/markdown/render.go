@@ -0,0 +1,711 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/tview"
+)
+
+// Style defines the colors of the rendered markdown, the default color of the view is used for the unset colors.
+type Style struct {
+	Text           tcell.Color
+	Heading        tcell.Color
+	Link           tcell.Color
+	Code           tcell.Color
+	CodeBackground tcell.Color
+	Quote          tcell.Color
+	Note           tcell.Color
+	Warning        tcell.Color
+}
+
+// Document is a markdown document rendered for a tview.TextView with dynamic colors and regions.
+type Document struct {
+	// Text is the rendered document
+	Text string
+
+	// Links holds the destinations of the links and images, as written in the document.
+	// The link N is displayed in the region "N".
+	Links []string
+
+	// Anchors maps the anchors of the headings, e.g. "cluster-down" for "## Cluster Down", to their regions.
+	Anchors map[string]string
+
+	// CodeBlocks holds the code blocks, the code block N is displayed in the region "cN".
+	CodeBlocks []CodeBlock
+}
+
+// CodeBlock is a code block of a document.
+type CodeBlock struct {
+	// Language is the language of a fenced code block, e.g. "sh", empty when not set
+	Language string
+	Code     string
+}
+
+// Width of the horizontal rules
+const ruleWidth = 40
+
+// Bullets of the unordered lists, by nesting level
+var bullets = []string{"•", "◦", "▪"}
+
+// Titles of the admonitions, e.g. "> [!NOTE]" or "> **Note:**"
+var admonitions = map[string]string{
+	"NOTE":      "Note",
+	"TIP":       "Tip",
+	"IMPORTANT": "Important",
+	"WARNING":   "Warning",
+	"CAUTION":   "Caution",
+}
+
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlTagPattern     = regexp.MustCompile(`<[^>]*>`)
+	htmlBreakPattern   = regexp.MustCompile(`(?i)^<br\s*/?>$`)
+	anchorPattern      = regexp.MustCompile(`[^\p{L}\p{N}\s_-]`)
+)
+
+// textStyle is the style of the rendered text, the unset colors are inherited from the enclosing style.
+type textStyle struct {
+	fg    tcell.Color
+	bg    tcell.Color
+	attrs string
+}
+
+// prefix is written at the start of every line of a block, e.g. the bar of a quote.
+// The first line of a list item gets the marker of the item instead of the indentation.
+type prefix struct {
+	first string
+	rest  string
+	color tcell.Color
+	used  bool
+}
+
+type renderer struct {
+	style      Style
+	out        strings.Builder
+	links      []string
+	anchors    map[string]string
+	codeBlocks []CodeBlock
+	styles     []textStyle
+
+	prefixes  []*prefix
+	lineStart bool
+
+	// The text is escaped once the whole run between two tags is known,
+	// so that brackets split across text nodes are never read as tags
+	pending strings.Builder
+
+	// Width of the text written on the current line
+	width int
+
+	// The line breaks are replaced by spaces, e.g. in the table cells
+	singleLine bool
+}
+
+// Render parses the given markdown and renders it with the given style.
+func Render(source string, style Style) *Document {
+	p := parser.NewWithExtensions(parser.CommonExtensions | parser.OrderedListStart)
+	doc := p.Parse([]byte(source))
+
+	r := newRenderer(style, textStyle{fg: style.Text})
+	r.blocks(doc)
+	r.flush()
+
+	return &Document{Text: strings.TrimRight(r.out.String(), "\n"), Links: r.links, Anchors: r.anchors, CodeBlocks: r.codeBlocks}
+}
+
+func newRenderer(style Style, base textStyle) *renderer {
+	return &renderer{style: style, styles: []textStyle{base}, anchors: make(map[string]string), lineStart: true}
+}
+
+// Anchor returns the anchor of the heading with the given text, the way GitHub generates it:
+// the text is lower-cased, the punctuation is removed and the spaces are replaced by dashes.
+func Anchor(heading string) string {
+	anchor := anchorPattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(heading)), "")
+	return strings.ReplaceAll(anchor, " ", "-")
+}
+
+// colorTag returns the color as written in a style tag.
+func colorTag(color tcell.Color) string {
+	if color == tcell.ColorDefault {
+		return "-"
+	}
+	return color.String()
+}
+
+// tag returns the style tag switching to the given style.
+func (s textStyle) tag() string {
+	tag := fmt.Sprintf("[%s:%s:-]", colorTag(s.fg), colorTag(s.bg))
+
+	if s.attrs != "" {
+		tag += "[::" + s.attrs + "]"
+	}
+
+	return tag
+}
+
+func (r *renderer) current() textStyle {
+	return r.styles[len(r.styles)-1]
+}
+
+// push switches to the given style, combined with the current one.
+func (r *renderer) push(style textStyle) {
+	current := r.current()
+
+	if style.fg == tcell.ColorDefault {
+		style.fg = current.fg
+	}
+
+	if style.bg == tcell.ColorDefault {
+		style.bg = current.bg
+	}
+
+	style.attrs = current.attrs + style.attrs
+	r.styles = append(r.styles, style)
+	r.raw(style.tag())
+}
+
+// pop switches back to the style used before the last push.
+func (r *renderer) pop() {
+	r.styles = r.styles[:len(r.styles)-1]
+	r.raw(r.current().tag())
+}
+
+// flush writes the pending text.
+func (r *renderer) flush() {
+	if r.pending.Len() > 0 {
+		r.out.WriteString(tview.Escape(r.pending.String()))
+		r.pending.Reset()
+	}
+}
+
+// raw writes the given tags.
+func (r *renderer) raw(tags string) {
+	r.flush()
+	r.out.WriteString(tags)
+}
+
+// text writes the given text, the prefixes are written first at the start of a line.
+func (r *renderer) text(text string) {
+	if text == "" {
+		return
+	}
+
+	r.startLine()
+	r.pending.WriteString(text)
+	r.width += runewidth.StringWidth(text)
+}
+
+// startLine writes the prefixes of the blocks at the start of a line.
+func (r *renderer) startLine() {
+	if !r.lineStart {
+		return
+	}
+
+	r.lineStart = false
+
+	for _, p := range r.prefixes {
+		text := p.rest
+		if !p.used {
+			text = p.first
+			p.used = true
+		}
+
+		r.raw(textStyle{fg: p.color}.tag())
+		r.text(text)
+		r.raw(r.current().tag())
+	}
+}
+
+// newline ends the current line.
+func (r *renderer) newline() {
+	if r.singleLine {
+		r.text(" ")
+		return
+	}
+
+	r.startLine()
+	r.raw("\n")
+	r.lineStart = true
+	r.width = 0
+}
+
+func (r *renderer) pushPrefix(first string, rest string, color tcell.Color) {
+	r.prefixes = append(r.prefixes, &prefix{first: first, rest: rest, color: color})
+}
+
+func (r *renderer) popPrefix() {
+	r.prefixes = r.prefixes[:len(r.prefixes)-1]
+}
+
+// separate writes the blank line between the given block and the previous one.
+// There is none before the first block of a container, nor between the blocks of tight lists.
+func (r *renderer) separate(node ast.Node) {
+	parent := node.GetParent()
+
+	if r.out.Len() == 0 || parent == nil || ast.GetFirstChild(parent) == node {
+		return
+	}
+
+	switch p := parent.(type) {
+	case *ast.List:
+		if p.Tight {
+			return
+		}
+	case *ast.ListItem:
+		if list, ok := p.GetParent().(*ast.List); p.Tight || ok && list.Tight {
+			return
+		}
+	}
+
+	r.newline()
+}
+
+func (r *renderer) blocks(node ast.Node) {
+	for _, child := range node.GetChildren() {
+		r.block(child)
+	}
+}
+
+func (r *renderer) block(node ast.Node) {
+	// The HTML blocks without text, e.g. the comments, are left out
+	if block, ok := node.(*ast.HTMLBlock); ok && len(htmlLines(string(block.Literal))) == 0 {
+		return
+	}
+
+	r.separate(node)
+
+	switch n := node.(type) {
+	case *ast.Paragraph:
+		r.inlines(n)
+		r.newline()
+	case *ast.Heading:
+		attrs := "b"
+		if n.Level <= 2 {
+			attrs = "bu"
+		}
+		r.startLine()
+		r.raw(fmt.Sprintf(`["%s"]`, r.anchor(n)))
+		r.push(textStyle{fg: r.style.Heading, attrs: attrs})
+		r.inlines(n)
+		r.pop()
+		r.raw(`[""]`)
+		r.newline()
+	case *ast.List:
+		r.list(n)
+	case *ast.BlockQuote, *ast.Aside:
+		r.quote(n)
+	case *ast.CodeBlock:
+		r.code(n)
+	case *ast.Table:
+		r.table(n)
+	case *ast.HorizontalRule:
+		r.push(textStyle{fg: r.style.Quote})
+		r.text(strings.Repeat("─", ruleWidth))
+		r.pop()
+		r.newline()
+	case *ast.HTMLBlock:
+		for _, line := range htmlLines(string(n.Literal)) {
+			r.text(line)
+			r.newline()
+		}
+	default:
+		if node.AsContainer() != nil {
+			r.blocks(node)
+		} else if leaf := node.AsLeaf(); leaf != nil && len(leaf.Literal) > 0 {
+			r.text(string(leaf.Literal))
+			r.newline()
+		}
+	}
+}
+
+// anchor adds the anchor of the heading and returns its region, the duplicate anchors are numbered like on GitHub.
+func (r *renderer) anchor(heading *ast.Heading) string {
+	anchor := Anchor(plainText(heading))
+
+	for i := 1; ; i++ {
+		if _, ok := r.anchors[anchor]; !ok {
+			break
+		}
+		anchor = fmt.Sprintf("%s-%d", Anchor(plainText(heading)), i)
+	}
+
+	region := fmt.Sprintf("h%d", len(r.anchors))
+	r.anchors[anchor] = region
+
+	return region
+}
+
+// list renders the items of the list, indented after their bullet or number.
+func (r *renderer) list(list *ast.List) {
+	depth := 0
+	for parent := list.GetParent(); parent != nil; parent = parent.GetParent() {
+		if _, ok := parent.(*ast.List); ok {
+			depth++
+		}
+	}
+
+	number := list.Start
+	if number == 0 {
+		number = 1
+	}
+
+	delimiter := list.Delimiter
+	if delimiter == 0 {
+		delimiter = '.'
+	}
+
+	for i, item := range list.GetChildren() {
+		if i > 0 && !list.Tight {
+			r.newline()
+		}
+
+		marker := bullets[depth%len(bullets)] + " "
+
+		if list.ListFlags&ast.ListTypeOrdered != 0 {
+			marker = fmt.Sprintf("%d%c ", number, delimiter)
+			number++
+		}
+
+		r.pushPrefix(marker, strings.Repeat(" ", runewidth.StringWidth(marker)), r.style.Heading)
+
+		if len(item.GetChildren()) == 0 {
+			r.newline()
+		}
+
+		r.blocks(item)
+		r.popPrefix()
+	}
+}
+
+// quote renders the blocks of the quote after a bar, the admonitions get a title and their own color.
+func (r *renderer) quote(quote ast.Node) {
+	color := r.style.Quote
+	title := admonition(quote)
+
+	switch title {
+	case "":
+	case admonitions["NOTE"], admonitions["TIP"]:
+		color = r.style.Note
+	default:
+		color = r.style.Warning
+	}
+
+	r.pushPrefix("│ ", "│ ", color)
+
+	if title != "" {
+		r.push(textStyle{fg: color, attrs: "b"})
+		r.text(title)
+		r.pop()
+		r.newline()
+	}
+
+	r.blocks(quote)
+	r.popPrefix()
+}
+
+// admonition returns the title of the admonition when the quote is one, its label is removed from the quote.
+// Both the GitHub alerts, e.g. "> [!WARNING]", and the strong labels, e.g. "> **Warning:**", are recognized.
+func admonition(quote ast.Node) string {
+	paragraph, ok := ast.GetFirstChild(quote).(*ast.Paragraph)
+
+	if !ok || len(paragraph.GetChildren()) == 0 {
+		return ""
+	}
+
+	inlines := paragraph.GetChildren()
+
+	if text, ok := inlines[0].(*ast.Text); ok {
+		literal := string(text.Literal)
+
+		if end := strings.Index(literal, "]"); strings.HasPrefix(literal, "[!") && end > 0 {
+			if title, ok := admonitions[strings.ToUpper(literal[2:end])]; ok {
+				text.Literal = []byte(strings.TrimLeft(literal[end+1:], " \n"))
+
+				if len(text.Literal) == 0 && len(inlines) == 1 {
+					ast.RemoveFromTree(paragraph)
+				}
+
+				return title
+			}
+		}
+	}
+
+	for i, inline := range inlines {
+		if strong, ok := inline.(*ast.Strong); ok {
+			label := strings.TrimRight(plainText(strong), ": ")
+			title, ok := admonitions[strings.ToUpper(label)]
+
+			if !ok {
+				return ""
+			}
+
+			if i+1 < len(inlines) {
+				if text, ok := inlines[i+1].(*ast.Text); ok {
+					text.Literal = []byte(strings.TrimLeft(string(text.Literal), ": \n"))
+				}
+			}
+
+			ast.RemoveFromTree(strong)
+			return title
+		}
+
+		// Only the empty text nodes can come before the label
+		if text, ok := inline.(*ast.Text); !ok || strings.TrimSpace(string(text.Literal)) != "" {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// plainText returns the text of the node without its formatting.
+func plainText(node ast.Node) string {
+	var text strings.Builder
+
+	ast.WalkFunc(node, func(node ast.Node, entering bool) ast.WalkStatus {
+		if leaf := node.AsLeaf(); leaf != nil && entering {
+			text.Write(leaf.Literal)
+		}
+		return ast.GoToNext
+	})
+
+	return text.String()
+}
+
+// code renders the code block in its own region, on a background padded to the width of its longest line.
+func (r *renderer) code(block *ast.CodeBlock) {
+	code := strings.TrimRight(string(block.Literal), "\n")
+	lines := strings.Split(code, "\n")
+	width := 0
+
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "\t", "    ")
+
+		if w := runewidth.StringWidth(lines[i]); w > width {
+			width = w
+		}
+	}
+
+	language, _, _ := strings.Cut(strings.TrimSpace(string(block.Info)), " ")
+
+	r.startLine()
+	r.raw(fmt.Sprintf(`["c%d"]`, len(r.codeBlocks)))
+	r.codeBlocks = append(r.codeBlocks, CodeBlock{Language: language, Code: code})
+	r.push(textStyle{fg: r.style.Code, bg: r.style.CodeBackground})
+
+	for i, line := range append(append([]string{""}, lines...), "") {
+		r.text(" " + line + strings.Repeat(" ", width-runewidth.StringWidth(line)+1))
+
+		// The region ends with the last line, so that the highlight doesn't spread to the next blocks
+		if i == len(lines)+1 {
+			r.pop()
+			r.raw(`[""]`)
+		}
+
+		r.newline()
+	}
+}
+
+// tableCell is a rendered table cell.
+type tableCell struct {
+	text  string
+	width int
+	align ast.CellAlignFlags
+}
+
+// table renders the table with its columns aligned, the header rows are separated from the body by a rule.
+func (r *renderer) table(table *ast.Table) {
+	var rows [][]tableCell
+	var widths []int
+	headerRows := 0
+
+	for _, section := range table.GetChildren() {
+		for _, row := range section.GetChildren() {
+			var cells []tableCell
+
+			for i, node := range row.GetChildren() {
+				cell, ok := node.(*ast.TableCell)
+
+				if !ok {
+					continue
+				}
+
+				rendered := r.cell(cell)
+				cells = append(cells, rendered)
+
+				if i >= len(widths) {
+					widths = append(widths, 0)
+				}
+
+				if rendered.width > widths[i] {
+					widths[i] = rendered.width
+				}
+			}
+
+			if _, ok := section.(*ast.TableHeader); ok {
+				headerRows++
+			}
+
+			rows = append(rows, cells)
+		}
+	}
+
+	for i, cells := range rows {
+		if i == headerRows && headerRows > 0 {
+			rule := make([]string, len(widths))
+			for column, width := range widths {
+				rule[column] = strings.Repeat("─", width)
+			}
+
+			r.push(textStyle{fg: r.style.Quote})
+			r.text(strings.Join(rule, "─┼─"))
+			r.pop()
+			r.newline()
+		}
+
+		for column, width := range widths {
+			if column > 0 {
+				r.push(textStyle{fg: r.style.Quote})
+				r.text(" │ ")
+				r.pop()
+			}
+
+			var cell tableCell
+			if column < len(cells) {
+				cell = cells[column]
+			}
+
+			padding := width - cell.width
+			left := 0
+
+			switch cell.align {
+			case ast.TableAlignmentRight:
+				left = padding
+			case ast.TableAlignmentCenter:
+				left = padding / 2
+			}
+
+			r.text(strings.Repeat(" ", left))
+			r.startLine()
+			r.raw(cell.text)
+			r.width += cell.width
+			r.text(strings.Repeat(" ", padding-left))
+		}
+
+		r.newline()
+	}
+}
+
+// cell renders the content of the table cell on a single line.
+func (r *renderer) cell(cell *ast.TableCell) tableCell {
+	sub := newRenderer(r.style, r.current())
+	sub.links = r.links
+	sub.singleLine = true
+	sub.lineStart = false
+
+	if cell.IsHeader {
+		sub.push(textStyle{fg: r.style.Heading, attrs: "b"})
+	}
+
+	sub.inlines(cell)
+
+	if cell.IsHeader {
+		sub.pop()
+	}
+
+	sub.flush()
+	r.links = sub.links
+
+	return tableCell{text: sub.out.String(), width: sub.width, align: cell.Align}
+}
+
+// htmlLines returns the lines of text of the HTML block, without the tags.
+func htmlLines(block string) []string {
+	var lines []string
+
+	for _, line := range strings.Split(htmlCommentPattern.ReplaceAllString(block, ""), "\n") {
+		line = strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(line, "")))
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func (r *renderer) inlines(node ast.Node) {
+	for _, child := range node.GetChildren() {
+		r.inline(child)
+	}
+}
+
+func (r *renderer) inline(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.Text:
+		r.text(strings.ReplaceAll(string(n.Literal), "\n", " "))
+	case *ast.Softbreak:
+		r.text(" ")
+	case *ast.Hardbreak:
+		r.newline()
+	case *ast.Emph:
+		r.push(textStyle{attrs: "i"})
+		r.inlines(n)
+		r.pop()
+	case *ast.Strong:
+		r.push(textStyle{attrs: "b"})
+		r.inlines(n)
+		r.pop()
+	case *ast.Del:
+		r.push(textStyle{attrs: "s"})
+		r.inlines(n)
+		r.pop()
+	case *ast.Code:
+		r.push(textStyle{fg: r.style.Code, bg: r.style.CodeBackground})
+		r.text(string(n.Literal))
+		r.pop()
+	case *ast.Link:
+		r.link(string(n.Destination), func() {
+			if len(n.GetChildren()) == 0 {
+				r.text(string(n.Destination))
+			}
+			r.inlines(n)
+		})
+	case *ast.Image:
+		r.link(string(n.Destination), func() {
+			alt := plainText(n)
+			if alt == "" {
+				alt = string(n.Destination)
+			}
+			r.text("[image: " + alt + "]")
+		})
+	case *ast.HTMLSpan:
+		if htmlBreakPattern.MatchString(string(n.Literal)) {
+			r.newline()
+		}
+	default:
+		if node.AsContainer() != nil {
+			r.inlines(node)
+		} else if leaf := node.AsLeaf(); leaf != nil {
+			r.text(string(leaf.Literal))
+		}
+	}
+}
+
+// link renders the text of a link in its own region, the destination is added to the links of the document.
+func (r *renderer) link(destination string, text func()) {
+	r.startLine()
+	r.raw(fmt.Sprintf(`["%d"]`, len(r.links)))
+	r.links = append(r.links, destination)
+	r.push(textStyle{fg: r.style.Link, attrs: "u"})
+	text()
+	r.pop()
+	r.raw(`[""]`)
+}
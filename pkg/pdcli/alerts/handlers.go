@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
@@ -18,23 +19,26 @@ type Note struct {
 	CreatedAt string
 }
 type Alert struct {
-	IncidentID  string
-	AlertID     string
-	ClusterID   string
-	ClusterName string
-	Name        string
-	Console     string
-	Hostname    string
-	IP          string
-	Labels      string
-	LastCheckIn string
-	Severity    string
-	Status      string
-	Sop         string
-	Token       string
-	Tags        string
-	WebURL      string
-	Notes       []Note
+	IncidentID     string
+	AlertID        string
+	ClusterID      string
+	ClusterName    string
+	Name           string
+	Service        string
+	Console        string
+	Hostname       string
+	IP             string
+	Labels         string
+	LastCheckIn    string
+	Severity       string
+	Status         string
+	IncidentStatus string
+	CreatedAt      string
+	Sop            string
+	Token          string
+	Tags           string
+	WebURL         string
+	Notes          []Note
 }
 
 var (
@@ -114,8 +118,9 @@ func GetIncidentAlerts(c client.PagerDutyClient, incident pdApi.Incident) ([]Ale
 
 		tempAlertObj := Alert{}
 
-		// Fetch incident Urgency
+		// Fetch incident Urgency and Status
 		tempAlertObj.Severity = incident.Urgency
+		tempAlertObj.IncidentStatus = incident.Status
 
 		if tempAlertObj.Severity == "" {
 			tempAlertObj.Severity = alert.Severity
@@ -192,8 +197,10 @@ func (a *Alert) ParseAlertData(c client.PagerDutyClient, alert *pdApi.IncidentAl
 	a.IncidentID = alert.Incident.ID
 	a.AlertID = alert.ID
 	a.Name = alert.Summary
+	a.Service = alert.Service.Summary
 	a.Status = alert.Status
 	a.WebURL = alert.HTMLURL
+	a.CreatedAt = alert.CreatedAt
 
 	// Check if the alert is of type 'Missing cluster'
 	isCHGM := alert.Body["details"].(map[string]interface{})["notes"]
@@ -316,8 +323,9 @@ func ParseAlertMetaData(alert Alert) string {
 	return alertData
 }
 
-// getTableData parses and returns tabular data for the given alerts, i.e table headers and rows.
-func GetTableData(alerts []Alert, cols string) ([]string, [][]string) {
+// GetTableData parses and returns tabular data for the given alerts, i.e table headers and rows.
+// The age column displays the age bucket of the alerts for the given buckets.
+func GetTableData(alerts []Alert, cols string, ageBuckets []time.Duration) ([]string, [][]string) {
 	var headers []string
 	var tableData [][]string
 
@@ -332,6 +340,8 @@ func GetTableData(alerts []Alert, cols string) ([]string, [][]string) {
 
 	headersMap := make(map[int]string)
 
+	now := time.Now()
+
 	for _, alert := range alerts {
 
 		var values []string
@@ -371,7 +381,7 @@ func GetTableData(alerts []Alert, cols string) ([]string, [][]string) {
 		if columnsMap["status"] {
 			i++
 			headersMap[i] = "STATUS"
-			values = append(values, alert.Status)
+			values = append(values, alert.IncidentState())
 		}
 
 		if columnsMap["severity"] {
@@ -380,6 +390,12 @@ func GetTableData(alerts []Alert, cols string) ([]string, [][]string) {
 			values = append(values, alert.Severity)
 		}
 
+		if columnsMap["age"] {
+			i++
+			headersMap[i] = "AGE"
+			values = append(values, AgeBucketLabel(alert.AgeBucket(now, ageBuckets), ageBuckets))
+		}
+
 		tableData = append(tableData, values)
 	}
 
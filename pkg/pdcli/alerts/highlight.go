@@ -0,0 +1,156 @@
+package pdcli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+// HighlightRules defines which rules are used to color code the alerts table.
+type HighlightRules struct {
+	// Color the rows by the alert severity or incident urgency
+	Severity bool
+	// Color the status cells by the incident status
+	Status bool
+	// Mark the alerts of clusters with multiple active alerts
+	Cluster bool
+	// Color the age cells by the age bucket
+	Age bool
+	// Upper bounds of the age buckets in ascending order
+	AgeBuckets []time.Duration
+}
+
+const (
+	HighlightSeverity = "severity"
+	HighlightStatus   = "status"
+	HighlightCluster  = "cluster"
+	HighlightAge      = "age"
+)
+
+// DefaultAgeBuckets are the age buckets used when none are specified.
+var DefaultAgeBuckets = []time.Duration{time.Hour, 4 * time.Hour, 24 * time.Hour}
+
+// ParseHighlightRules parses the comma separated highlight rules and age buckets, e.g. "severity,status" and "1h,4h,24h".
+func ParseHighlightRules(highlight string, ageBuckets string) (HighlightRules, error) {
+	rules := HighlightRules{AgeBuckets: DefaultAgeBuckets}
+
+	for _, rule := range strings.Split(highlight, ",") {
+		switch strings.TrimSpace(rule) {
+		case "":
+		case HighlightSeverity:
+			rules.Severity = true
+		case HighlightStatus:
+			rules.Status = true
+		case HighlightCluster:
+			rules.Cluster = true
+		case HighlightAge:
+			rules.Age = true
+		default:
+			return HighlightRules{}, fmt.Errorf("unknown highlight rule '%s'", rule)
+		}
+	}
+
+	if ageBuckets == "" {
+		return rules, nil
+	}
+
+	rules.AgeBuckets = nil
+
+	for _, bucket := range strings.Split(ageBuckets, ",") {
+		duration, err := time.ParseDuration(strings.TrimSpace(bucket))
+
+		if err != nil {
+			return HighlightRules{}, fmt.Errorf("invalid age bucket '%s': %v", bucket, err)
+		}
+
+		if len(rules.AgeBuckets) > 0 && duration <= rules.AgeBuckets[len(rules.AgeBuckets)-1] {
+			return HighlightRules{}, fmt.Errorf("age buckets must be in ascending order")
+		}
+
+		rules.AgeBuckets = append(rules.AgeBuckets, duration)
+	}
+
+	return rules, nil
+}
+
+// IsHighSeverity reports whether the alert has a high urgency or a critical severity.
+func (a Alert) IsHighSeverity() bool {
+	switch a.Severity {
+	case constants.StatusHigh, "critical", "error":
+		return true
+	}
+	return false
+}
+
+// IncidentState returns the status of the incident the alert belongs to,
+// falling back to the alert status if it is unknown.
+func (a Alert) IncidentState() string {
+	if a.IncidentStatus != "" {
+		return a.IncidentStatus
+	}
+	return a.Status
+}
+
+// ClusterAlertCounts returns the number of alerts for each cluster ID.
+// Alerts without a cluster ID are not counted.
+func ClusterAlertCounts(alerts []Alert) map[string]int {
+	counts := make(map[string]int)
+
+	for _, alert := range alerts {
+		if alert.ClusterID == "" || alert.ClusterID == "N/A" {
+			continue
+		}
+		counts[alert.ClusterID]++
+	}
+
+	return counts
+}
+
+// AgeBucket returns the index of the age bucket the alert falls into at the given time.
+// Alerts older than the last bucket return len(buckets), alerts with an unknown age return -1.
+func (a Alert) AgeBucket(now time.Time, buckets []time.Duration) int {
+	createdAt, err := time.Parse(time.RFC3339, a.CreatedAt)
+
+	if err != nil {
+		return -1
+	}
+
+	age := now.Sub(createdAt)
+
+	for i, bucket := range buckets {
+		if age < bucket {
+			return i
+		}
+	}
+
+	return len(buckets)
+}
+
+// AgeBucketLabel returns the text displayed for the given age bucket, e.g. "< 1h", "1h - 4h" or "> 24h".
+func AgeBucketLabel(bucket int, buckets []time.Duration) string {
+	switch {
+	case bucket < 0 || bucket > len(buckets) || len(buckets) == 0:
+		return "N/A"
+	case bucket == 0:
+		return "< " + formatDuration(buckets[0])
+	case bucket == len(buckets):
+		return "> " + formatDuration(buckets[len(buckets)-1])
+	}
+
+	return formatDuration(buckets[bucket-1]) + " - " + formatDuration(buckets[bucket])
+}
+
+// formatDuration returns a short form of the duration, e.g. "4h" instead of "4h0m0s".
+func formatDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
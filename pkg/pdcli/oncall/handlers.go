@@ -7,7 +7,6 @@ import (
 
 	"github.com/PagerDuty/go-pagerduty"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
-	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 )
 
@@ -24,18 +23,12 @@ type OncallLayer struct {
 	Users   []OncallUser
 }
 
-// TeamSREOnCall fetches the current roles and names of on-call users.
-func TeamSREOnCall(c client.PagerDutyClient) ([]OncallLayer, error) {
+// TeamSREOnCall fetches the current roles and names of the on-call users of the given schedules.
+func TeamSREOnCall(c client.PagerDutyClient, scheduleIDs []string) ([]OncallLayer, error) {
 	var callOpts pagerduty.ListOnCallOptions
 	var oncallLayers []OncallLayer
 
-	callOpts.ScheduleIDs = []string{
-		constants.PrimaryScheduleID,
-		constants.SecondaryScheduleID,
-		constants.OncallManager,
-		constants.OncallIDWeekend,
-		constants.InvestigatorID,
-	}
+	callOpts.ScheduleIDs = scheduleIDs
 	since := time.Now().Add(time.Hour * -11)
 	until := time.Now().Add(time.Hour * 13)
 	callOpts.Since = since.String()
@@ -48,7 +41,12 @@ func TeamSREOnCall(c client.PagerDutyClient) ([]OncallLayer, error) {
 		return nil, err
 	}
 
+	if len(oncallListing.OnCalls) == 0 {
+		return nil, nil
+	}
+
 	startTime, _ := utils.FormatTimestamp(oncallListing.OnCalls[0].Start)
+	layerStart := oncallListing.OnCalls[0].Start
 	var temp []OncallUser
 	var mgmtUsers []OncallUser
 
@@ -77,12 +75,18 @@ func TeamSREOnCall(c client.PagerDutyClient) ([]OncallLayer, error) {
 			tempUser.End = timeConversionEnd
 			mgmtUsers = append(mgmtUsers, tempUser)
 			startTime, _ = utils.FormatTimestamp(oncallListing.OnCalls[ind+1].Start)
+			layerStart = oncallListing.OnCalls[ind+1].Start
 			continue
 		}
 
 		if timeConversionStart != startTime {
 			temp = append(temp, mgmtUsers...)
-			oncallStartTime := startTime[11:16]
+			oncallStartTime, err := utcClock(layerStart)
+
+			if err != nil {
+				return nil, err
+			}
+
 			var layerId string
 			switch oncallStartTime {
 			case "22:30":
@@ -103,6 +107,7 @@ func TeamSREOnCall(c client.PagerDutyClient) ([]OncallLayer, error) {
 			oncallLayers = append(oncallLayers, *oncallLayer)
 			temp = nil
 			startTime = timeConversionStart
+			layerStart = y.Start
 		}
 
 		tempUser := OncallUser{}
@@ -116,6 +121,17 @@ func TeamSREOnCall(c client.PagerDutyClient) ([]OncallLayer, error) {
 	return oncallLayers, err
 }
 
+// utcClock returns the UTC time of day of the timestamp, the layers start at fixed UTC times whatever the time zone of the dates
+func utcClock(timestamp string) (string, error) {
+	t, err := time.Parse(time.RFC3339, timestamp)
+
+	if err != nil {
+		return "", err
+	}
+
+	return t.UTC().Format("15:04"), nil
+}
+
 // AllTeamsOncall displays the oncall data of all Red Hat PagerDuty teams.
 func AllTeamsOncall(c client.PagerDutyClient) ([]OncallUser, error) {
 	var callOpts pagerduty.ListOnCallOptions
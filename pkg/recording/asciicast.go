@@ -0,0 +1,347 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+)
+
+const (
+	// Directory in the config directory containing the recordings
+	Dir = "recordings"
+
+	// Extension of the asciicast files
+	Extension = ".cast"
+
+	// Format of the start time in the recording names
+	timeFormat = "20060102-150405"
+)
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event is a line of an asciicast v2 file following the header.
+// Its time is the number of seconds since the start of the recording.
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// Types of the events
+const (
+	// The data is written to the terminal
+	OutputEvent = "o"
+	// The terminal is resized, the data is the size as "WIDTHxHEIGHT"
+	ResizeEvent = "r"
+)
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Time, e.Type, e.Data})
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if len(fields) != 3 {
+		return fmt.Errorf("invalid asciicast event: %s", data)
+	}
+
+	if err := json.Unmarshal(fields[0], &e.Time); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(fields[1], &e.Type); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(fields[2], &e.Data)
+}
+
+// Recorder writes the events of a terminal to an asciicast v2 file.
+type Recorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	start time.Time
+	name  string
+}
+
+// invalidNameChars are the characters replaced in the parts of the recording names
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// Name returns the name of a recording of the given incident and cluster, started at the given time.
+// The parts which are unknown are left out.
+func Name(incidentID string, cluster string, start time.Time) string {
+	var parts []string
+
+	for _, part := range []string{incidentID, cluster} {
+		part = strings.Trim(invalidNameChars.ReplaceAllString(part, "-"), "-.")
+
+		if part != "" && part != "N-A" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(append(parts, start.Format(timeFormat)), "_")
+}
+
+// Path returns the path of the recording with the given name.
+func Path(name string) (string, error) {
+	name = strings.TrimSuffix(name, Extension)
+
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid recording name '%s'", name)
+	}
+
+	configDir, err := config.Dir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, Dir, name+Extension), nil
+}
+
+// Create starts a recording of a terminal of the given size.
+// A suffix is added to the name if a recording with the same name already exists.
+func Create(name string, width int, height int, title string) (*Recorder, error) {
+	path, err := Path(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+
+	for suffix := 2; os.IsExist(err); suffix++ {
+		path = strings.TrimSuffix(path, Extension) + fmt.Sprintf("-%d", suffix) + Extension
+		file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{
+		file:  file,
+		start: time.Now(),
+		name:  strings.TrimSuffix(filepath.Base(path), Extension),
+	}
+
+	header := Header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Title:     title,
+		Env:       map[string]string{"TERM": "xterm-256color", "SHELL": os.Getenv("SHELL")},
+	}
+
+	if err = r.writeLine(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Name returns the name of the recording.
+func (r *Recorder) Name() string {
+	return r.name
+}
+
+// Output records the data written to the terminal.
+func (r *Recorder) Output(data string) error {
+	return r.writeEvent(OutputEvent, data)
+}
+
+// Resize records the new size of the terminal.
+func (r *Recorder) Resize(width int, height int) error {
+	return r.writeEvent(ResizeEvent, fmt.Sprintf("%dx%d", width, height))
+}
+
+// Close ends the recording.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *Recorder) writeEvent(kind string, data string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.writeLine(Event{Time: time.Since(r.start).Seconds(), Type: kind, Data: data})
+}
+
+func (r *Recorder) writeLine(value interface{}) error {
+	line, err := json.Marshal(value)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = r.file.Write(append(line, '\n'))
+	return err
+}
+
+// Recording describes a recording file.
+type Recording struct {
+	Name     string
+	Header   Header
+	Duration time.Duration
+}
+
+// Start returns the time the recording was started.
+func (r Recording) Start() time.Time {
+	return time.Unix(r.Header.Timestamp, 0)
+}
+
+// List returns the recordings, the oldest first.
+// The files which are not asciicast files are ignored.
+func List() ([]Recording, error) {
+	configDir, err := config.Dir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(configDir, Dir, "*"+Extension))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var recordings []Recording
+
+	for _, file := range files {
+		recording, err := Open(strings.TrimSuffix(filepath.Base(file), Extension))
+
+		if err == nil {
+			recordings = append(recordings, recording)
+		}
+	}
+
+	sort.SliceStable(recordings, func(i, j int) bool {
+		return recordings[i].Header.Timestamp < recordings[j].Header.Timestamp
+	})
+
+	return recordings, nil
+}
+
+// Open reads the header and the duration of the recording with the given name.
+func Open(name string) (Recording, error) {
+	recording := Recording{Name: strings.TrimSuffix(name, Extension)}
+
+	err := read(name, func(header Header) {
+		recording.Header = header
+	}, func(event Event) error {
+		recording.Duration = time.Duration(event.Time * float64(time.Second))
+		return nil
+	})
+
+	return recording, err
+}
+
+// read calls the given functions with the header and the events of the recording with the given name.
+func read(name string, onHeader func(Header), onEvent func(Event) error) error {
+	path, err := Path(name)
+
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return fmt.Errorf("cannot read recording '%s': %v", name, err)
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		return fmt.Errorf("recording '%s' is empty", name)
+	}
+
+	var header Header
+
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Version != 2 {
+		return fmt.Errorf("recording '%s' is not an asciicast v2 file", name)
+	}
+
+	onHeader(header)
+
+	for scanner.Scan() {
+		var event Event
+
+		// A recording which was interrupted may end with a partial line
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			break
+		}
+
+		if err := onEvent(event); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Play writes the output of the recording with the given name to out, at its original pace multiplied by speed.
+// The pauses of the recording are shortened to idleLimit, if it is not zero.
+func Play(name string, out io.Writer, speed float64, idleLimit time.Duration) error {
+	if speed <= 0 {
+		return fmt.Errorf("invalid speed %v", speed)
+	}
+
+	var last float64
+
+	return read(name, func(Header) {
+		fmt.Fprint(out, "\x1b[H\x1b[2J")
+	}, func(event Event) error {
+		delay := time.Duration((event.Time - last) / speed * float64(time.Second))
+		last = event.Time
+
+		if idleLimit > 0 && delay > idleLimit {
+			delay = idleLimit
+		}
+
+		time.Sleep(delay)
+
+		if event.Type != OutputEvent {
+			return nil
+		}
+
+		_, err := io.WriteString(out, event.Data)
+		return err
+	})
+}
@@ -0,0 +1,263 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const (
+	// FileName is the name of the encrypted secret file, in the config directory
+	FileName = "secrets.enc"
+
+	// PassphraseEnv is the environment variable holding the passphrase of the secret file, it is prompted for otherwise
+	PassphraseEnv = "KITE_PASSPHRASE"
+
+	// Header of the secret file, followed by the salt of the key, the nonce and the encrypted secrets
+	fileHeader = "kite-secrets-v1\n"
+	saltSize   = 16
+)
+
+var (
+	// passphrase is asked once per run
+	passphrase     string
+	passphraseLock sync.Mutex
+)
+
+// fileStore keeps the secrets in a JSON object encrypted with XChaCha20-Poly1305, the key is derived from a passphrase with scrypt.
+type fileStore struct {
+	path string
+}
+
+func newFileStore(dir string) *fileStore {
+	return &fileStore{path: filepath.Join(dir, FileName)}
+}
+
+// readPassphrase returns the passphrase of the secret file, from the environment or the terminal.
+// A new passphrase is prompted twice when the file is created.
+func readPassphrase(create bool) (string, error) {
+	passphraseLock.Lock()
+	defer passphraseLock.Unlock()
+
+	if value := os.Getenv(PassphraseEnv); value != "" {
+		return value, nil
+	}
+
+	if passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("the passphrase of the secret file is required, set %s", PassphraseEnv)
+	}
+
+	prompt := func(message string) (string, error) {
+		fmt.Fprint(os.Stderr, message)
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(value), err
+	}
+
+	value, err := prompt("Passphrase of the kite secrets: ")
+
+	if err != nil {
+		return "", err
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("the passphrase of the secret file is empty")
+	}
+
+	if create {
+		confirmation, err := prompt("Confirm the passphrase: ")
+
+		if err != nil {
+			return "", err
+		}
+
+		if confirmation != value {
+			return "", fmt.Errorf("the passphrases don't match")
+		}
+	}
+
+	passphrase = value
+
+	return passphrase, nil
+}
+
+// deriveKey returns the encryption key of the passphrase
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, chacha20poly1305.KeySize)
+}
+
+// read decrypts the secrets of the file, none if it doesn't exist
+func (f *fileStore) read() (map[string]string, error) {
+	data, err := os.ReadFile(f.path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, []byte(fileHeader)) || len(data) < len(fileHeader)+saltSize+chacha20poly1305.NonceSizeX {
+		return nil, fmt.Errorf("invalid secret file '%s'", f.path)
+	}
+
+	data = data[len(fileHeader):]
+	salt, nonce, ciphertext := data[:saltSize], data[saltSize:saltSize+chacha20poly1305.NonceSizeX], data[saltSize+chacha20poly1305.NonceSizeX:]
+
+	passphrase, err := readPassphrase(false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(passphrase, salt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(fileHeader))
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt the secret file '%s', the passphrase is wrong", f.path)
+	}
+
+	secrets := map[string]string{}
+
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("invalid secret file '%s': %v", f.path, err)
+	}
+
+	return secrets, nil
+}
+
+// write encrypts the secrets to the file, with a new salt and nonce
+func (f *fileStore) write(secrets map[string]string) error {
+	_, statErr := os.Stat(f.path)
+	passphrase, err := readPassphrase(errors.Is(statErr, os.ErrNotExist))
+
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(secrets)
+
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltSize)
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	key, err := deriveKey(passphrase, salt)
+
+	if err != nil {
+		return err
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+
+	if err != nil {
+		return err
+	}
+
+	data := append([]byte(fileHeader), salt...)
+	data = append(data, nonce...)
+	data = aead.Seal(data, nonce, plaintext, []byte(fileHeader))
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+
+	// The file is replaced at once, so that the secrets aren't lost when kite is interrupted
+	tmp := f.path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("cannot save the secret file '%s': %v", f.path, err)
+	}
+
+	return os.Rename(tmp, f.path)
+}
+
+func (f *fileStore) Get(name string) (string, error) {
+	// The secrets aren't decrypted, nor the passphrase prompted, when there is no secret file
+	if _, err := os.Stat(f.path); errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	}
+
+	secrets, err := f.read()
+
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secrets[name]
+
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+func (f *fileStore) Set(name string, value string) error {
+	secrets, err := f.read()
+
+	if err != nil {
+		return err
+	}
+
+	secrets[name] = value
+
+	return f.write(secrets)
+}
+
+func (f *fileStore) Delete(name string) error {
+	if _, err := os.Stat(f.path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	secrets, err := f.read()
+
+	if err != nil {
+		return err
+	}
+
+	if _, ok := secrets[name]; !ok {
+		return nil
+	}
+
+	delete(secrets, name)
+
+	return f.write(secrets)
+}
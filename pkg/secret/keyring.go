@@ -0,0 +1,121 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Service the secrets are stored under in the keyring
+const keyringService = "kite"
+
+// keyring stores the secrets in the keyring of the desktop with its command line tool:
+// secret-tool for the Secret Service on Linux, e.g. GNOME Keyring or KWallet, and security for the macOS Keychain.
+type keyring struct {
+	command string
+}
+
+func newKeyring() (Store, error) {
+	command := "secret-tool"
+
+	if runtime.GOOS == "darwin" {
+		command = "security"
+	}
+
+	if _, err := exec.LookPath(command); err != nil {
+		return nil, fmt.Errorf("the keyring is not available, %s is not found: install it or use the '%s' secret storage", command, File)
+	}
+
+	return &keyring{command: command}, nil
+}
+
+// run runs the keyring command with the given input, and returns its output and its exit code
+func (k *keyring) run(input string, args ...string) (string, int, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(k.command, args...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) {
+		return stdout.String(), exitErr.ExitCode(), fmt.Errorf("%s failed: %s", k.command, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), 0, err
+}
+
+func (k *keyring) Get(name string) (string, error) {
+	var output string
+	var code int
+	var err error
+
+	// The secrets which aren't stored make secret-tool exit with 1 and security with 44
+	if k.command == "security" {
+		output, code, err = k.run("", "find-generic-password", "-s", keyringService, "-a", name, "-w")
+		if code == 44 {
+			return "", ErrNotFound
+		}
+	} else {
+		output, code, err = k.run("", "lookup", "service", keyringService, "account", name)
+		if code == 1 && output == "" {
+			return "", ErrNotFound
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(output, "\n"), nil
+}
+
+func (k *keyring) Set(name string, value string) error {
+	if k.command == "security" {
+		// The command is read from the standard input so that the password doesn't show in the process list,
+		// the password is given as hexadecimal so that it needs no quoting
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %q -X %s\n", keyringService, name, hex.EncodeToString([]byte(value)))
+
+		if _, _, err := k.run(command, "-i"); err != nil {
+			return err
+		}
+
+		// The commands of the interactive mode may fail without an exit code, the password is read back
+		stored, err := k.Get(name)
+
+		if err != nil {
+			return err
+		}
+
+		if stored != value {
+			return fmt.Errorf("%s failed to save the %s", k.command, name)
+		}
+
+		return nil
+	}
+
+	_, _, err := k.run(value, "store", "--label", fmt.Sprintf("kite %s", name), "service", keyringService, "account", name)
+	return err
+}
+
+func (k *keyring) Delete(name string) error {
+	if _, err := k.Get(name); errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	if k.command == "security" {
+		_, _, err := k.run("", "delete-generic-password", "-s", keyringService, "-a", name)
+		return err
+	}
+
+	_, _, err := k.run("", "clear", "service", keyringService, "account", name)
+	return err
+}
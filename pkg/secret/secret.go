@@ -0,0 +1,83 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Names of the secrets, as the fields of the configuration file
+const (
+	APIKey      = "api_key"
+	GitHubToken = "gh_token"
+)
+
+// Storages of the secrets
+const (
+	// Plain stores the secrets in the configuration file, as plain text
+	Plain = "plain"
+	// Keyring stores the secrets in the keyring of the desktop: the Secret Service on Linux, the Keychain on macOS
+	Keyring = "keyring"
+	// File stores the secrets in a file of the config directory, encrypted with a passphrase
+	File = "file"
+)
+
+// ErrNotFound is returned when the secret isn't stored.
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps the secrets of kite under their name.
+type Store interface {
+	// Get returns the secret with the given name, ErrNotFound if it isn't stored
+	Get(name string) (string, error)
+
+	// Set stores the secret with the given name, replacing the previous one
+	Set(name string, value string) error
+
+	// Delete removes the secret with the given name, if it is stored
+	Delete(name string) error
+}
+
+// New returns the store of the given storage, the file storage keeps its file in the given directory.
+// The plain storage has no store, the secrets stay in the configuration file.
+func New(storage string, dir string) (Store, error) {
+	switch storage {
+	case "", Plain:
+		return nil, nil
+	case Keyring:
+		return newKeyring()
+	case File:
+		return newFileStore(dir), nil
+	}
+
+	return nil, fmt.Errorf("unknown secret storage '%s', expected %s, %s or %s", storage, Plain, Keyring, File)
+}
+
+// EnvVar returns the environment variable overriding the secret with the given name, e.g. KITE_API_KEY.
+func EnvVar(name string) string {
+	return "KITE_" + strings.ToUpper(name)
+}
+
+// FromCommand returns the secret printed by the command, e.g. "pass show pagerduty".
+// Only the first line is kept, the password managers printing metadata on the next lines.
+func FromCommand(command string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("the secret command '%s' failed: %v %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	value, _, _ := strings.Cut(stdout.String(), "\n")
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return "", fmt.Errorf("the secret command '%s' printed nothing", command)
+	}
+
+	return value, nil
+}
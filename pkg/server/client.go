@@ -0,0 +1,120 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// Size of the terminal reported by clients which aren't attached from a terminal
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// client forwards the input and the size of the local terminal to a server.
+type client struct {
+	conn net.Conn
+	mu   sync.Mutex
+}
+
+func (c *client) send(kind byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return writeFrame(c.conn, kind, payload)
+}
+
+// Attach displays the TUI of the server with the given name on the terminal of in and out until it is detached.
+// The terminal is put in raw mode while it is attached.
+func Attach(name string, in *os.File, out io.Writer) error {
+	path, err := SocketPath(name)
+
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.Dial("unix", path)
+
+	if err != nil {
+		return fmt.Errorf("no kite server named '%s' is running", name)
+	}
+
+	defer conn.Close()
+
+	c := &client{conn: conn}
+	fd := int(in.Fd())
+	width, height := terminalSize(fd)
+
+	err = c.send(frameHello, append(resizePayload(width, height), os.Getenv("TERM")...))
+
+	if err != nil {
+		return err
+	}
+
+	if term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+
+		if err != nil {
+			return err
+		}
+
+		defer term.Restore(fd, state)
+	}
+
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	defer signal.Stop(resized)
+
+	go func() {
+		for range resized {
+			width, height := terminalSize(fd)
+
+			if c.send(frameResize, resizePayload(width, height)) != nil {
+				return
+			}
+		}
+	}()
+
+	go func() {
+		buffer := make([]byte, 4096)
+
+		for {
+			n, err := in.Read(buffer)
+
+			if n > 0 && c.send(frameData, buffer[:n]) != nil {
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// The server closes the connection when the client is detached
+	_, err = io.Copy(out, conn)
+
+	if err != nil && !errors.Is(err, net.ErrClosed) && !errors.Is(err, syscall.ECONNRESET) {
+		return err
+	}
+
+	return nil
+}
+
+// terminalSize returns the size of the given terminal, the default size if it isn't a terminal.
+func terminalSize(fd int) (int, int) {
+	width, height, err := term.GetSize(fd)
+
+	if err != nil || width <= 0 || height <= 0 {
+		return defaultWidth, defaultHeight
+	}
+
+	return width, height
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The client sends framed messages to the server, the server sends the raw terminal output to the client.
+// A frame is made of its type, the length of its payload as a big endian uint32 and its payload.
+const (
+	// The first frame sent by a client, its payload is the TERM of the client terminal
+	frameHello byte = iota + 1
+	// Input of the client terminal
+	frameData
+	// Size of the client terminal, its payload is the width and height as big endian uint16
+	frameResize
+)
+
+// Maximum payload size of a frame
+const maxFrameSize = 1 << 16
+
+type frame struct {
+	kind    byte
+	payload []byte
+}
+
+func writeFrame(w io.Writer, kind byte, payload []byte) error {
+	header := make([]byte, 5)
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+func readFrame(r io.Reader) (frame, error) {
+	header := make([]byte, 5)
+
+	if _, err := io.ReadFull(r, header); err != nil {
+		return frame{}, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+
+	if size > maxFrameSize {
+		return frame{}, fmt.Errorf("frame too large: %d bytes", size)
+	}
+
+	payload := make([]byte, size)
+
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return frame{}, err
+	}
+
+	return frame{kind: header[0], payload: payload}, nil
+}
+
+func resizePayload(width int, height int) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload, uint16(width))
+	binary.BigEndian.PutUint16(payload[2:], uint16(height))
+	return payload
+}
+
+func parseResize(payload []byte) (int, int, error) {
+	if len(payload) != 4 {
+		return 0, 0, fmt.Errorf("invalid resize frame")
+	}
+	return int(binary.BigEndian.Uint16(payload)), int(binary.BigEndian.Uint16(payload[2:])), nil
+}
@@ -0,0 +1,379 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	// Name of the server started when none is given
+	DefaultName = "default"
+
+	// Terminal used when the one of the client is unknown
+	DefaultTerm = "xterm-256color"
+
+	// Time given to a client to introduce itself
+	helloTimeout = 5 * time.Second
+)
+
+// Server shares the TUI of a kite process with the clients attached to its socket, one at a time.
+// The terminals of the slides keep running while no client is attached.
+type Server struct {
+	name     string
+	path     string
+	listener net.Listener
+	app      *tview.Application
+
+	mu     sync.Mutex
+	client net.Conn
+	tty    *connTty
+	closed bool
+}
+
+// SocketPath returns the path of the socket of the server with the given name.
+func SocketPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid server name '%s'", name)
+	}
+
+	dir, err := SocketDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name+".sock"), nil
+}
+
+// SocketDir returns the directory containing the sockets of the servers, only accessible by the current user.
+func SocketDir() (string, error) {
+	dir := filepath.Join(os.TempDir(), fmt.Sprintf("kite-%d", os.Getuid()))
+
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		dir = filepath.Join(runtimeDir, "kite")
+	}
+
+	err := os.MkdirAll(dir, 0700)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Another user could create the directory first, or a link to it, and replace the sockets by their own
+	info, err := os.Lstat(dir)
+
+	if err != nil {
+		return "", err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+
+	if !info.IsDir() || !ok || int(stat.Uid) != os.Getuid() || info.Mode().Perm() != 0700 {
+		return "", fmt.Errorf("the socket directory %s must be a directory of the current user only accessible by them (0700)", dir)
+	}
+
+	return dir, nil
+}
+
+// IsRunning returns true if a server with the given name accepts connections.
+func IsRunning(name string) bool {
+	path, err := SocketPath(name)
+
+	if err != nil {
+		return false
+	}
+
+	conn, err := net.Dial("unix", path)
+
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+
+	return true
+}
+
+// List returns the names of the running servers.
+func List() ([]string, error) {
+	dir, err := SocketDir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.sock"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".sock")
+
+		if IsRunning(name) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// New returns the server with the given name, it doesn't listen to its socket yet.
+func New(name string) (*Server, error) {
+	path, err := SocketPath(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{name: name, path: path}, nil
+}
+
+// Name returns the name of the server.
+func (s *Server) Name() string {
+	return s.name
+}
+
+// Listen creates the socket of the server, the socket of a server which is gone is replaced.
+func (s *Server) Listen() error {
+	if IsRunning(s.name) {
+		return fmt.Errorf("kite server '%s' is already running", s.name)
+	}
+
+	os.Remove(s.path)
+
+	listener, err := net.Listen("unix", s.path)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(s.path, 0600)
+
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
+	s.listener = listener
+
+	return nil
+}
+
+// Placeholder returns the screen the application draws to while no client is attached.
+func Placeholder() tcell.Screen {
+	return placeholder(defaultWidth, defaultHeight)
+}
+
+// placeholder returns a screen of the given size drawing to nobody.
+func placeholder(width int, height int) tcell.Screen {
+	ti, err := tcell.LookupTerminfo(DefaultTerm)
+
+	if err == nil {
+		screen, err := tcell.NewTerminfoScreenFromTtyTerminfo(newConnTty(nil, width, height), ti)
+
+		if err == nil {
+			return &finalScreen{Screen: screen}
+		}
+	}
+
+	return tcell.NewSimulationScreen("UTF-8")
+}
+
+// Serve attaches the clients connecting to the socket to the given application until the server is closed.
+// The application must be running on the placeholder screen.
+func (s *Server) Serve(app *tview.Application) error {
+	s.app = app
+
+	for {
+		conn, err := s.listener.Accept()
+
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+
+			if closed {
+				return nil
+			}
+
+			return err
+		}
+
+		// A client which doesn't introduce itself doesn't block the other ones
+		go s.handle(conn)
+	}
+}
+
+// handle reads the hello frame of a new client and displays the application on its terminal.
+func (s *Server) handle(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(helloTimeout))
+	hello, err := readFrame(conn)
+	conn.SetReadDeadline(time.Time{})
+
+	if err != nil || hello.kind != frameHello || len(hello.payload) < 4 {
+		conn.Close()
+		return
+	}
+
+	width, height, _ := parseResize(hello.payload[:4])
+	ti, err := tcell.LookupTerminfo(string(hello.payload[4:]))
+
+	if err != nil {
+		ti, err = tcell.LookupTerminfo(DefaultTerm)
+	}
+
+	if err != nil {
+		fmt.Fprintf(conn, "cannot attach to kite server '%s': %v\r\n", s.name, err)
+		conn.Close()
+		return
+	}
+
+	tty := newConnTty(conn, width, height)
+	screen, err := tcell.NewTerminfoScreenFromTtyTerminfo(tty, ti)
+
+	if err != nil {
+		fmt.Fprintf(conn, "cannot attach to kite server '%s': %v\r\n", s.name, err)
+		conn.Close()
+		return
+	}
+
+	go tty.readFrames()
+
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+		conn.Close()
+		return
+	}
+
+	// Only one client is attached at a time, the previous one is detached
+	previous, previousTty := s.client, s.tty
+	s.client = conn
+	s.tty = tty
+	s.setScreen(&finalScreen{Screen: screen})
+	s.mu.Unlock()
+
+	if previous != nil {
+		previousTty.detach()
+		previous.Close()
+	}
+
+	go func() {
+		<-tty.done
+		s.release(conn)
+	}()
+}
+
+// release detaches the given client if it is still attached.
+func (s *Server) release(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == conn && !s.closed {
+		// The programs of the slides keep the size of the last client
+		size, _ := s.tty.WindowSize()
+		s.tty.detach()
+		s.client = nil
+		s.tty = nil
+		s.setScreen(placeholder(size.Width, size.Height))
+	}
+
+	conn.Close()
+}
+
+// setScreen replaces the screen of the application from its event loop, the previous screen is not drawn to
+// while it is finalized.
+func (s *Server) setScreen(screen tcell.Screen) {
+	s.app.QueueUpdate(func() {
+		s.app.SetScreen(screen)
+	})
+}
+
+// finalScreen is a screen which is no longer drawn once it is finalized.
+// The application may draw its previous screen before switching to the new one, which tcell doesn't expect.
+type finalScreen struct {
+	tcell.Screen
+	finalized atomic.Bool
+}
+
+func (s *finalScreen) Fini() {
+	s.finalized.Store(true)
+	s.Screen.Fini()
+}
+
+func (s *finalScreen) Show() {
+	if !s.finalized.Load() {
+		s.Screen.Show()
+	}
+}
+
+func (s *finalScreen) Sync() {
+	if !s.finalized.Load() {
+		s.Screen.Sync()
+	}
+}
+
+// Detach detaches the attached client, the application keeps running in the background.
+// It is safe to call from the event loop of the application.
+func (s *Server) Detach() {
+	s.mu.Lock()
+	conn := s.client
+	s.mu.Unlock()
+
+	if conn != nil {
+		go s.release(conn)
+	}
+}
+
+// Attached returns true if a client is attached.
+func (s *Server) Attached() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client != nil
+}
+
+// Close stops accepting clients, disconnects the attached one and removes the socket.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if s.client != nil {
+		s.tty.detach()
+		s.client.Close()
+		s.client = nil
+		s.tty = nil
+	}
+
+	if s.listener == nil {
+		return nil
+	}
+
+	err := s.listener.Close()
+	os.Remove(s.path)
+
+	return err
+}
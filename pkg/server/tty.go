@@ -0,0 +1,172 @@
+package server
+
+import (
+	"io"
+	"net"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// connTty is the terminal of an attached client, it implements tcell.Tty over the client connection.
+// Without a connection it is the terminal of nobody, its output is discarded.
+type connTty struct {
+	conn  net.Conn
+	input chan []byte
+
+	// Closed when the client is gone
+	done chan struct{}
+
+	// Closed when the server detaches the client, the input nobody reads anymore is dropped
+	detached   chan struct{}
+	detachOnce sync.Once
+
+	mu       sync.Mutex
+	size     tcell.WindowSize
+	onResize func()
+	stopped  chan struct{}
+	pending  []byte
+}
+
+func newConnTty(conn net.Conn, width int, height int) *connTty {
+	return &connTty{
+		conn:     conn,
+		input:    make(chan []byte),
+		done:     make(chan struct{}),
+		detached: make(chan struct{}),
+		size:     tcell.WindowSize{Width: width, Height: height},
+		stopped:  make(chan struct{}),
+	}
+}
+
+// readFrames forwards the input and resize events of the client until it is gone.
+func (t *connTty) readFrames() {
+	defer close(t.done)
+
+	for {
+		f, err := readFrame(t.conn)
+
+		if err != nil {
+			return
+		}
+
+		switch f.kind {
+		case frameData:
+			select {
+			case t.input <- f.payload:
+			case <-t.detached:
+				return
+			}
+
+		case frameResize:
+			width, height, err := parseResize(f.payload)
+
+			if err != nil {
+				continue
+			}
+
+			t.mu.Lock()
+			t.size = tcell.WindowSize{Width: width, Height: height}
+			onResize := t.onResize
+			t.mu.Unlock()
+
+			if onResize != nil {
+				onResize()
+			}
+		}
+	}
+}
+
+// detach stops forwarding the input of the client, it is called by the server once the client is replaced or released.
+func (t *connTty) detach() {
+	t.detachOnce.Do(func() {
+		close(t.detached)
+	})
+}
+
+// Start is a no-op, the client terminal is in raw mode while it is attached.
+func (t *connTty) Start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-t.stopped:
+		t.stopped = make(chan struct{})
+	default:
+	}
+
+	return nil
+}
+
+// Stop is a no-op, the client restores its terminal when it is detached.
+func (t *connTty) Stop() error {
+	return nil
+}
+
+// Drain wakes up the pending reads.
+func (t *connTty) Drain() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-t.stopped:
+	default:
+		close(t.stopped)
+	}
+
+	return nil
+}
+
+func (t *connTty) NotifyResize(cb func()) {
+	t.mu.Lock()
+	t.onResize = cb
+	t.mu.Unlock()
+}
+
+func (t *connTty) WindowSize() (tcell.WindowSize, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.size, nil
+}
+
+// Read returns the input of the client, io.EOF once the tty is drained.
+// A client which is gone has no more input, the screen is replaced by the server.
+func (t *connTty) Read(b []byte) (int, error) {
+	if len(t.pending) == 0 {
+		t.mu.Lock()
+		stopped := t.stopped
+		t.mu.Unlock()
+
+		select {
+		case t.pending = <-t.input:
+		case <-stopped:
+			return 0, io.EOF
+		}
+	}
+
+	n := copy(b, t.pending)
+	t.pending = t.pending[n:]
+
+	return n, nil
+}
+
+// Write sends the output to the client, the output is dropped once the client is gone.
+func (t *connTty) Write(b []byte) (int, error) {
+	if t.conn == nil {
+		return len(b), nil
+	}
+
+	select {
+	case <-t.done:
+		return len(b), nil
+	default:
+	}
+
+	t.conn.Write(b)
+
+	return len(b), nil
+}
+
+func (t *connTty) Close() error {
+	return nil
+}
@@ -0,0 +1,287 @@
+package sop
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+)
+
+const (
+	// Directory in the config directory containing the SOP repositories
+	Dir = "sop"
+
+	// Repository synchronized by default
+	DefaultOwner = "openshift"
+	DefaultRepo  = "ops-sop"
+)
+
+// RemoteFormat is the format of the URL the repositories are cloned from, with the owner and the repository name.
+var RemoteFormat = "https://github.com/%s/%s.git"
+
+// ErrNotCached is returned when a repository hasn't been synchronized with kite sop sync.
+var ErrNotCached = errors.New("the SOP repository is not synchronized, run 'kite sop sync'")
+
+// Match is a SOP matching a search.
+type Match struct {
+	// Path of the SOP in the repository
+	Path string
+	// First line matching the most search terms, numbered from 1, and its text
+	Line int
+	Text string
+	// Score ranks the matches, the SOPs mentioning the terms the most come first
+	Score int
+}
+
+// RepoDir returns the directory the given repository is synchronized to.
+func RepoDir(owner string, repo string) (string, error) {
+	if owner == "" || repo == "" || strings.ContainsAny(owner+repo, `/\`) || strings.HasPrefix(owner, ".") || strings.HasPrefix(repo, ".") {
+		return "", fmt.Errorf("invalid repository '%s/%s'", owner, repo)
+	}
+
+	configDir, err := config.Dir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, Dir, owner, repo), nil
+}
+
+// Sync clones the given repository, or updates it if it has already been cloned.
+// Only the latest revision is fetched. The token authenticates the requests to GitHub, the output of git is written to out.
+func Sync(owner string, repo string, token string, out io.Writer) error {
+	dir, err := RepoDir(owner, repo)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git is required to synchronize the SOPs: %v", err)
+	}
+
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	// The token is passed in a header rather than in the remote URL, so that it isn't stored in the repository.
+	// The header is configured in the environment of git, its arguments can be read by the other users.
+	if token != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		env = append(env, "GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials)
+	}
+
+	git := func(args ...string) error {
+		cmd := exec.Command("git", args...)
+		cmd.Stdout = out
+		cmd.Stderr = out
+		cmd.Env = env
+		return cmd.Run()
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0700); err != nil {
+			return err
+		}
+
+		if err := git("clone", "--depth", "1", fmt.Sprintf(RemoteFormat, owner, repo), dir); err != nil {
+			return fmt.Errorf("cannot clone %s/%s: %v", owner, repo, err)
+		}
+
+		return nil
+	}
+
+	if err := git("-C", dir, "fetch", "--depth", "1", "origin"); err != nil {
+		return fmt.Errorf("cannot update %s/%s: %v", owner, repo, err)
+	}
+
+	if err := git("-C", dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+		return fmt.Errorf("cannot update %s/%s: %v", owner, repo, err)
+	}
+
+	return nil
+}
+
+// IsCached reports whether the given repository has been synchronized.
+func IsCached(owner string, repo string) bool {
+	dir, err := RepoDir(owner, repo)
+
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// Read returns the content of the file with the given path in the synchronized repository.
+func Read(owner string, repo string, path string) (string, error) {
+	if !IsCached(owner, repo) {
+		return "", ErrNotCached
+	}
+
+	dir, _ := RepoDir(owner, repo)
+	file := filepath.Join(dir, filepath.FromSlash(path))
+
+	// The files outside of the repository are never read
+	if rel, err := filepath.Rel(dir, file); err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid SOP path '%s'", path)
+	}
+
+	content, err := os.ReadFile(file)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// Search returns the markdown files of the synchronized repository containing all the words of the query, ignoring the case.
+// The best matches come first, at most limit matches are returned.
+func Search(owner string, repo string, query string, limit int) ([]Match, error) {
+	if !IsCached(owner, repo) {
+		return nil, ErrNotCached
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	dir, _ := RepoDir(owner, repo)
+	var matches []Match
+
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.EqualFold(filepath.Ext(file), ".md") {
+			return nil
+		}
+
+		content, err := os.ReadFile(file)
+
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(dir, file)
+
+		if match, ok := matchSOP(filepath.ToSlash(rel), string(content), terms); ok {
+			matches = append(matches, match)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Path < matches[j].Path
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches, nil
+}
+
+// matchSOP reports whether the SOP contains all the given lower-cased terms, and scores it.
+// The terms in the path of the SOP, e.g. the alert name, weigh more than the terms in its content.
+func matchSOP(path string, content string, terms []string) (Match, bool) {
+	match := Match{Path: path}
+	lowerContent := strings.ToLower(content)
+	lowerPath := strings.ToLower(path)
+
+	for _, term := range terms {
+		count := strings.Count(lowerContent, term)
+
+		if count == 0 && !strings.Contains(lowerPath, term) {
+			return Match{}, false
+		}
+
+		match.Score += count
+
+		if strings.Contains(lowerPath, term) {
+			match.Score += 10
+		}
+	}
+
+	best := 0
+
+	for i, line := range strings.Split(content, "\n") {
+		lowerLine := strings.ToLower(line)
+		found := 0
+
+		for _, term := range terms {
+			if strings.Contains(lowerLine, term) {
+				found++
+			}
+		}
+
+		if found > best {
+			best = found
+			match.Line = i + 1
+			match.Text = strings.TrimSpace(line)
+		}
+	}
+
+	return match, true
+}
+
+// Branch returns the branch the repository was synchronized from, false if it isn't synchronized.
+func Branch(owner string, repo string) (string, bool) {
+	dir, err := RepoDir(owner, repo)
+
+	if err != nil {
+		return "", false
+	}
+
+	head, err := os.ReadFile(filepath.Join(dir, ".git", "HEAD"))
+
+	if err != nil {
+		return "", false
+	}
+
+	ref := strings.TrimSpace(string(head))
+
+	if !strings.HasPrefix(ref, "ref: refs/heads/") {
+		return "", false
+	}
+
+	return strings.TrimPrefix(ref, "ref: refs/heads/"), true
+}
+
+// URL returns the GitHub URL of the SOP with the given path, on the branch the repository was synchronized from.
+func URL(owner string, repo string, path string) string {
+	branch, ok := Branch(owner, repo)
+
+	if !ok {
+		branch = "master"
+	}
+
+	return fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s", owner, repo, branch, path)
+}
@@ -0,0 +1,363 @@
+package sop
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+)
+
+const (
+	// RulesFile is the file of the config directory mapping the alerts to their SOP
+	RulesFile = "sop_rules.json"
+
+	// Minimum score of the SOPs matched by their name, the SOPs whose name only shares a word with the alert aren't candidates
+	minFuzzyScore = 50
+)
+
+// Rule maps the alerts matching it to a SOP. The alert, service and labels are regular expressions
+// matched against the alert fields, ignoring the case. The fields which aren't set match all the alerts.
+type Rule struct {
+	Alert   string `json:"alert,omitempty"`
+	Service string `json:"service,omitempty"`
+	Labels  string `json:"labels,omitempty"`
+
+	// SOP is the path of the SOP in the repository, e.g. "v4/alerts/KubeAPIDown.md", or its URL, e.g. "file:///srv/sop/KubeAPIDown.md"
+	SOP string `json:"sop"`
+}
+
+// Alert holds the alert fields the SOPs are resolved from.
+type Alert struct {
+	Name    string
+	Service string
+	Labels  string
+}
+
+// Candidate is a SOP which may document an alert.
+type Candidate struct {
+	// URL of the SOP
+	URL string
+	// Path of the SOP in the repository, empty for the SOPs of the rules given by their URL
+	Path string
+	// Rule reports whether the SOP comes from a rule, rather than from its name
+	Rule bool
+	// Score of the SOPs matched by their name, from 0 to 100
+	Score int
+}
+
+var (
+	// alertNameLabel finds the name of the alert in its labels, e.g. "alertname=KubeAPIDown" or "alertname: KubeAPIDown"
+	alertNameLabel  = regexp.MustCompile(`alertname["']?\s*[:=]\s*["']?([\w.-]+)`)
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ReadRules returns the rules of the rules file, none if it doesn't exist.
+func ReadRules() ([]Rule, error) {
+	configDir, err := config.Dir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	file := filepath.Join(configDir, RulesFile)
+	data, err := os.ReadFile(file)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the SOP rules '%s': %v", file, err)
+	}
+
+	var rules []Rule
+
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse the SOP rules '%s': %v", file, err)
+	}
+
+	for i, rule := range rules {
+		if rule.SOP == "" {
+			return nil, fmt.Errorf("the SOP rule %d of '%s' has no sop", i+1, file)
+		}
+
+		for _, pattern := range []string{rule.Alert, rule.Service, rule.Labels} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s' of the SOP rule %d of '%s': %v", pattern, i+1, file, err)
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// Match reports whether the alert matches the rule.
+func (r Rule) Match(alert Alert) bool {
+	for _, field := range []struct{ pattern, value string }{
+		{r.Alert, alert.Name},
+		{r.Service, alert.Service},
+		{r.Labels, alert.Labels},
+	} {
+		if field.pattern == "" {
+			continue
+		}
+
+		if matched, err := regexp.MatchString("(?i)"+field.pattern, field.value); err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Resolve returns the SOPs which may document the alert, the best candidates first, at most limit candidates.
+// The SOPs of the rules matching the alert come first, in the order of the rules file.
+// They are followed by the SOPs of the synchronized repository whose name is close to the alert name.
+func Resolve(owner string, repo string, alert Alert, rules []Rule, limit int) []Candidate {
+	var candidates []Candidate
+	seen := map[string]bool{}
+
+	for _, rule := range rules {
+		if !rule.Match(alert) {
+			continue
+		}
+
+		candidate := Candidate{Rule: true}
+
+		// The local files are given by their file:// URL, the paths are the ones of the repository
+		if strings.Contains(rule.SOP, "://") {
+			candidate.URL = rule.SOP
+		} else {
+			candidate.Path = strings.TrimPrefix(rule.SOP, "/")
+			candidate.URL = URL(owner, repo, candidate.Path)
+		}
+
+		if !seen[candidate.URL] {
+			seen[candidate.URL] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	var matches []Candidate
+
+	for _, file := range index(owner, repo) {
+		if score := fuzzyScore(alert, file); score >= minFuzzyScore {
+			if url := URL(owner, repo, file); !seen[url] {
+				matches = append(matches, Candidate{URL: url, Path: file, Score: score})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Path < matches[j].Path
+	})
+
+	candidates = append(candidates, matches...)
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	return candidates
+}
+
+// index returns the paths of the markdown files of the synchronized repository, none if it isn't synchronized
+func index(owner string, repo string) []string {
+	if !IsCached(owner, repo) {
+		return nil
+	}
+
+	dir, _ := RepoDir(owner, repo)
+	var paths []string
+
+	filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.EqualFold(filepath.Ext(file), ".md") {
+			rel, _ := filepath.Rel(dir, file)
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+
+		return nil
+	})
+
+	return paths
+}
+
+// alertNames returns the names the alert may be documented under: the words of its name, e.g. "KubeAPIDown" in
+// "KubeAPIDown CRITICAL (1)", and the alertname of its labels
+func alertNames(alert Alert) []string {
+	var names []string
+
+	for _, match := range alertNameLabel.FindAllStringSubmatch(alert.Labels, -1) {
+		names = append(names, match[1])
+	}
+
+	names = append(names, strings.Fields(alert.Name)...)
+	names = append(names, alert.Name)
+
+	return names
+}
+
+// fuzzyScore scores how close the name of the SOP is to the alert name, from 0 to 100.
+// The SOP named after the alert scores 100, the SOPs whose name contains the alert name or is a few edits away score less.
+// The services and the labels mentioning the directory of the SOP, e.g. "etcd", add to the score.
+func fuzzyScore(alert Alert, file string) int {
+	name := normalize(strings.TrimSuffix(path.Base(file), path.Ext(file)))
+
+	if len(name) < 3 {
+		return 0
+	}
+
+	best := 0
+
+	for _, alertName := range alertNames(alert) {
+		alertName = normalize(alertName)
+
+		if len(alertName) < 3 {
+			continue
+		}
+
+		score := 0
+
+		switch {
+		case alertName == name:
+			score = 100
+		case len(alertName) >= 6 && len(name) >= 6 && (strings.Contains(name, alertName) || strings.Contains(alertName, name)):
+			score = 60 + 30*min(len(name), len(alertName))/max(len(name), len(alertName))
+		default:
+			distance := levenshtein(alertName, name)
+			similarity := 100 - 100*distance/max(len(name), len(alertName))
+
+			if similarity >= 70 {
+				score = similarity
+			}
+		}
+
+		best = max(best, score)
+	}
+
+	best = max(best, sharedWords(alert.Name, file))
+
+	if best == 0 {
+		return 0
+	}
+
+	// The context of the alert favors the SOPs of its component
+	context := strings.ToLower(alert.Service + " " + alert.Labels)
+
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if len(dir) >= 4 && strings.Contains(context, strings.ToLower(dir)) {
+			best += 5
+		}
+	}
+
+	return min(best, 100)
+}
+
+// sharedWords scores the words shared by the alert name and the SOP name, e.g. "Kube", "API" and "Down"
+func sharedWords(alertName string, file string) int {
+	alertWords := words(alertName)
+	fileWords := words(strings.TrimSuffix(path.Base(file), path.Ext(file)))
+
+	if len(alertWords) == 0 || len(fileWords) == 0 {
+		return 0
+	}
+
+	shared := 0
+
+	for _, word := range fileWords {
+		for _, alertWord := range alertWords {
+			if word == alertWord {
+				shared++
+				break
+			}
+		}
+	}
+
+	return 80 * shared / max(len(alertWords), len(fileWords))
+}
+
+// words splits the name into its lower-cased words, at the case changes and the separators, e.g. "KubeAPIDown" into kube, api and down
+func words(name string) []string {
+	var result []string
+	var word []rune
+	runes := []rune(name)
+
+	flush := func() {
+		if len(word) > 0 {
+			result = append(result, strings.ToLower(string(word)))
+			word = nil
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		// A new word starts at an upper-case letter following a lower-case one, or preceding one in an acronym
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+			flush()
+		}
+
+		word = append(word, r)
+	}
+
+	flush()
+
+	return result
+}
+
+// normalize lower-cases the name and removes its separators, so that "kube-api-down" and "KubeAPIDown" are equal
+func normalize(name string) string {
+	return nonAlphanumeric.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// levenshtein returns the number of edits turning a into b
+func levenshtein(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
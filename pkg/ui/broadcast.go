@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+// toggleBroadcast adds the active slide to the slides whose input is broadcast, or removes it.
+// The keys typed in the terminal of a broadcast slide are also sent to the terminals of the other broadcast slides.
+func (tui *TUI) toggleBroadcast() {
+	slide := tui.Mux.Active()
+
+	if slide == nil {
+		return
+	}
+
+	if slide.broadcast {
+		tui.Mux.SetBroadcast(slide, false)
+		utils.InfoLogger.Printf("Input of '%s' is no longer broadcast", slide.title)
+		return
+	}
+
+	if tui.focusedTerminal() == nil {
+		utils.ErrorLogger.Println("Only the input of the terminal slides can be broadcast")
+		return
+	}
+
+	tui.Mux.SetBroadcast(slide, true)
+	utils.InfoLogger.Printf("Input of '%s' is broadcast to %d other slide(s)", slide.title, len(tui.Mux.BroadcastSlides())-1)
+}
+
+// broadcastTargets returns the terminals the input of the terminal is broadcast to.
+// The input is only broadcast from a broadcast slide, to the focused terminal of the other broadcast slides.
+func (t *Terminal) broadcastTargets() []*Terminal {
+	if t.tui == nil || t.tui.Mux == nil {
+		return nil
+	}
+
+	slide := t.tui.Mux.SlideOf(t)
+
+	if slide == nil || !slide.broadcast {
+		return nil
+	}
+
+	var targets []*Terminal
+
+	for _, other := range t.tui.Mux.BroadcastSlides() {
+		if other == slide || other.view.FocusedPane() == nil {
+			continue
+		}
+
+		// The terminals whose scrollback is browsed don't receive any input
+		if term, ok := other.view.FocusedPane().primitive.(*Terminal); ok && term.running && term.copyMode == nil {
+			targets = append(targets, term)
+		}
+	}
+
+	return targets
+}
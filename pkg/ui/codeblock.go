@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/markdown"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// Buttons of the code block confirmation
+const (
+	sendCodeBlockButton   = "Send"
+	cancelCodeBlockButton = "Cancel"
+)
+
+// terminalSlides returns the slides whose focused pane is a running terminal, and their terminal.
+func (tui *TUI) terminalSlides() ([]*Slide, []*Terminal) {
+	var slides []*Slide
+	var terms []*Terminal
+
+	for _, slide := range tui.Mux.Slides() {
+		if slide.view.FocusedPane() == nil {
+			continue
+		}
+
+		if term, ok := slide.view.FocusedPane().primitive.(*Terminal); ok && term.running {
+			slides = append(slides, slide)
+			terms = append(terms, term)
+		}
+	}
+
+	return slides, terms
+}
+
+// sendCodeBlock lets the user pick the terminal slide the code block is sent to.
+// The placeholders of the selected alert fields are substituted in the code, which is sent after a confirmation.
+func (tui *TUI) sendCodeBlock(block markdown.CodeBlock) {
+	slides, terms := tui.terminalSlides()
+
+	if len(slides) == 0 {
+		utils.ErrorLogger.Println("No terminal slide to send the code block to, open one first")
+		return
+	}
+
+	code := SubstitutePlaceholders(block.Code, tui.SelectedAlert)
+
+	var items []listItem
+
+	for i, slide := range slides {
+		slide, term := slide, terms[i]
+		items = append(items, listItem{
+			text:     fmt.Sprintf("%d %s", tui.Mux.IndexOf(slide)+1, tview.Escape(slide.title)),
+			selected: func() { tui.confirmCodeBlock(code, slide, term) },
+		})
+	}
+
+	tui.showListModal(TerminalListPageTitle, "Send the Code Block to", 50, items, 0)
+}
+
+// confirmCodeBlock displays the code and sends it to the terminal once confirmed.
+// The code is pasted in the terminal without being run, the user reviews it in the slide before pressing Enter.
+func (tui *TUI) confirmCodeBlock(code string, slide *Slide, term *Terminal) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Send to '%s'?\n\n%s", tview.Escape(slide.title), tview.Escape(code))).
+		AddButtons([]string{sendCodeBlockButton, cancelCodeBlockButton}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			tui.hideModal(CodeBlockPageTitle)
+
+			if buttonLabel != sendCodeBlockButton {
+				return
+			}
+
+			if err := term.PasteCode(code); err != nil {
+				utils.ErrorLogger.Printf("Cannot send the code block to '%s': %v", slide.title, err)
+				return
+			}
+
+			tui.Mux.Switch(slide)
+			utils.InfoLogger.Printf("Code block sent to '%s', review it and press Enter to run it", slide.title)
+		})
+
+	tui.Root.AddPage(CodeBlockPageTitle, modal, true, true)
+	tui.App.SetFocus(modal)
+}
+
+// codeBlockIndex returns the index of the code block displayed in the given region, false for the other regions.
+func codeBlockIndex(region string) (int, bool) {
+	if !strings.HasPrefix(region, "c") {
+		return 0, false
+	}
+
+	index, err := strconv.Atoi(region[1:])
+	return index, err == nil
+}
@@ -1,11 +1,9 @@
 package ui
 
-import "github.com/gdamore/tcell/v2"
-
 const (
 
 	// Text Format
-	TitleFmt = " [lightcyan::b]%s "
+	TitleFmt = " [::b]%s "
 
 	// Table Titles
 	AlertsTableTitle          = "[ ALERTS ]"
@@ -18,6 +16,7 @@ const (
 	OncallTableTitle          = "ONCALL"
 	NextOncallTableTitle      = "[ NEXT ONCALL ]"
 	AllTeamsOncallTableTitle  = "[ ALL TEAMS ONCALL ]"
+	SOPSearchTableTitle       = "[ SOP SEARCH ]"
 
 	// Page Titles
 	AlertsPageTitle          = "Alerts"
@@ -33,25 +32,32 @@ const (
 	NextOncallPageTitle      = "Next Oncall"
 	AllTeamsOncallPageTitle  = "All Teams Oncall"
 	ServiceLogsPageTitle     = "Service Logs"
+	MainPageTitle            = "Main"
+	HelpPageTitle            = "Help"
+	SlideListPageTitle       = "Slide List"
+	RecordingListPageTitle   = "Recording List"
+	LauncherListPageTitle    = "Launcher List"
+	LinkListPageTitle        = "Link List"
+	TerminalListPageTitle    = "Terminal List"
+	CodeBlockPageTitle       = "Send Code Block"
+	SOPSearchPageTitle       = "SOP Search"
+	SOPCandidatePageTitle    = "SOP Candidates"
 
 	//Footer
-	FooterText                = "[Esc] Go Back"
-	FooterTextAlerts          = "[R] Refresh Alerts | [1] Acknowledged Incidents | [2] Trigerred Incidents\n" + FooterText
-	FooterTextTrigerredAlerts = "[1] Acknowledged Incidents | [2] Trigerred Incidents\n" + FooterText
-	FooterTextAckIncidents    = "[ENTER] View Incident \n " + FooterText
-	FooterTextIncidents       = "[ENTER] Select Incident | [CTRL+A] Acknowledge Incidents | [V] View Incident Alerts\n" + FooterText
-	FooterTextOncall          = "[N] Your Next Oncall Schedule | [A] All Teams Oncall | [<-] Previous Layer Oncall | [->] Next Layer Oncall \n" + FooterText
-	TerminalFooterText        = "[CTRL + N] Next Slide | [CTRL + P] Previous Slide | [CTRL + S] Add Slide | [CTRL + E] Exit Slide | [CTRL + B] + [Num] Change to Slide with [Num]  | [CTRL + Q] Quit "
-	TerminalFooterEscapeState = "Enter the Slide Number to Switch To : "
-
-	// Colors
-	TableTitleColor                = tcell.ColorLightCyan
-	BorderColor                    = tcell.ColorLightGray
-	FooterTextColor                = tcell.ColorGray
-	InfoTextColor                  = tcell.ColorLightSlateGray
-	ErrorTextColor                 = tcell.ColorRed
-	PromptTextColor                = tcell.ColorLightGreen
-	LoggerTextColor                = tcell.ColorGreen
-	TerminalFooterTextColor        = tcell.ColorGreen
-	TerminalFooterEscapeStateColor = tcell.ColorDarkGreen
+	TerminalFooterEscapeState = "Enter the Slide Number or Name to Switch To : "
+	TerminalFooterPrefixState = "Waiting for a slide command, press [Num] to switch to the slide with [Num] : "
+	TerminalFooterJoinState   = "Enter the Slide Number or Name to Move to a New Pane : "
+	TerminalFooterRenameState = "Enter the New Slide Name : "
+	TerminalFooterSearchState = "Search the Scrollback : "
+	TerminalFooterSOPState    = "Search the SOPs : "
+
+	// Maximum height of the slide list, including its border
+	SlideListMaxHeight = 22
+
+	// Maximum number of SOPs listed by a search
+	SOPSearchLimit = 50
+
+	// Maximum number of SOPs proposed for an alert which doesn't mention its SOP
+	SOPCandidateLimit = 10
+
 )
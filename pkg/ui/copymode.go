@@ -0,0 +1,379 @@
+package ui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+// copyMode is the state of a terminal whose scrollback is browsed with the keyboard.
+// The text is the scrollback and the screen when the copy mode is entered, the output of the program isn't displayed meanwhile.
+// The cursor and selection positions are line and character indexes in the text.
+type copyMode struct {
+	lines []string
+	row   int
+	col   int
+
+	// First line displayed
+	top int
+
+	selecting bool
+	startRow  int
+	startCol  int
+
+	search string
+}
+
+func newCopyMode(lines []string, row int, col int) *copyMode {
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+
+	c := &copyMode{lines: lines, top: row}
+	c.moveTo(row, col)
+	return c
+}
+
+// moveTo moves the cursor to the given position, the position is kept within the text.
+func (c *copyMode) moveTo(row int, col int) {
+	c.row = clamp(row, 0, len(c.lines)-1)
+	c.col = clamp(col, 0, utf8.RuneCountInString(c.lines[c.row])-1)
+}
+
+// clamp returns the value within low and high, low wins when high is lower.
+func clamp(value int, low int, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+	return value
+}
+
+// handleKey moves the cursor with the arrows, the vi keys, the page keys, Home and End.
+func (c *copyMode) handleKey(event *tcell.EventKey, pageHeight int) {
+	switch event.Key() {
+	case tcell.KeyUp:
+		c.moveTo(c.row-1, c.col)
+	case tcell.KeyDown:
+		c.moveTo(c.row+1, c.col)
+	case tcell.KeyLeft:
+		c.moveTo(c.row, c.col-1)
+	case tcell.KeyRight:
+		c.moveTo(c.row, c.col+1)
+	case tcell.KeyPgUp:
+		c.moveTo(c.row-pageHeight, c.col)
+	case tcell.KeyPgDn:
+		c.moveTo(c.row+pageHeight, c.col)
+	case tcell.KeyHome:
+		c.moveTo(c.row, 0)
+	case tcell.KeyEnd:
+		c.moveTo(c.row, utf8.RuneCountInString(c.lines[c.row]))
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'k':
+			c.moveTo(c.row-1, c.col)
+		case 'j':
+			c.moveTo(c.row+1, c.col)
+		case 'h':
+			c.moveTo(c.row, c.col-1)
+		case 'l':
+			c.moveTo(c.row, c.col+1)
+		case '0':
+			c.moveTo(c.row, 0)
+		case '$':
+			c.moveTo(c.row, utf8.RuneCountInString(c.lines[c.row]))
+		case 'g':
+			c.moveTo(0, 0)
+		case 'G':
+			c.moveTo(len(c.lines)-1, 0)
+		}
+	}
+}
+
+// startSelection starts selecting the text from the cursor, or clears the selection.
+func (c *copyMode) startSelection() {
+	c.selecting = !c.selecting
+	c.startRow = c.row
+	c.startCol = c.col
+}
+
+// selectionBounds returns the first and last selected positions, in the order of the text.
+func (c *copyMode) selectionBounds() (int, int, int, int) {
+	if c.startRow < c.row || c.startRow == c.row && c.startCol <= c.col {
+		return c.startRow, c.startCol, c.row, c.col
+	}
+	return c.row, c.col, c.startRow, c.startCol
+}
+
+// isSelected reports whether the character at the given position is selected.
+func (c *copyMode) isSelected(row int, col int) bool {
+	if !c.selecting {
+		return false
+	}
+
+	firstRow, firstCol, lastRow, lastCol := c.selectionBounds()
+
+	return (row > firstRow || row == firstRow && col >= firstCol) && (row < lastRow || row == lastRow && col <= lastCol)
+}
+
+// selection returns the selected text, or the line under the cursor when nothing is selected.
+func (c *copyMode) selection() string {
+	if !c.selecting {
+		return c.lines[c.row]
+	}
+
+	firstRow, firstCol, lastRow, lastCol := c.selectionBounds()
+	var lines []string
+
+	for row := firstRow; row <= lastRow; row++ {
+		line := []rune(c.lines[row])
+		start, end := 0, len(line)
+
+		if row == firstRow {
+			start = clamp(firstCol, 0, len(line))
+		}
+
+		if row == lastRow {
+			end = clamp(lastCol+1, start, len(line))
+		}
+
+		lines = append(lines, string(line[start:end]))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// matches returns the positions of the characters where the search matches the given line, ignoring the case.
+func (c *copyMode) matches(row int) []int {
+	if c.search == "" {
+		return nil
+	}
+
+	line := strings.ToLower(c.lines[row])
+	search := strings.ToLower(c.search)
+	var cols []int
+
+	for offset := 0; offset < len(line); {
+		index := strings.Index(line[offset:], search)
+
+		if index < 0 {
+			break
+		}
+
+		cols = append(cols, utf8.RuneCountInString(line[:offset+index]))
+		_, size := utf8.DecodeRuneInString(line[offset+index:])
+		offset += index + size
+	}
+
+	return cols
+}
+
+// find moves the cursor to the next match of the search, towards the older lines when backward.
+// It returns false if there is no more match in that direction.
+func (c *copyMode) find(backward bool) bool {
+	step := 1
+	if backward {
+		step = -1
+	}
+
+	for row := c.row; row >= 0 && row < len(c.lines); row += step {
+		cols := c.matches(row)
+
+		for i := range cols {
+			col := cols[i]
+			if backward {
+				col = cols[len(cols)-1-i]
+			}
+
+			if row != c.row || backward && col < c.col || !backward && col > c.col {
+				c.moveTo(row, col)
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// draw displays the text around the cursor in the given area, with the selection and the matches of the search highlighted.
+func (c *copyMode) draw(screen tcell.Screen, x int, y int, width int, height int, theme *Theme, hasFocus bool) {
+	if height <= 0 || width <= 0 {
+		return
+	}
+
+	// Keep the cursor visible
+	if c.row < c.top {
+		c.top = c.row
+	} else if c.row >= c.top+height {
+		c.top = c.row - height + 1
+	}
+
+	c.top = clamp(c.top, 0, len(c.lines)-1)
+
+	textStyle := tcell.StyleDefault
+	selectedStyle := textStyle.Reverse(true)
+	matchStyle := textStyle.Background(theme.Selected)
+	searchLength := utf8.RuneCountInString(c.search)
+
+	for line := 0; line < height && c.top+line < len(c.lines); line++ {
+		row := c.top + line
+		highlighted := make(map[int]bool)
+
+		for _, col := range c.matches(row) {
+			for i := 0; i < searchLength; i++ {
+				highlighted[col+i] = true
+			}
+		}
+
+		cellX := 0
+
+		for col, r := range []rune(c.lines[row]) {
+			runeWidth := runewidth.RuneWidth(r)
+
+			if cellX+runeWidth > width {
+				break
+			}
+
+			style := textStyle
+
+			if c.isSelected(row, col) {
+				style = selectedStyle
+			} else if highlighted[col] {
+				style = matchStyle
+			}
+
+			screen.SetContent(x+cellX, y+line, r, nil, style)
+
+			if row == c.row && col == c.col && hasFocus {
+				screen.ShowCursor(x+cellX, y+line)
+			}
+
+			cellX += runeWidth
+		}
+
+		if row == c.row && hasFocus && c.col >= utf8.RuneCountInString(c.lines[row]) {
+			screen.ShowCursor(x+clamp(cellX, 0, width-1), y+line)
+		}
+	}
+
+	position := fmt.Sprintf("[Copy Mode %d/%d]", c.row+1, len(c.lines))
+
+	if len(position) <= width {
+		for i, r := range position {
+			screen.SetContent(x+width-len(position)+i, y, r, nil, selectedStyle)
+		}
+	}
+}
+
+// initClipboard enables the copy to the clipboard of the terminal kite runs in, unless disabled in the config.
+func (tui *TUI) initClipboard(cfg *config.Config) {
+	tui.OSC52 = cfg == nil || cfg.OSC52 == nil || *cfg.OSC52
+}
+
+// focusedTerminal returns the terminal of the focused pane of the active slide, nil if it isn't a terminal.
+func (tui *TUI) focusedTerminal() *Terminal {
+	slide := tui.Mux.Active()
+
+	if slide == nil || slide.view.FocusedPane() == nil {
+		return nil
+	}
+
+	term, _ := slide.view.FocusedPane().primitive.(*Terminal)
+	return term
+}
+
+// copyModeCommands returns the commands of a terminal in copy mode, the cursor is moved by the terminal itself.
+func (tui *TUI) copyModeCommands(term *Terminal) []command {
+	return []command{
+		{action: ActionStartSelection, handler: func() { term.copyMode.startSelection() }},
+		{action: ActionCopySelection, handler: func() { tui.copySelection(term) }},
+		{action: ActionSearch, handler: func() { tui.searchScrollback(term) }},
+		{action: ActionNextMatch, handler: func() { tui.findMatch(term, true) }},
+		{action: ActionPreviousMatch, handler: func() { tui.findMatch(term, false) }},
+		{action: ActionExitCopyMode, handler: term.exitCopyMode},
+		{action: ActionHelp, handler: tui.showHelp},
+	}
+}
+
+// enterCopyMode lets the scrollback of the focused terminal be browsed, selected and searched
+func (tui *TUI) enterCopyMode() {
+	term := tui.focusedTerminal()
+
+	if term == nil {
+		utils.ErrorLogger.Println("Copy mode is only available in terminal slides")
+		return
+	}
+
+	term.enterCopyMode()
+}
+
+// copySelection copies the selected text to the kite clipboard and leaves the copy mode.
+// The text is also copied to the clipboard of the terminal kite runs in, with the OSC 52 escape sequence.
+func (tui *TUI) copySelection(term *Terminal) {
+	tui.Clipboard = term.copyMode.selection()
+
+	if tui.OSC52 {
+		setClipboard(term.screen, tui.Clipboard)
+	}
+
+	utils.InfoLogger.Printf("Copied %d characters", utf8.RuneCountInString(tui.Clipboard))
+	term.exitCopyMode()
+}
+
+// searchScrollback prompts for a text searched towards the older lines of the terminal
+func (tui *TUI) searchScrollback(term *Terminal) {
+	tui.prompt(TerminalFooterSearchState, func(input string) {
+		if term.copyMode == nil {
+			return
+		}
+
+		term.copyMode.search = input
+		tui.findMatch(term, true)
+	})
+}
+
+// findMatch moves to the next match of the search, towards the older lines when backward.
+func (tui *TUI) findMatch(term *Terminal, backward bool) {
+	if term.copyMode.search == "" {
+		return
+	}
+
+	if !term.copyMode.find(backward) {
+		utils.InfoLogger.Printf("No more matches for '%s'", term.copyMode.search)
+	}
+}
+
+// paste sends the kite clipboard to the program running in the focused terminal
+func (tui *TUI) paste() {
+	term := tui.focusedTerminal()
+
+	if term == nil || tui.Clipboard == "" {
+		return
+	}
+
+	term.paste(tui.Clipboard)
+
+	for _, target := range term.broadcastTargets() {
+		target.paste(tui.Clipboard)
+	}
+}
+
+// setClipboard sets the clipboard of the terminal showing the given screen with the OSC 52 escape sequence.
+// Terminals which don't support it ignore the sequence.
+func setClipboard(screen tcell.Screen, text string) {
+	if screen == nil {
+		return
+	}
+
+	if tty, ok := screen.Tty(); ok {
+		fmt.Fprintf(tty, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
-	"github.com/gdamore/tcell/v2"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/ocm"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
@@ -17,17 +16,16 @@ func (tui *TUI) SetAlertsTableEvents(alerts []pdcli.Alert) {
 	tui.Table.SetSelectedFunc(func(row int, column int) {
 		var alertData string
 
-		alertID := tui.Table.GetCell(row, 1).Text
-
-		for _, alert := range alerts {
-			if alertID == alert.AlertID {
-				utils.InfoLogger.Printf("GET: fetching alert metadata for alert ID: %s", alertID)
-				alertData = pdcli.ParseAlertMetaData(alert)
-				tui.ClusterName = alert.ClusterName
-				tui.ClusterID = alert.ClusterID
-				tui.SOPLink = alert.Sop
-				break
-			}
+		// The table rows follow the order of the alerts, the first row being the header
+		if row > 0 && row <= len(alerts) {
+			alert := alerts[row-1]
+			utils.InfoLogger.Printf("GET: fetching alert metadata for alert ID: %s", alert.AlertID)
+			alertData = pdcli.ParseAlertMetaData(alert)
+			tui.ClusterName = alert.ClusterName
+			tui.ClusterID = alert.ClusterID
+			tui.IncidentID = alert.IncidentID
+			tui.SelectedAlert = &alert
+			tui.SOPLink = alert.Sop
 		}
 
 		tui.AlertMetadata.SetText(alertData)
@@ -35,8 +33,7 @@ func (tui *TUI) SetAlertsTableEvents(alerts []pdcli.Alert) {
 
 		// Do not prompt for cluster login if there's no cluster ID associated with the alert (v3 clusters)
 		if tui.ClusterID != "N/A" && tui.ClusterID != "" && alertData != "" {
-			secondaryWindowText := fmt.Sprintf("Press 'Y' to log into the cluster: %s\nPress 'S' to view the SOP\nPress 'L' to view service logs", tui.ClusterName)
-			tui.SecondaryWindow.SetText(secondaryWindowText).SetTextColor(PromptTextColor)
+			tui.SecondaryWindow.SetText(tui.clusterPromptText(tui.ClusterName)).SetTextColor(tui.Theme.PromptText)
 		}
 	})
 }
@@ -50,6 +47,7 @@ func (tui *TUI) SetAckTableEvents() {
 		client, _ := client.NewClient().Connect()
 		incidentID := tui.IncidentsTable.GetCell(row, 0).Text
 		incident.APIObject.ID = incidentID
+		tui.IncidentID = incidentID
 		var clusterName string
 		var alertData string
 
@@ -61,6 +59,7 @@ func (tui *TUI) SetAckTableEvents() {
 				alertData = pdcli.ParseAlertMetaData(alert)
 				clusterName = alert.ClusterName
 				tui.ClusterID = alert.ClusterID
+				tui.SelectedAlert = &alert
 				break
 			}
 		}
@@ -68,7 +67,6 @@ func (tui *TUI) SetAckTableEvents() {
 			alertData = pdcli.ParseAlertMetaData(Alert)
 			tui.AlertMetadata.SetText(alertData)
 			tui.Pages.AddAndSwitchToPage(AckAlertDataPage, tui.AlertMetadata, true)
-			tui.Footer.SetText(FooterText)
 
 		} else {
 			tui.SetAlertsTableEvents(alerts)
@@ -77,8 +75,7 @@ func (tui *TUI) SetAckTableEvents() {
 		}
 		// Do not prompt for cluster login if there's no cluster ID associated with the alert (v3 clusters)
 		if tui.ClusterID != "N/A" && tui.ClusterID != "" && alertData != "" {
-			secondaryWindowText := fmt.Sprintf("Press 'Y' to log into the cluster: %s\nPress 'S' to view the SOP\nPress 'L' to view service logs", clusterName)
-			tui.SecondaryWindow.SetText(secondaryWindowText)
+			tui.SecondaryWindow.SetText(tui.clusterPromptText(clusterName))
 		}
 	})
 }
@@ -90,11 +87,11 @@ func (tui *TUI) SetIncidentsTableEvents() {
 	tui.IncidentsTable.SetSelectedFunc(func(row, column int) {
 		incidentID := tui.IncidentsTable.GetCell(row, 0).Text
 		if _, ok := tui.SelectedIncidents[incidentID]; !ok || tui.SelectedIncidents[incidentID] == "" {
-			tui.IncidentsTable.GetCell(row, 0).SetTextColor(tcell.ColorLimeGreen)
+			tui.IncidentsTable.GetCell(row, 0).SetTextColor(tui.Theme.Selected)
 			tui.SelectedIncidents[incidentID] = incidentID
 			utils.InfoLogger.Printf("Selected incident: %s", incidentID)
 		} else {
-			tui.IncidentsTable.GetCell(row, 0).SetTextColor(tcell.ColorWhite)
+			tui.IncidentsTable.GetCell(row, 0).SetTextColor(tui.Theme.Text)
 			tui.SelectedIncidents[incidentID] = ""
 			utils.InfoLogger.Printf("Deselected incident: %s", incidentID)
 		}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// showModal displays the given primitive centered on top of the multiplexer.
+func (tui *TUI) showModal(name string, p tview.Primitive, width int, height int) {
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	tui.Root.AddPage(name, modal, true, true)
+	tui.App.SetFocus(p)
+}
+
+// hideModal removes the given modal and gives the focus back to the multiplexer.
+func (tui *TUI) hideModal(name string) {
+	if name == tui.listModal {
+		tui.listModal = ""
+	}
+
+	tui.Root.RemovePage(name)
+	tui.App.SetFocus(tui.TerminalLayout)
+}
+
+// listItem is an item of a list modal, with an optional secondary text.
+type listItem struct {
+	text      string
+	secondary string
+	selected  func()
+}
+
+// showListModal displays the items in a list modal, which is closed when an item is selected or with the back key.
+// The list is scrolled within the modal when it is higher than SlideListMaxHeight.
+func (tui *TUI) showListModal(name string, title string, width int, items []listItem, current int) {
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(" " + title + " ")
+	height := len(items) + 2
+
+	// The items with a secondary text take two lines
+	for _, item := range items {
+		if item.secondary != "" {
+			list.ShowSecondaryText(true)
+			height = 2*len(items) + 2
+			break
+		}
+	}
+
+	for _, item := range items {
+		item := item
+		list.AddItem(item.text, item.secondary, 0, func() {
+			tui.hideModal(name)
+			item.selected()
+		})
+	}
+
+	if height > SlideListMaxHeight {
+		height = SlideListMaxHeight
+	}
+
+	list.SetCurrentItem(current)
+	tui.listModal = name
+	tui.showModal(name, list, width, height)
+}
+
+// showHelp displays the key bindings valid for the active slide and page.
+// The bindings are generated from the commands registered for the input handlers.
+func (tui *TUI) showHelp() {
+	table := tview.NewTable().SetSelectable(false, false)
+	row := 0
+
+	addSection := func(title string, commands []command) {
+		if len(commands) == 0 {
+			return
+		}
+
+		if row > 0 {
+			row++
+		}
+
+		table.SetCell(row, 0, tview.NewTableCell(title).SetTextColor(tui.Theme.Title).SetAttributes(tcell.AttrBold))
+		row++
+
+		for _, cmd := range commands {
+			table.SetCell(row, 0, tview.NewTableCell(tview.Escape(tui.Keymap.Key(cmd.action))).SetTextColor(tui.Theme.PromptText))
+			table.SetCell(row, 1, tview.NewTableCell(tview.Escape(tui.Keymap.Description(cmd.action))).SetExpansion(1))
+			row++
+		}
+	}
+
+	addSection("Slides", tui.muxCommands())
+	addSection("Panes", tui.paneCommands())
+
+	if tui.Keymap.Prefix != nil {
+		prefix := tui.Keymap.Prefix.String()
+		table.SetCell(row, 0, tview.NewTableCell(tview.Escape(prefix+" [Num]")).SetTextColor(tui.Theme.PromptText))
+		table.SetCell(row, 1, tview.NewTableCell(tview.Escape("Change to Slide with [Num]")))
+		table.SetCell(row+1, 0, tview.NewTableCell(prefix+" "+prefix).SetTextColor(tui.Theme.PromptText))
+		table.SetCell(row+1, 1, tview.NewTableCell("Send the Prefix Key to the Slide"))
+		table.SetCell(row+2, 0, tview.NewTableCell(prefix+" "+tui.Keymap.bindings[ActionHelp].String()).SetTextColor(tui.Theme.PromptText))
+		table.SetCell(row+2, 1, tview.NewTableCell("Help"))
+		row += 3
+	}
+
+	title := "Slide"
+	if tui.isKitePaneFocused() {
+		title, _ = tui.Pages.GetFrontPage()
+	}
+
+	addSection(title, tui.slideCommands())
+
+	table.
+		SetBorder(true).
+		SetBorderColor(tui.Theme.Border).
+		SetBorderPadding(1, 1, 2, 2).
+		SetTitle(fmt.Sprintf(TitleFmt, "[ HELP ]"))
+
+	tui.showModal(HelpPageTitle, table, 70, row+4)
+}
@@ -15,318 +15,573 @@ import (
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
 )
 
+// command binds an action to the handler run when its key is pressed.
+// Commands with a passthrough key are handled by the focused primitive itself,
+// the bound key is translated into the passthrough key before it is forwarded.
+type command struct {
+	action      Action
+	handler     func()
+	passthrough tcell.Key
+}
+
 // initKeyboard initializes the keyboard event handlers for all the TUI components.
+// All the key bindings are dispatched from the commands registered for the active slide and page.
 func (tui *TUI) initKeyboard() {
-	var isEscapeSequence bool = false
 	tui.App.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if isEscapeSequence {
-			if event.Rune() >= '0' && event.Rune() <= '9' {
-				slideNum, _ := strconv.Atoi(string(event.Rune()))
-				SwitchToSlide(slideNum, tui)
+		if tui.Root.HasPage(HelpPageTitle) {
+			switch {
+			case tui.Keymap.Matches(ActionBack, event) || tui.Keymap.Matches(ActionHelp, event) || event.Key() == tcell.KeyEnter:
+				tui.hideModal(HelpPageTitle)
+			case event.Key() == tcell.KeyUp || event.Key() == tcell.KeyDown || event.Key() == tcell.KeyPgUp || event.Key() == tcell.KeyPgDn:
+				// The help can be scrolled when it doesn't fit on the screen
+				return event
 			}
-			tui.TerminalFixedFooter.
-				SetText(TerminalFooterText).
-				SetBackgroundColor(TerminalFooterTextColor)
-			isEscapeSequence = false
 			return nil
 		}
-		if event.Key() == tcell.KeyEscape {
-			// Check if alerts command is executed
-			if tui.Pages.HasPage(AlertsPageTitle) {
-				tui.InitAlertsSecondaryView()
-				page, _ := tui.Pages.GetFrontPage()
-
-				// Handle page traversal
-				switch page {
-				case AlertDataPageTitle:
-					tui.Pages.SwitchToPage(tui.FrontPage)
-				case ServiceLogsPageTitle:
-					tui.Pages.SwitchToPage(AlertDataPageTitle)
-					tui.InitAlertDataSecondaryView()
-				case AlertMetadata:
-					tui.Pages.SwitchToPage(IncidentsPageTitle)
-				case AckAlertDataPage:
-					tui.Pages.SwitchToPage(AckIncidentsPageTitle)
-				default:
-					tui.InitAlertsUI(tui.Alerts, AlertsTableTitle, AlertsPageTitle)
-					tui.Pages.SwitchToPage(AlertsPageTitle)
-					tui.Footer.SetText(FooterTextAlerts)
+
+		for _, modal := range []string{tui.listModal, CodeBlockPageTitle} {
+			if modal != "" && tui.Root.HasPage(modal) {
+				if tui.Keymap.Matches(ActionBack, event) {
+					tui.hideModal(modal)
+					return nil
 				}
+				return event
 			}
-			// Check if oncall command is executed
-			if title, _ := tui.Pages.GetFrontPage(); strings.Contains(title, "Oncall") {
-				tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, 2))
-				tui.Footer.SetText(FooterTextOncall)
-			}
-			return nil
 		}
-		if event.Key() == tcell.KeyLeft && CursorPos > 0 {
-			CursorPos--
-			return event
-		} else if event.Key() == tcell.KeyRight && CursorPos < len(tui.TerminalInputBuffer) {
-			CursorPos++
-			return event
-		} else if event.Key() == tcell.KeyCtrlN {
-			NextSlide(tui)
-			return nil
-			// Move to the Previous Slide
-		} else if event.Key() == tcell.KeyCtrlP {
-			PreviousSlide(tui)
-			return nil
-			// Add a new Slide - bash
-		} else if event.Key() == tcell.KeyCtrlS {
-			AddNewSlide(tui, constants.Shell, os.Getenv("SHELL"), []string{}, false)
+
+		if tui.isEscapeSequence {
+			tui.handlePromptKey(event)
 			return nil
-			// Add a new Slide - ocm-container
-		} else if event.Key() == tcell.KeyCtrlO {
-			OcmContainerPath, err := exec.LookPath(constants.OcmContainer)
-			if err != nil {
-				utils.ErrorLogger.Println("ocm-container is not found.\nPlease install it via:", constants.OcmContainerURL)
+		}
+
+		// Multiplexer bindings are only active after the prefix key, if one is configured
+		if tui.Keymap.Prefix != nil {
+			if tui.isPrefixed {
+				tui.isPrefixed = false
+				tui.resetTerminalFooter()
+
+				// Pressing the prefix key twice sends it to the slide
+				if tui.Keymap.Prefix.Matches(event) {
+					return event
+				}
+
+				// The slide number is entered at the prompt, to reach the slides numbered from 10
+				if event.Key() == tcell.KeyRune && event.Modifiers()&tcell.ModAlt == 0 && event.Rune() >= '0' && event.Rune() <= '9' {
+					tui.promptSlide(TerminalFooterEscapeState, tui.Mux.Switch)
+					tui.handlePromptKey(event)
+					return nil
+				}
+
+				// The help is available on every slide after the prefix key
+				if tui.Keymap.Matches(ActionHelp, event) {
+					tui.showHelp()
+					return nil
+				}
+
+				// The keys which aren't bound to a multiplexer action are dropped, as tmux does
+				if cmd, ok := tui.matchCommand(append(tui.muxCommands(), tui.paneCommands()...), event); ok {
+					cmd.handler()
+				}
+				return nil
+			} else if tui.Keymap.Prefix.Matches(event) {
+				tui.TerminalFixedFooter.
+					SetText(TerminalFooterPrefixState).
+					SetBackgroundColor(tui.Theme.TerminalFooterEscapeState)
+				tui.isPrefixed = true
 				return nil
 			}
-			AddNewSlide(tui, constants.OcmContainer, OcmContainerPath, []string{}, false)
+		} else if cmd, ok := tui.matchCommand(append(tui.muxCommands(), tui.paneCommands()...), event); ok {
+			cmd.handler()
 			return nil
+		}
 
-		} else if event.Key() == tcell.KeyCtrlB {
-			// Reset the input buffer
-			tui.TerminalFixedFooter.
-				SetText(TerminalFooterEscapeState).
-				SetBackgroundColor(TerminalFooterEscapeStateColor)
-			isEscapeSequence = true
-			return nil
-			// Delete the current active Slide
-		} else if event.Key() == tcell.KeyCtrlE {
-			slideNum, _ := strconv.Atoi(tui.TerminalPageBar.GetHighlights()[0])
-			RemoveSlide(slideNum, tui)
-			tui.TerminalInputBuffer = []rune{}
+		// Slides without any commands, i.e terminals, receive all the remaining keys
+		if cmd, ok := tui.matchCommand(tui.slideCommands(), event); ok {
+			if cmd.handler == nil {
+				if event.Key() == cmd.passthrough {
+					return event
+				}
+				return tcell.NewEventKey(cmd.passthrough, 0, tcell.ModNone)
+			}
+			cmd.handler()
 			return nil
 		}
-		// } else if event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
-		// 	if len(tui.TerminalInputBuffer) > 0 {
-		// 		if CursorPos > 0 {
-		// 			tui.TerminalInputBuffer = append(tui.TerminalInputBuffer[:CursorPos-1], tui.TerminalInputBuffer[CursorPos:]...)
-		// 			CursorPos--
-		// 		} else {
-		// 			tui.TerminalInputBuffer = tui.TerminalInputBuffer[:len(tui.TerminalInputBuffer)-1]
-		// 		}
-		// 	}
-		// 	if len(tui.TerminalLastChars) > len("exit") {
-		// 		tui.TerminalLastChars = tui.TerminalLastChars[len(tui.TerminalLastChars)-len("exit"):]
-		// 	}
-		// 	// Working on the input buffer
-		// } else if event.Key() == tcell.KeyRune {
-		// 	if CursorPos >= len(tui.TerminalInputBuffer) {
-		// 		// Append new rune to end of input buffer
-		// 		tui.TerminalInputBuffer = append(tui.TerminalInputBuffer, event.Rune())
-		// 		tui.TerminalLastChars = append(tui.TerminalLastChars, event.Rune())
-		// 		if len(tui.TerminalLastChars) > len("exit") {
-		// 			tui.TerminalLastChars = tui.TerminalLastChars[1:]
-		// 		}
-		// 	} else {
-		// 		// Insert new rune at cursor position in input buffer
-		// 		tui.TerminalInputBuffer = append(tui.TerminalInputBuffer[:CursorPos], append([]rune{event.Rune()}, tui.TerminalInputBuffer[CursorPos:]...)...)
-		// 		tui.TerminalLastChars = append(tui.TerminalLastChars[:CursorPos], append([]rune{event.Rune()}, tui.TerminalLastChars[CursorPos:]...)...)
-		// 		if len(tui.TerminalLastChars) > len("exit") {
-		// 			tui.TerminalLastChars = tui.TerminalLastChars[1:]
-		// 		}
-		// 	}
-		// 	CursorPos++
-
-		// } else if event.Key() == tcell.KeyEnter {
-		// 	if string(tui.TerminalInputBuffer) == "exit" || string(tui.TerminalLastChars) == "exit" {
-		// 		slideNum, _ := strconv.Atoi(tui.TerminalPageBar.GetHighlights()[0])
-		// 		RemoveSlide(slideNum, tui)
-		// 	}
-		// 	tui.TerminalInputBuffer = []rune{}
-		// 	tui.TerminalLastChars = []rune{}
-		// 	CursorPos = 0
-		// }
 
 		// Override the default exit behaviour with Ctrl+C
 		if event.Key() == tcell.KeyCtrlC {
 			return nil
 		}
-		// Exit the App on Ctrl + Q
-		if event.Key() == tcell.KeyCtrlQ {
-			utils.InfoLogger.Println("Exiting kite")
-			tui.App.Stop()
-		}
-
-		tui.setupAlertsPageInput()
-		tui.setupIncidentsPageInput()
-		tui.setupAlertDetailsPageInput()
-		tui.setupOncallPageInput()
 
 		return event
 	})
 }
 
-func (tui *TUI) setupAlertsPageInput() {
-	if title, _ := tui.Pages.GetFrontPage(); title == AlertsPageTitle {
+// matchCommand returns the first command bound to the given key event.
+func (tui *TUI) matchCommand(commands []command, event *tcell.EventKey) (command, bool) {
+	for _, cmd := range commands {
+		if tui.Keymap.Matches(cmd.action, event) {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
 
-		tui.Pages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+// muxCommands returns the terminal multiplexer commands, which are available on every slide.
+func (tui *TUI) muxCommands() []command {
+	commands := []command{
+		{action: ActionNextSlide, handler: tui.Mux.Next},
+		{action: ActionPreviousSlide, handler: tui.Mux.Previous},
+		{action: ActionShellSlide, handler: tui.addShellSlide},
+		{action: ActionOcmSlide, handler: tui.addOcmContainerSlide},
+		{action: ActionExitSlide, handler: tui.exitActiveSlide},
+		{action: ActionGotoSlide, handler: func() { tui.promptSlide(TerminalFooterEscapeState, tui.Mux.Switch) }},
+		{action: ActionRenameSlide, handler: func() { tui.prompt(TerminalFooterRenameState, tui.renameActiveSlide) }},
+		{action: ActionMoveSlideLeft, handler: func() { tui.Mux.Move(-1) }},
+		{action: ActionMoveSlideRight, handler: func() { tui.Mux.Move(1) }},
+		{action: ActionSlideList, handler: tui.showSlideList},
+		{action: ActionSaveSession, handler: tui.saveSession},
+		{action: ActionRecordingList, handler: tui.showRecordingList},
+		{action: ActionBroadcast, handler: tui.toggleBroadcast},
+		{action: ActionLauncherList, handler: tui.showLauncherList},
+		{action: ActionDetach, handler: tui.detach},
+		{action: ActionQuit, handler: tui.quit},
+	}
 
-			if event.Rune() == '1' {
-				utils.InfoLogger.Print("Switching to acknowledged incidents view")
-				tui.SeedAckIncidentsUI()
+	return append(commands, tui.launcherCommands()...)
+}
 
-				if len(tui.Incidents) == 0 {
-					utils.InfoLogger.Printf("No acknowledged incidents assigned found")
-				}
+// paneCommands returns the commands managing the panes of the active slide, which are available on every slide.
+func (tui *TUI) paneCommands() []command {
+	return []command{
+		{action: ActionSplitVertical, handler: func() { tui.splitPane(tview.FlexColumn) }},
+		{action: ActionSplitHorizontal, handler: func() { tui.splitPane(tview.FlexRow) }},
+		{action: ActionJoinSlide, handler: func() { tui.promptSlide(TerminalFooterJoinState, tui.joinSlide) }},
+		{action: ActionClosePane, handler: tui.closePane},
+		{action: ActionFocusPaneLeft, handler: func() { tui.focusPane(-1, 0) }},
+		{action: ActionFocusPaneRight, handler: func() { tui.focusPane(1, 0) }},
+		{action: ActionFocusPaneUp, handler: func() { tui.focusPane(0, -1) }},
+		{action: ActionFocusPaneDown, handler: func() { tui.focusPane(0, 1) }},
+		{action: ActionGrowPane, handler: func() { tui.resizePane(PaneResizeStep) }},
+		{action: ActionShrinkPane, handler: func() { tui.resizePane(-PaneResizeStep) }},
+		{action: ActionCopyMode, handler: tui.enterCopyMode},
+		{action: ActionPaste, handler: tui.paste},
+		{action: ActionToggleRecording, handler: tui.toggleRecording},
+	}
+}
 
-				tui.Pages.SwitchToPage(AckIncidentsPageTitle)
-			}
+// slideCommands returns the commands of the focused pane of the active slide.
+func (tui *TUI) slideCommands() []command {
+	if tui.isKitePaneFocused() {
+		return tui.pageCommands()
+	}
 
-			if event.Rune() == '2' {
-				utils.InfoLogger.Print("Switching to incidents view")
-				tui.SeedIncidentsUI()
+	if term := tui.focusedTerminal(); term != nil && term.copyMode != nil {
+		return tui.copyModeCommands(term)
+	}
 
-				if len(tui.Incidents) == 0 {
-					utils.InfoLogger.Printf("No trigerred incidents assigned to found")
-				}
+	if slide := tui.Mux.Active(); slide != nil {
+		return slide.view.FocusedPane().commands
+	}
 
-				tui.Pages.SwitchToPage(IncidentsPageTitle)
-			}
+	return nil
+}
 
-			// Alerts refresh
-			if event.Rune() == 'r' || event.Rune() == 'R' {
-				utils.InfoLogger.Print("Refreshing alerts...")
-				tui.SeedAlertsUI()
-			}
-			return event
-		})
+// sopCommands returns the commands of the SOP views.
+func (tui *TUI) sopCommands() []command {
+	return []command{
+		{action: ActionNextLink, passthrough: tcell.KeyTab},
+		{action: ActionPreviousLink, passthrough: tcell.KeyBacktab},
+		{action: ActionOpenLink, passthrough: tcell.KeyEnter},
+		{action: ActionHistoryBack, handler: func() { tui.withFocusedSOP((*sopBrowser).goBack) }},
+		{action: ActionHistoryForward, handler: func() { tui.withFocusedSOP((*sopBrowser).goForward) }},
+		{action: ActionLinkList, handler: func() { tui.withFocusedSOP((*sopBrowser).showLinks) }},
+		{action: ActionNextCodeBlock, handler: func() { tui.withFocusedSOP((*sopBrowser).cycleCodeBlocks) }},
+		{action: ActionHelp, handler: tui.showHelp},
 	}
 }
 
-func (tui *TUI) setupIncidentsPageInput() {
-	if title, _ := tui.Pages.GetFrontPage(); title == IncidentsPageTitle {
-		tui.Pages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-			if event.Key() == tcell.KeyCtrlA {
-				for _, v := range tui.SelectedIncidents {
-					if v != "" {
-						tui.AckIncidents = append(tui.AckIncidents, v)
-					}
-				}
+// pageCommands returns the commands valid for the page currently displayed in the kite slide.
+func (tui *TUI) pageCommands() []command {
+	var commands []command
 
-				if len(tui.AckIncidents) == 0 {
-					utils.ErrorLogger.Print("Please select atleast one incident to acknowledge")
-				} else {
-					tui.ackowledgeSelectedIncidents()
-				}
-			}
-			if event.Rune() == 'V' || event.Rune() == 'v' {
-				row, _ := tui.IncidentsTable.GetSelection()
-				var incident pdApi.Incident
-				client, _ := client.NewClient().Connect()
-				incidentID := tui.IncidentsTable.GetCell(row, 0).Text
-				incident.APIObject.ID = incidentID
-				var clusterName string
-				var alertData string
-
-				alerts, _ := pdcli.GetIncidentAlerts(client, incident)
-				Alert := alerts[0]
-
-				for _, alert := range alerts {
-					if incidentID == alert.IncidentID {
-						alertData = pdcli.ParseAlertMetaData(alert)
-						clusterName = alert.ClusterName
-						tui.ClusterID = alert.ClusterID
-						break
-					}
-				}
-				if len(alerts) == 1 {
-					alertData = pdcli.ParseAlertMetaData(Alert)
-					tui.AlertMetadata.SetText(alertData)
-					tui.Pages.AddAndSwitchToPage(AlertMetadata, tui.AlertMetadata, true)
+	selectCmd := command{action: ActionSelect, passthrough: tcell.KeyEnter}
+	page, item := tui.Pages.GetFrontPage()
 
-				} else {
-					tui.SetAlertsTableEvents(alerts)
-					tui.InitAlertsUI(alerts, AlertMetadata, AlertMetadata)
+	switch {
+	case item == tui.AlertMetadata:
+		commands = []command{
+			{action: ActionClusterLogin, handler: tui.loginToCluster},
+			{action: ActionViewSOP, handler: tui.viewSOP},
+			{action: ActionServiceLogs, handler: tui.viewServiceLogs},
+		}
 
-				}
-				// Do not prompt for cluster login if there's no cluster ID associated with the alert (v3 clusters)
-				if tui.ClusterID != "N/A" && tui.ClusterID != "" && alertData != "" {
-					secondaryWindowText := fmt.Sprintf("Press 'Y' to log into the cluster: %s\nPress 'S' to view the SOP\nPress 'L' to view service logs", clusterName)
-					tui.SecondaryWindow.SetText(secondaryWindowText)
-				}
-			}
-			return event
-		})
+	case page == AlertsPageTitle:
+		commands = []command{
+			selectCmd,
+			{action: ActionRefreshAlerts, handler: tui.refreshAlerts},
+			{action: ActionAckIncidents, handler: tui.viewAckIncidents},
+			{action: ActionTriggeredIncidents, handler: tui.viewTriggeredIncidents},
+		}
+
+	case page == TrigerredAlertsPageTitle:
+		commands = []command{
+			selectCmd,
+			{action: ActionAckIncidents, handler: tui.viewAckIncidents},
+			{action: ActionTriggeredIncidents, handler: tui.viewTriggeredIncidents},
+		}
+
+	case page == IncidentsPageTitle:
+		commands = []command{
+			selectCmd,
+			{action: ActionAcknowledge, handler: tui.acknowledgeIncidents},
+			{action: ActionViewIncidentAlerts, handler: tui.viewIncidentAlerts},
+		}
+
+	case page == AckIncidentsPageTitle, page == AlertMetadata, page == AckAlertDataPage:
+		commands = []command{selectCmd}
+
+	case strings.Contains(page, OncallPageTitle):
+		if tui.NextOncallTable != nil {
+			commands = append(commands, command{action: ActionNextOncall, handler: tui.viewNextOncall})
+		}
+
+		if tui.AllTeamsOncallTable != nil {
+			commands = append(commands, command{action: ActionAllTeamsOncall, handler: tui.viewAllTeamsOncall})
+		}
+
+		commands = append(commands,
+			command{action: ActionPreviousLayer, handler: tui.previousOncallLayer},
+			command{action: ActionNextLayer, handler: tui.nextOncallLayer},
+		)
+	}
+
+	if page == SOPSearchPageTitle {
+		commands = []command{selectCmd}
 	}
+
+	return append(commands,
+		command{action: ActionSearchSOPs, handler: tui.promptSOPSearch},
+		command{action: ActionBack, handler: tui.goBack},
+		command{action: ActionHelp, handler: tui.showHelp},
+	)
 }
 
-func (tui *TUI) setupAlertDetailsPageInput() {
-	tui.AlertMetadata.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+func (tui *TUI) addShellSlide() {
+	tui.launchSlide(ShellSlide, constants.Shell, os.Getenv("SHELL"), []string{})
+}
 
-		if event.Rune() == 'Y' || event.Rune() == 'y' {
-			// Get ocm-conatiner executable from PATH
-			ocmContainer, err := exec.LookPath("ocm-container")
+func (tui *TUI) addOcmContainerSlide() {
+	OcmContainerPath, err := exec.LookPath(constants.OcmContainer)
+	if err != nil {
+		utils.ErrorLogger.Println("ocm-container is not found.\nPlease install it via:", constants.OcmContainerURL)
+		return
+	}
+	tui.launchSlide(OcmContainerSlide, constants.OcmContainer, OcmContainerPath, []string{})
+}
 
-			if err != nil {
-				errMessage := "ocm-container is not found.\nPlease install it via: " + constants.OcmContainerURL
-				utils.ErrorLogger.Print(errMessage)
-				return nil
-			}
+// Delete the current active Slide
+func (tui *TUI) exitActiveSlide() {
+	if slide := tui.Mux.Active(); slide != nil {
+		tui.exitSlide(slide)
+	}
+}
 
-			// Convert the ClusterID into args for ocm-container command
-			clusterIDArgs := []string{tui.ClusterID}
-			AddNewSlide(tui, tui.ClusterName, ocmContainer, clusterIDArgs, true)
-		}
+// Delete the given slide and stop the programs running in its panes
+// kite is exited when the last slide is deleted
+func (tui *TUI) exitSlide(slide *Slide) {
+	if !tui.Mux.Remove(slide) {
+		return
+	}
 
-		if event.Rune() == 'L' || event.Rune() == 'l' {
-			utils.InfoLogger.Print("Retrieving service logs for cluster")
-			tui.fetchClusterServiceLogs()
+	for _, pane := range slide.view.Panes() {
+		if term, ok := pane.primitive.(*Terminal); ok {
+			term.Close()
 		}
+	}
+}
 
-		if event.Rune() == 'S' || event.Rune() == 's' {
-			if tui.SOPLink == "" || tui.SOPLink == "<nil>" {
-				utils.InfoLogger.Print("No SOP mentioned for the alert")
-				return nil
-			}
-			utils.InfoLogger.Print("Opening SOP in a new tab")
-			ViewAlertSOP(tui, tui.SOPLink)
+// Prompt for a text in the footer, the handler is called with the text entered when Enter is pressed
+func (tui *TUI) prompt(prompt string, handler func(input string)) {
+	tui.promptText = prompt
+	tui.promptInput = []rune{}
+	tui.isSlidePrompt = false
+	tui.onPromptInput = handler
+	tui.isEscapeSequence = true
+	tui.updatePrompt()
+}
+
+// Prompt for a slide number or name, the handler is called with the matching slide
+func (tui *TUI) promptSlide(prompt string, handler func(slide *Slide)) {
+	tui.prompt(prompt, func(input string) {
+		slide := tui.Mux.Find(input)
+
+		if slide == nil {
+			utils.ErrorLogger.Printf("No slide matching '%s'", input)
+			return
 		}
 
-		return event
+		handler(slide)
 	})
+	tui.isSlidePrompt = true
 }
 
-func (tui *TUI) setupOncallPageInput() {
-	if title, _ := tui.Pages.GetFrontPage(); strings.Contains(title, OncallPageTitle) {
-		tui.Pages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+// handlePromptKey edits the text entered at the prompt.
+// Slide numbers are submitted as soon as no other slide number nor slide name can start with the digits entered.
+func (tui *TUI) handlePromptKey(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		tui.closePrompt()
+		return
+	case tcell.KeyEnter:
+		tui.submitPrompt()
+		return
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(tui.promptInput) > 0 {
+			tui.promptInput = tui.promptInput[:len(tui.promptInput)-1]
+		}
+	case tcell.KeyRune:
+		tui.promptInput = append(tui.promptInput, event.Rune())
 
-			if tui.NextOncallTable != nil {
-				if event.Rune() == 'N' || event.Rune() == 'n' {
-					utils.InfoLogger.Print("Viewing user next on-call schedule")
-					tui.Pages.SwitchToPage(NextOncallPageTitle)
+		input := string(tui.promptInput)
 
-					if len(tui.AckIncidents) == 0 {
-						utils.InfoLogger.Print("You are not scheduled for any oncall duties for the next 3 months. Cheer up!")
-					}
-				}
-			}
+		if slideNum, err := strconv.Atoi(input); tui.isSlidePrompt && err == nil && slideNum*10 > tui.Mux.Len() && !tui.Mux.hasTitlePrefix(input) {
+			tui.submitPrompt()
+			return
+		}
+	}
 
-			if tui.AllTeamsOncallTable != nil {
-				if event.Rune() == 'A' || event.Rune() == 'a' {
-					utils.InfoLogger.Print("Switching to all team on-call view")
-					tui.Pages.SwitchToPage(AllTeamsOncallPageTitle)
-				}
-			}
-			if event.Key() == tcell.KeyLeft {
-				if tui.CurrentOnCallPage > 0 {
-					tui.CurrentOnCallPage -= 1
-				}
-				tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
-			}
-			if event.Key() == tcell.KeyRight {
-				if tui.CurrentOnCallPage < 4 {
-					tui.CurrentOnCallPage += 1
-				}
-				tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
-			}
-			return event
+	tui.updatePrompt()
+}
+
+func (tui *TUI) submitPrompt() {
+	input := string(tui.promptInput)
+	handler := tui.onPromptInput
+	tui.closePrompt()
+
+	if strings.TrimSpace(input) != "" {
+		handler(input)
+	}
+}
+
+func (tui *TUI) closePrompt() {
+	tui.isEscapeSequence = false
+	tui.promptInput = []rune{}
+	tui.onPromptInput = nil
+	tui.resetTerminalFooter()
+}
+
+func (tui *TUI) updatePrompt() {
+	tui.TerminalFixedFooter.
+		SetText(tui.promptText + tview.Escape(string(tui.promptInput))).
+		SetBackgroundColor(tui.Theme.TerminalFooterEscapeState)
+}
+
+func (tui *TUI) renameActiveSlide(title string) {
+	tui.Mux.Rename(tui.Mux.Active(), strings.TrimSpace(title))
+}
+
+// showSlideList displays the list of slides, the selected slide is switched to
+func (tui *TUI) showSlideList() {
+	var items []listItem
+
+	for index, slide := range tui.Mux.Slides() {
+		slide := slide
+		items = append(items, listItem{
+			text:     fmt.Sprintf("%d %s", index+1, tview.Escape(slide.title)),
+			selected: func() { tui.Mux.Switch(slide) },
 		})
 	}
+
+	tui.showListModal(SlideListPageTitle, "Slides", 50, items, tui.Mux.ActiveIndex())
+}
+
+// quit exits kite, the session is saved by StartApp
+func (tui *TUI) quit() {
+	utils.InfoLogger.Println("Exiting kite")
+	tui.App.Stop()
+}
+
+// detach detaches the client of the kite server, the slides keep running in the background
+func (tui *TUI) detach() {
+	if tui.Server == nil {
+		utils.ErrorLogger.Println("kite is not running in a server, start it with 'kite attach'")
+		return
+	}
+
+	utils.InfoLogger.Printf("Detaching from kite server '%s'", tui.Server.Name())
+	tui.Server.Detach()
+}
+
+// goBack handles the page traversal when going back from a page.
+func (tui *TUI) goBack() {
+	if page, _ := tui.Pages.GetFrontPage(); page == SOPSearchPageTitle {
+		tui.closeSOPSearch()
+		return
+	}
+
+	// Check if alerts command is executed
+	if tui.Pages.HasPage(AlertsPageTitle) {
+		tui.InitAlertsSecondaryView()
+		page, _ := tui.Pages.GetFrontPage()
+
+		// Handle page traversal
+		switch page {
+		case AlertDataPageTitle:
+			tui.Pages.SwitchToPage(tui.FrontPage)
+		case ServiceLogsPageTitle:
+			tui.Pages.SwitchToPage(AlertDataPageTitle)
+			tui.InitAlertDataSecondaryView()
+		case AlertMetadata:
+			tui.Pages.SwitchToPage(IncidentsPageTitle)
+		case AckAlertDataPage:
+			tui.Pages.SwitchToPage(AckIncidentsPageTitle)
+		default:
+			tui.InitAlertsUI(tui.Alerts, AlertsTableTitle, AlertsPageTitle)
+			tui.Pages.SwitchToPage(AlertsPageTitle)
+		}
+	}
+	// Check if oncall command is executed
+	if title, _ := tui.Pages.GetFrontPage(); strings.Contains(title, "Oncall") {
+		tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, 2))
+	}
+}
+
+func (tui *TUI) viewAckIncidents() {
+	utils.InfoLogger.Print("Switching to acknowledged incidents view")
+	tui.SeedAckIncidentsUI()
+
+	if len(tui.Incidents) == 0 {
+		utils.InfoLogger.Printf("No acknowledged incidents assigned found")
+	}
+
+	tui.Pages.SwitchToPage(AckIncidentsPageTitle)
+}
+
+func (tui *TUI) viewTriggeredIncidents() {
+	utils.InfoLogger.Print("Switching to incidents view")
+	tui.SeedIncidentsUI()
+
+	if len(tui.Incidents) == 0 {
+		utils.InfoLogger.Printf("No trigerred incidents assigned to found")
+	}
+
+	tui.Pages.SwitchToPage(IncidentsPageTitle)
+}
+
+// Alerts refresh
+func (tui *TUI) refreshAlerts() {
+	utils.InfoLogger.Print("Refreshing alerts...")
+	tui.SeedAlertsUI()
+}
+
+func (tui *TUI) acknowledgeIncidents() {
+	for _, v := range tui.SelectedIncidents {
+		if v != "" {
+			tui.AckIncidents = append(tui.AckIncidents, v)
+		}
+	}
+
+	if len(tui.AckIncidents) == 0 {
+		utils.ErrorLogger.Print("Please select atleast one incident to acknowledge")
+	} else {
+		tui.ackowledgeSelectedIncidents()
+	}
+}
+
+func (tui *TUI) viewIncidentAlerts() {
+	row, _ := tui.IncidentsTable.GetSelection()
+	var incident pdApi.Incident
+	client, _ := client.NewClient().Connect()
+	incidentID := tui.IncidentsTable.GetCell(row, 0).Text
+	incident.APIObject.ID = incidentID
+	tui.IncidentID = incidentID
+	var clusterName string
+	var alertData string
+
+	alerts, _ := pdcli.GetIncidentAlerts(client, incident)
+	Alert := alerts[0]
+
+	for _, alert := range alerts {
+		if incidentID == alert.IncidentID {
+			alertData = pdcli.ParseAlertMetaData(alert)
+			clusterName = alert.ClusterName
+			tui.ClusterID = alert.ClusterID
+			tui.SelectedAlert = &alert
+			break
+		}
+	}
+	if len(alerts) == 1 {
+		alertData = pdcli.ParseAlertMetaData(Alert)
+		tui.AlertMetadata.SetText(alertData)
+		tui.Pages.AddAndSwitchToPage(AlertMetadata, tui.AlertMetadata, true)
+
+	} else {
+		tui.SetAlertsTableEvents(alerts)
+		tui.InitAlertsUI(alerts, AlertMetadata, AlertMetadata)
+
+	}
+	// Do not prompt for cluster login if there's no cluster ID associated with the alert (v3 clusters)
+	if tui.ClusterID != "N/A" && tui.ClusterID != "" && alertData != "" {
+		tui.SecondaryWindow.SetText(tui.clusterPromptText(clusterName))
+	}
+}
+
+func (tui *TUI) loginToCluster() {
+	// Get ocm-conatiner executable from PATH
+	ocmContainer, err := exec.LookPath("ocm-container")
+
+	if err != nil {
+		errMessage := "ocm-container is not found.\nPlease install it via: " + constants.OcmContainerURL
+		utils.ErrorLogger.Print(errMessage)
+		return
+	}
+
+	// Convert the ClusterID into args for ocm-container command
+	clusterIDArgs := []string{tui.ClusterID}
+	tui.launchSlide(ClusterSlide, tui.ClusterName, ocmContainer, clusterIDArgs)
+}
+
+func (tui *TUI) viewServiceLogs() {
+	utils.InfoLogger.Print("Retrieving service logs for cluster")
+	tui.fetchClusterServiceLogs()
+}
+
+func (tui *TUI) viewSOP() {
+	if tui.SOPLink == "" || tui.SOPLink == "<nil>" {
+		tui.resolveSOP()
+		return
+	}
+	utils.InfoLogger.Print("Opening SOP in a new tab")
+	ViewAlertSOP(tui, tui.SOPLink)
+}
+
+func (tui *TUI) viewNextOncall() {
+	utils.InfoLogger.Print("Viewing user next on-call schedule")
+	tui.Pages.SwitchToPage(NextOncallPageTitle)
+
+	if len(tui.AckIncidents) == 0 {
+		utils.InfoLogger.Print("You are not scheduled for any oncall duties for the next 3 months. Cheer up!")
+	}
+}
+
+func (tui *TUI) viewAllTeamsOncall() {
+	utils.InfoLogger.Print("Switching to all team on-call view")
+	tui.Pages.SwitchToPage(AllTeamsOncallPageTitle)
+}
+
+func (tui *TUI) previousOncallLayer() {
+	if tui.CurrentOnCallPage > 0 {
+		tui.CurrentOnCallPage -= 1
+	}
+	tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
+}
+
+func (tui *TUI) nextOncallLayer() {
+	if tui.CurrentOnCallPage < 4 {
+		tui.CurrentOnCallPage += 1
+	}
+	tui.Pages.SwitchToPage(fmt.Sprintf("%s%d", OncallPageTitle, tui.CurrentOnCallPage))
 }
@@ -0,0 +1,573 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+)
+
+// Action identifies a command of the TUI that can be bound to a key.
+type Action string
+
+const (
+	// Terminal multiplexer actions
+	ActionNextSlide     Action = "next_slide"
+	ActionPreviousSlide Action = "previous_slide"
+	ActionShellSlide    Action = "shell_slide"
+	ActionOcmSlide      Action = "ocm_slide"
+	ActionExitSlide     Action = "exit_slide"
+	ActionGotoSlide     Action = "goto_slide"
+	ActionQuit          Action = "quit"
+
+	// Slide management actions
+	ActionRenameSlide    Action = "rename_slide"
+	ActionMoveSlideLeft  Action = "move_slide_left"
+	ActionMoveSlideRight Action = "move_slide_right"
+	ActionSlideList      Action = "slide_list"
+	ActionSaveSession    Action = "save_session"
+	ActionDetach         Action = "detach"
+	ActionRecordingList  Action = "recording_list"
+	ActionBroadcast      Action = "toggle_broadcast"
+	ActionLauncherList   Action = "launcher_list"
+
+	// Pane actions
+	ActionSplitVertical   Action = "split_vertical"
+	ActionSplitHorizontal Action = "split_horizontal"
+	ActionJoinSlide       Action = "join_slide"
+	ActionClosePane       Action = "close_pane"
+	ActionFocusPaneLeft   Action = "focus_pane_left"
+	ActionFocusPaneRight  Action = "focus_pane_right"
+	ActionFocusPaneUp     Action = "focus_pane_up"
+	ActionFocusPaneDown   Action = "focus_pane_down"
+	ActionGrowPane        Action = "grow_pane"
+	ActionShrinkPane      Action = "shrink_pane"
+	ActionCopyMode        Action = "copy_mode"
+	ActionPaste           Action = "paste"
+	ActionToggleRecording Action = "toggle_recording"
+
+	// Copy mode actions
+	ActionStartSelection Action = "start_selection"
+	ActionCopySelection  Action = "copy_selection"
+	ActionSearch         Action = "search"
+	ActionNextMatch      Action = "next_match"
+	ActionPreviousMatch  Action = "previous_match"
+	ActionExitCopyMode   Action = "exit_copy_mode"
+
+	// Page actions
+	ActionHelp               Action = "help"
+	ActionBack               Action = "back"
+	ActionRefreshAlerts      Action = "refresh_alerts"
+	ActionAckIncidents       Action = "view_acknowledged_incidents"
+	ActionTriggeredIncidents Action = "view_triggered_incidents"
+	ActionSelect             Action = "select"
+	ActionAcknowledge        Action = "acknowledge_incidents"
+	ActionViewIncidentAlerts Action = "view_incident_alerts"
+	ActionClusterLogin       Action = "cluster_login"
+	ActionViewSOP            Action = "view_sop"
+	ActionServiceLogs        Action = "service_logs"
+	ActionNextOncall         Action = "next_oncall"
+	ActionAllTeamsOncall     Action = "all_teams_oncall"
+	ActionPreviousLayer      Action = "previous_oncall_layer"
+	ActionNextLayer          Action = "next_oncall_layer"
+	ActionSearchSOPs         Action = "search_sops"
+
+	// SOP actions
+	ActionNextLink       Action = "next_link"
+	ActionPreviousLink   Action = "previous_link"
+	ActionOpenLink       Action = "open_link"
+	ActionHistoryBack    Action = "history_back"
+	ActionHistoryForward Action = "history_forward"
+	ActionLinkList       Action = "link_list"
+	ActionNextCodeBlock  Action = "next_code_block"
+)
+
+// launchActionPrefix precedes the name of a launcher in the actions running it
+const launchActionPrefix = "launch:"
+
+// LaunchAction returns the action running the launcher with the given name.
+func LaunchAction(name string) Action {
+	return Action(launchActionPrefix + name)
+}
+
+// actionDescriptions holds the text displayed for each action in the footers.
+var actionDescriptions = map[Action]string{
+	ActionNextSlide:          "Next Slide",
+	ActionPreviousSlide:      "Previous Slide",
+	ActionShellSlide:         "Add Slide",
+	ActionOcmSlide:           "Add ocm-container Slide",
+	ActionExitSlide:          "Exit Slide",
+	ActionGotoSlide:          "+ [Num|Name] Change to Slide with [Num] or [Name]",
+	ActionQuit:               "Quit",
+	ActionRenameSlide:        "Rename Slide",
+	ActionMoveSlideLeft:      "Move Slide Left",
+	ActionMoveSlideRight:     "Move Slide Right",
+	ActionSlideList:          "List Slides",
+	ActionSaveSession:        "Save Session",
+	ActionDetach:             "Detach",
+	ActionRecordingList:      "List Recordings",
+	ActionBroadcast:          "Toggle Input Broadcast",
+	ActionLauncherList:       "List Launchers",
+	ActionSplitVertical:      "Split Pane Side by Side",
+	ActionSplitHorizontal:    "Split Pane Stacked",
+	ActionJoinSlide:          "+ [Num|Name] Move Slide with [Num] or [Name] to a New Pane",
+	ActionClosePane:          "Close Pane",
+	ActionFocusPaneLeft:      "Focus Left Pane",
+	ActionFocusPaneRight:     "Focus Right Pane",
+	ActionFocusPaneUp:        "Focus Upper Pane",
+	ActionFocusPaneDown:      "Focus Lower Pane",
+	ActionGrowPane:           "Grow Pane",
+	ActionShrinkPane:         "Shrink Pane",
+	ActionCopyMode:           "Copy Mode",
+	ActionPaste:              "Paste",
+	ActionToggleRecording:    "Start or Stop Recording",
+	ActionStartSelection:     "Start or Clear Selection",
+	ActionCopySelection:      "Copy Selection",
+	ActionSearch:             "Search Backward",
+	ActionNextMatch:          "Next Match Backward",
+	ActionPreviousMatch:      "Previous Match Forward",
+	ActionExitCopyMode:       "Exit Copy Mode",
+	ActionHelp:               "Help",
+	ActionBack:               "Go Back",
+	ActionRefreshAlerts:      "Refresh Alerts",
+	ActionAckIncidents:       "Acknowledged Incidents",
+	ActionTriggeredIncidents: "Triggered Incidents",
+	ActionSelect:             "Select",
+	ActionAcknowledge:        "Acknowledge Incidents",
+	ActionViewIncidentAlerts: "View Incident Alerts",
+	ActionClusterLogin:       "Log into the Cluster",
+	ActionViewSOP:            "View SOP",
+	ActionServiceLogs:        "View Service Logs",
+	ActionNextOncall:         "Your Next Oncall Schedule",
+	ActionAllTeamsOncall:     "All Teams Oncall",
+	ActionPreviousLayer:      "Previous Layer Oncall",
+	ActionNextLayer:          "Next Layer Oncall",
+	ActionSearchSOPs:         "Search SOPs",
+	ActionNextLink:           "Next Link",
+	ActionPreviousLink:       "Previous Link",
+	ActionOpenLink:           "Open Link or Send Code Block",
+	ActionHistoryBack:        "Previous SOP",
+	ActionHistoryForward:     "Next SOP",
+	ActionLinkList:           "List Links",
+	ActionNextCodeBlock:      "Next Code Block",
+}
+
+// muxActions are the terminal multiplexer actions which require the prefix key when one is configured.
+var muxActions = []Action{
+	ActionNextSlide,
+	ActionPreviousSlide,
+	ActionShellSlide,
+	ActionOcmSlide,
+	ActionExitSlide,
+	ActionGotoSlide,
+	ActionQuit,
+	ActionRenameSlide,
+	ActionMoveSlideLeft,
+	ActionMoveSlideRight,
+	ActionSlideList,
+	ActionSaveSession,
+	ActionDetach,
+	ActionRecordingList,
+	ActionBroadcast,
+	ActionLauncherList,
+	ActionSplitVertical,
+	ActionSplitHorizontal,
+	ActionJoinSlide,
+	ActionClosePane,
+	ActionFocusPaneLeft,
+	ActionFocusPaneRight,
+	ActionFocusPaneUp,
+	ActionFocusPaneDown,
+	ActionGrowPane,
+	ActionShrinkPane,
+	ActionCopyMode,
+	ActionPaste,
+	ActionToggleRecording,
+}
+
+// slideModes group the actions of the slides matched together, i.e. on the same page or in the same view.
+// The keys of the actions of a mode must differ, the actions of different modes can share their keys.
+var slideModes = [][]Action{
+	{ActionSelect, ActionRefreshAlerts, ActionAckIncidents, ActionTriggeredIncidents, ActionSearchSOPs, ActionBack, ActionHelp},
+	{ActionSelect, ActionAcknowledge, ActionViewIncidentAlerts, ActionSearchSOPs, ActionBack, ActionHelp},
+	{ActionClusterLogin, ActionViewSOP, ActionServiceLogs, ActionSearchSOPs, ActionBack, ActionHelp},
+	{ActionNextOncall, ActionAllTeamsOncall, ActionPreviousLayer, ActionNextLayer, ActionSearchSOPs, ActionBack, ActionHelp},
+	{ActionStartSelection, ActionCopySelection, ActionSearch, ActionNextMatch, ActionPreviousMatch, ActionExitCopyMode, ActionHelp},
+	{ActionNextLink, ActionPreviousLink, ActionOpenLink, ActionHistoryBack, ActionHistoryForward, ActionLinkList, ActionNextCodeBlock, ActionHelp},
+}
+
+// defaultBindings are the key bindings used when no prefix key is configured.
+var defaultBindings = map[Action]string{
+	ActionNextSlide:          "Ctrl+N",
+	ActionPreviousSlide:      "Ctrl+P",
+	ActionShellSlide:         "Ctrl+S",
+	ActionOcmSlide:           "Ctrl+O",
+	ActionExitSlide:          "Ctrl+E",
+	ActionGotoSlide:          "Ctrl+B",
+	ActionQuit:               "Ctrl+Q",
+	ActionRenameSlide:        "Alt+R",
+	ActionMoveSlideLeft:      "Alt+<",
+	ActionMoveSlideRight:     "Alt+>",
+	ActionSlideList:          "Alt+W",
+	ActionSaveSession:        "Alt+K",
+	ActionDetach:             "Alt+D",
+	ActionRecordingList:      "Alt+L",
+	ActionBroadcast:          "Alt+I",
+	ActionLauncherList:       "Alt+A",
+	ActionSplitVertical:      "Alt+V",
+	ActionSplitHorizontal:    "Alt+S",
+	ActionJoinSlide:          "Alt+J",
+	ActionClosePane:          "Alt+X",
+	ActionFocusPaneLeft:      "Alt+Left",
+	ActionFocusPaneRight:     "Alt+Right",
+	ActionFocusPaneUp:        "Alt+Up",
+	ActionFocusPaneDown:      "Alt+Down",
+	ActionGrowPane:           "Alt+=",
+	ActionShrinkPane:         "Alt+-",
+	ActionCopyMode:           "Alt+C",
+	ActionPaste:              "Alt+P",
+	ActionToggleRecording:    "Alt+T",
+	ActionStartSelection:     "Space",
+	ActionCopySelection:      "Enter",
+	ActionSearch:             "/",
+	ActionNextMatch:          "N",
+	ActionPreviousMatch:      "P",
+	ActionExitCopyMode:       "Esc",
+	ActionHelp:               "?",
+	ActionBack:               "Esc",
+	ActionRefreshAlerts:      "R",
+	ActionAckIncidents:       "1",
+	ActionTriggeredIncidents: "2",
+	ActionSelect:             "Enter",
+	ActionAcknowledge:        "Ctrl+A",
+	ActionViewIncidentAlerts: "V",
+	ActionClusterLogin:       "Y",
+	ActionViewSOP:            "S",
+	ActionServiceLogs:        "L",
+	ActionNextOncall:         "N",
+	ActionAllTeamsOncall:     "A",
+	ActionPreviousLayer:      "Left",
+	ActionNextLayer:          "Right",
+	ActionSearchSOPs:         "/",
+	ActionNextLink:           "Tab",
+	ActionPreviousLink:       "Backtab",
+	ActionOpenLink:           "Enter",
+	ActionHistoryBack:        "Left",
+	ActionHistoryForward:     "Right",
+	ActionLinkList:           "L",
+	ActionNextCodeBlock:      "C",
+}
+
+// defaultPrefixBindings are the multiplexer key bindings used after the prefix key, similar to tmux.
+var defaultPrefixBindings = map[Action]string{
+	ActionNextSlide:     "N",
+	ActionPreviousSlide: "P",
+	ActionShellSlide:    "S",
+	ActionOcmSlide:      "O",
+	ActionExitSlide:     "E",
+	ActionGotoSlide:     "B",
+	ActionQuit:          "Q",
+
+	ActionRenameSlide:    ",",
+	ActionMoveSlideLeft:  "<",
+	ActionMoveSlideRight: ">",
+	ActionSlideList:      "W",
+	ActionSaveSession:    "K",
+	ActionDetach:         "D",
+	ActionRecordingList:  "L",
+	ActionBroadcast:      "I",
+	ActionLauncherList:   "A",
+
+	ActionSplitVertical:   "%",
+	ActionSplitHorizontal: "\"",
+	ActionJoinSlide:       "J",
+	ActionClosePane:       "X",
+	ActionFocusPaneLeft:   "Left",
+	ActionFocusPaneRight:  "Right",
+	ActionFocusPaneUp:     "Up",
+	ActionFocusPaneDown:   "Down",
+	ActionGrowPane:        "+",
+	ActionShrinkPane:      "-",
+	ActionCopyMode:        "[",
+	ActionPaste:           "]",
+	ActionToggleRecording: "T",
+}
+
+// KeyBinding represents a single key press, either a special key or a rune,
+// optionally combined with the Alt modifier.
+type KeyBinding struct {
+	Key  tcell.Key
+	Rune rune
+	Alt  bool
+}
+
+// altPrefix precedes the keys pressed with the Alt modifier, e.g. "Alt+V".
+const altPrefix = "Alt+"
+
+// keyNames maps the lower-cased key names to tcell keys, e.g. "ctrl+n" or "esc".
+var keyNames = func() map[string]tcell.Key {
+	names := make(map[string]tcell.Key)
+	for key, name := range tcell.KeyNames {
+		names[strings.ToLower(strings.Replace(name, "-", "+", 1))] = key
+	}
+	names["escape"] = tcell.KeyEscape
+	names["space"] = tcell.KeyRune
+	return names
+}()
+
+// ParseKeyBinding parses a key description like "Ctrl+N", "Esc", "Left", "r" or "Alt+Left".
+func ParseKeyBinding(str string) (KeyBinding, error) {
+	str = strings.TrimSpace(str)
+
+	if len(str) > len(altPrefix) && strings.EqualFold(str[:len(altPrefix)], altPrefix) {
+		binding, err := ParseKeyBinding(str[len(altPrefix):])
+		binding.Alt = true
+		return binding, err
+	}
+
+	if runes := []rune(str); len(runes) == 1 {
+		return KeyBinding{Key: tcell.KeyRune, Rune: unicode.ToLower(runes[0])}, nil
+	}
+
+	key, ok := keyNames[strings.ToLower(strings.ReplaceAll(str, " ", ""))]
+
+	if !ok {
+		return KeyBinding{}, fmt.Errorf("unknown key '%s'", str)
+	}
+
+	if key == tcell.KeyRune {
+		return KeyBinding{Key: tcell.KeyRune, Rune: ' '}, nil
+	}
+
+	return KeyBinding{Key: key}, nil
+}
+
+// Matches reports whether the given key event is this key press.
+// Letters are matched case-insensitively.
+func (k KeyBinding) Matches(event *tcell.EventKey) bool {
+	if k.Alt != (event.Modifiers()&tcell.ModAlt != 0) {
+		return false
+	}
+	if k.Key != tcell.KeyRune {
+		return event.Key() == k.Key
+	}
+	return event.Key() == tcell.KeyRune && unicode.ToLower(event.Rune()) == k.Rune
+}
+
+// String returns the key as displayed in the footers.
+func (k KeyBinding) String() string {
+	if k.Alt {
+		return altPrefix + KeyBinding{Key: k.Key, Rune: k.Rune}.String()
+	}
+	if k.Key == tcell.KeyRune {
+		if k.Rune == ' ' {
+			return "Space"
+		}
+		return string(unicode.ToUpper(k.Rune))
+	}
+	return strings.Replace(tcell.KeyNames[k.Key], "-", "+", 1)
+}
+
+// Keymap maps the TUI actions to their key bindings.
+type Keymap struct {
+	// Prefix is the key which has to be pressed before a multiplexer binding.
+	// If nil, the multiplexer bindings are active at all times.
+	Prefix   *KeyBinding
+	bindings map[Action]KeyBinding
+
+	// Descriptions of the actions bound at runtime, e.g. the launchers
+	descriptions map[Action]string
+}
+
+// NewKeymap returns the default keymap with the user configured bindings applied.
+func NewKeymap(cfg *config.KeymapConfig) (*Keymap, error) {
+	km := &Keymap{bindings: make(map[Action]KeyBinding), descriptions: make(map[Action]string)}
+
+	for action, key := range defaultBindings {
+		km.bindings[action], _ = ParseKeyBinding(key)
+	}
+
+	if cfg == nil {
+		return km, nil
+	}
+
+	if cfg.Prefix != "" {
+		prefix, err := ParseKeyBinding(cfg.Prefix)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix key: %v", err)
+		}
+
+		km.Prefix = &prefix
+
+		for action, key := range defaultPrefixBindings {
+			km.bindings[action], _ = ParseKeyBinding(key)
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Bindings))
+
+	for name := range cfg.Bindings {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		action := Action(name)
+		key := cfg.Bindings[name]
+
+		if _, ok := actionDescriptions[action]; !ok {
+			return nil, fmt.Errorf("unknown action '%s' in keymap", name)
+		}
+
+		binding, err := ParseKeyBinding(key)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid key for action '%s': %v", name, err)
+		}
+
+		km.bindings[action] = binding
+	}
+
+	// The actions are bound once they are all configured, so that their keys can be swapped
+	for _, name := range names {
+		if err := km.checkBinding(Action(name), km.bindings[Action(name)]); err != nil {
+			return nil, fmt.Errorf("invalid key for action '%s': %v", name, err)
+		}
+	}
+
+	// Pressing the prefix key twice sends it to the slide, it cannot run an action too
+	if km.Prefix != nil {
+		for action, binding := range km.bindings {
+			if binding == *km.Prefix {
+				return nil, fmt.Errorf("the prefix key %s is already bound to the '%s' action", km.Prefix, action)
+			}
+		}
+	}
+
+	return km, nil
+}
+
+// Bind binds the given key to an action which is not built in, e.g. a launcher.
+// The key is rejected if it is the prefix key or if it is bound to another action matched in the same mode.
+func (km *Keymap) Bind(action Action, key string, description string) error {
+	binding, err := ParseKeyBinding(key)
+
+	if err != nil {
+		return err
+	}
+
+	if km.Prefix != nil && binding == *km.Prefix {
+		return fmt.Errorf("the key %s is the prefix key", binding)
+	}
+
+	if err := km.checkBinding(action, binding); err != nil {
+		return err
+	}
+
+	km.bindings[action] = binding
+	km.descriptions[action] = description
+
+	return nil
+}
+
+// Description returns the text describing the given action.
+func (km *Keymap) Description(action Action) string {
+	if description, ok := km.descriptions[action]; ok {
+		return description
+	}
+	return actionDescriptions[action]
+}
+
+// Matches reports whether the key event is bound to the given action.
+func (km *Keymap) Matches(action Action, event *tcell.EventKey) bool {
+	binding, ok := km.bindings[action]
+	return ok && binding.Matches(event)
+}
+
+// Key returns the display form of the key bound to the given action.
+// Multiplexer bindings are preceded by the prefix key when one is configured.
+func (km *Keymap) Key(action Action) string {
+	key := km.bindings[action].String()
+
+	if km.Prefix != nil && isMuxAction(action) {
+		key = km.Prefix.String() + " " + key
+	}
+
+	return key
+}
+
+// Help returns the footer text describing the given actions.
+func (km *Keymap) Help(actions ...Action) string {
+	var items []string
+
+	for _, action := range actions {
+		items = append(items, fmt.Sprintf("[%s] %s", km.Key(action), km.Description(action)))
+	}
+
+	return strings.Join(items, " | ")
+}
+
+// checkBinding returns an error if the key of the action is bound to another action matched in the same mode,
+// only the first of them would run. After the prefix key, the digits switch to the slides.
+func (km *Keymap) checkBinding(action Action, binding KeyBinding) error {
+	if km.Prefix != nil && isMuxAction(action) && !binding.Alt && binding.Key == tcell.KeyRune && unicode.IsDigit(binding.Rune) {
+		return fmt.Errorf("the key %s switches to a slide after the prefix key", binding)
+	}
+
+	for other, otherBinding := range km.bindings {
+		if other != action && otherBinding == binding && km.sameMode(action, other) {
+			return fmt.Errorf("the key %s is already bound to the '%s' action", binding, other)
+		}
+	}
+
+	return nil
+}
+
+// sameMode reports whether the keys of the two actions are matched together.
+// Without a prefix key, the multiplexer actions are matched before the actions of every mode.
+// After the prefix key, the help is matched with the multiplexer actions.
+func (km *Keymap) sameMode(a Action, b Action) bool {
+	if isMuxAction(a) || isMuxAction(b) {
+		if km.Prefix == nil || (isMuxAction(a) && isMuxAction(b)) {
+			return true
+		}
+		return a == ActionHelp || b == ActionHelp
+	}
+
+	for _, mode := range slideModes {
+		if inMode(mode, a) && inMode(mode, b) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inMode reports whether the action is one of the actions of the mode.
+func inMode(mode []Action, action Action) bool {
+	for _, a := range mode {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// isMuxAction reports whether the action belongs to the terminal multiplexer.
+func isMuxAction(action Action) bool {
+	if strings.HasPrefix(string(action), launchActionPrefix) {
+		return true
+	}
+
+	for _, a := range muxActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// Kinds of slides customized by the launch templates
+const (
+	ClusterSlide      = "cluster"
+	OcmContainerSlide = "ocm-container"
+	ShellSlide        = "shell"
+)
+
+// AlertEnv returns the environment variables describing the given alert, nil when no alert is selected.
+// The fields which are unknown are left out.
+func AlertEnv(alert *pdcli.Alert) []string {
+	if alert == nil {
+		return nil
+	}
+
+	var env []string
+
+	for _, variable := range []struct {
+		name  string
+		value string
+	}{
+		{"INCIDENT_ID", alert.IncidentID},
+		{"ALERT_ID", alert.AlertID},
+		{"ALERT_NAME", alert.Name},
+		{"ALERT_SEVERITY", alert.Severity},
+		{"CLUSTER_ID", alert.ClusterID},
+		{"CLUSTER_NAME", alert.ClusterName},
+		{"CONSOLE_URL", alert.Console},
+		{"SOP_URL", alert.Sop},
+		{"ALERT_LABELS", alert.Labels},
+		{"INCIDENT_URL", alert.WebURL},
+	} {
+		if value := strings.TrimSpace(variable.value); value != "" && value != "N/A" && value != "<nil>" {
+			env = append(env, variable.name+"="+value)
+		}
+	}
+
+	return env
+}
+
+// SubstitutePlaceholders replaces the placeholders of the alert fields in the given text by their value, e.g. "<cluster-id>",
+// "<CLUSTER_ID>", "${CLUSTER_ID}" or "$CLUSTER_ID" by the cluster ID of the alert. The placeholders of the unknown fields are kept.
+func SubstitutePlaceholders(text string, alert *pdcli.Alert) string {
+	for _, variable := range AlertEnv(alert) {
+		name, value, _ := strings.Cut(variable, "=")
+		words := strings.Join(strings.Split(name, "_"), `[-_ ]?`)
+		placeholder := regexp.MustCompile(`(?i)<` + words + `>|\$\{` + name + `\}|\$` + name + `\b`)
+		text = placeholder.ReplaceAllLiteralString(text, value)
+	}
+
+	return text
+}
+
+// launchTemplate returns the first launch template of the given kind of slide matching the selected alert, nil if there is none.
+func (tui *TUI) launchTemplate(slide string) (*config.LaunchTemplate, error) {
+	var name string
+
+	if tui.SelectedAlert != nil {
+		name = tui.SelectedAlert.Name
+	}
+
+	for i, launch := range tui.LaunchTemplates {
+		if launch.Slide != slide {
+			continue
+		}
+
+		matched, err := regexp.MatchString(launch.Alert, name)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert pattern of the '%s' launch template: %v", slide, err)
+		}
+
+		if matched {
+			return &tui.LaunchTemplates[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// expandTemplate executes the given launch template text with the selected alert.
+func (tui *TUI) expandTemplate(text string) (string, error) {
+	tmpl, err := template.New("launch").Parse(text)
+
+	if err != nil {
+		return "", err
+	}
+
+	var alert pdcli.Alert
+	if tui.SelectedAlert != nil {
+		alert = *tui.SelectedAlert
+	}
+
+	var out strings.Builder
+	err = tmpl.Execute(&out, alert)
+	return out.String(), err
+}
+
+// LaunchCommand returns the command run in a new slide of the given kind, customized by the matching launch template.
+// The context of the selected alert is passed to the program in its environment, the variables are also returned.
+func (tui *TUI) LaunchCommand(slide string, command string, args []string) (*exec.Cmd, []string, error) {
+	launch, err := tui.launchTemplate(slide)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if launch == nil {
+		return tui.templateCommand(command, nil, nil, args)
+	}
+
+	if launch.Command != "" {
+		command = launch.Command
+	}
+
+	if launch.Args == nil {
+		return tui.templateCommand(command, nil, launch.Env, args)
+	}
+
+	return tui.templateCommand(command, launch.Args, launch.Env, nil)
+}
+
+// templateCommand returns the command running the given program with the arguments and the environment templates
+// executed with the selected alert, the arguments are appended as they are.
+// The alert context variables and the template variables are added to the environment of the program, and returned.
+func (tui *TUI) templateCommand(command string, argTemplates []string, envTemplates map[string]string, args []string) (*exec.Cmd, []string, error) {
+	if _, err := exec.LookPath(command); err != nil {
+		return nil, nil, fmt.Errorf("'%s' is not found, install it or fix its path in the config file", command)
+	}
+
+	var expanded []string
+
+	for _, arg := range argTemplates {
+		value, err := tui.expandTemplate(arg)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid argument '%s': %v", arg, err)
+		}
+
+		expanded = append(expanded, value)
+	}
+
+	env := AlertEnv(tui.SelectedAlert)
+
+	// The variables are sorted to keep the environment stable
+	names := make([]string, 0, len(envTemplates))
+	for name := range envTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, err := tui.expandTemplate(envTemplates[name])
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid variable %s: %v", name, err)
+		}
+
+		env = append(env, name+"="+value)
+	}
+
+	cmd := exec.Command(command, append(expanded, args...)...)
+
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	return cmd, env, nil
+}
+
+// launchSlide adds a slide of the given kind running the given command, customized by the matching launch template.
+func (tui *TUI) launchSlide(slide string, name string, command string, args []string) {
+	cmd, env, err := tui.LaunchCommand(slide, command, args)
+
+	if err != nil {
+		utils.ErrorLogger.Printf("Cannot open the %s slide: %v", slide, err)
+		return
+	}
+
+	var key string
+
+	// A cluster is logged into once whatever the command run
+	if slide == ClusterSlide && tui.ClusterID != "" {
+		key = clusterSlideKey + tui.ClusterID
+	}
+
+	tui.addTerminalSlide(key, name, cmd, env)
+}
+
+// initLaunchers binds the keys of the configured launchers, the launchers which cannot be run are reported.
+func (tui *TUI) initLaunchers(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+
+	names := make(map[string]bool)
+
+	for _, launcher := range cfg.Launchers {
+		if launcher.Name == "" || launcher.Command == "" || names[launcher.Name] {
+			utils.ErrorLogger.Printf("Ignoring the launcher '%s': the launchers need a unique name and a command", launcher.Name)
+			continue
+		}
+
+		names[launcher.Name] = true
+
+		if _, err := exec.LookPath(launcher.Command); err != nil {
+			utils.ErrorLogger.Printf("The command of the launcher '%s' is not found: %s", launcher.Name, launcher.Command)
+		}
+
+		// The launcher stays available in the launcher list without its key
+		if launcher.Key != "" {
+			if err := tui.Keymap.Bind(LaunchAction(launcher.Name), launcher.Key, "Launch "+launcher.Name); err != nil {
+				utils.ErrorLogger.Printf("Invalid key for the launcher '%s': %v", launcher.Name, err)
+				launcher.Key = ""
+			}
+		}
+
+		tui.Launchers = append(tui.Launchers, launcher)
+	}
+}
+
+// launcherCommands returns the commands running the launchers bound to a key.
+func (tui *TUI) launcherCommands() []command {
+	var commands []command
+
+	for _, launcher := range tui.Launchers {
+		launcher := launcher
+
+		if launcher.Key != "" {
+			commands = append(commands, command{action: LaunchAction(launcher.Name), handler: func() { tui.runLauncher(launcher) }})
+		}
+	}
+
+	return commands
+}
+
+// runLauncher runs the given launcher in a new slide named after it.
+func (tui *TUI) runLauncher(launcher config.Launcher) {
+	cmd, env, err := tui.templateCommand(launcher.Command, launcher.Args, launcher.Env, nil)
+
+	if err != nil {
+		utils.ErrorLogger.Printf("Cannot run the launcher '%s': %v", launcher.Name, err)
+		return
+	}
+
+	tui.addTerminalSlide("", launcher.Name, cmd, env)
+}
+
+// showLauncherList displays the list of launchers, the selected launcher is run in a new slide
+func (tui *TUI) showLauncherList() {
+	if len(tui.Launchers) == 0 {
+		utils.InfoLogger.Println("No launchers configured, add them to the launchers section of the config file")
+		return
+	}
+
+	var items []listItem
+
+	for _, launcher := range tui.Launchers {
+		launcher := launcher
+		secondary := strings.Join(append([]string{launcher.Command}, launcher.Args...), " ")
+
+		if launcher.Key != "" {
+			secondary = fmt.Sprintf("%s  [%s]", secondary, tui.Keymap.Key(LaunchAction(launcher.Name)))
+		}
+
+		items = append(items, listItem{
+			text:      tview.Escape(launcher.Name),
+			secondary: tview.Escape(secondary),
+			selected:  func() { tui.runLauncher(launcher) },
+		})
+	}
+
+	tui.showListModal(LauncherListPageTitle, "Launchers", 70, items, 0)
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/rivo/tview"
+)
+
+// initMouse reads the mouse toggle from the config, mouse support is enabled unless disabled by the user.
+func (tui *TUI) initMouse(cfg *config.Config) {
+	tui.Mouse = cfg == nil || cfg.Mouse == nil || *cfg.Mouse
+}
+
+// ignoreFocusOnClick prevents the views which don't take any input from stealing the focus when clicked.
+// The other mouse actions, e.g. scrolling, are still handled by the view.
+func ignoreFocusOnClick(box interface {
+	SetMouseCapture(func(tview.MouseAction, *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse)) *tview.Box
+}) {
+	box.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action == tview.MouseLeftDown {
+			return action, nil
+		}
+		return action, event
+	})
+}
+
+// selectOnDoubleClick makes a double click on a table row behave like pressing Enter on it.
+// The row is selected by the first click of the double click.
+func (tui *TUI) selectOnDoubleClick(table *tview.Table) {
+	table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action != tview.MouseLeftDoubleClick || !table.InRect(event.Position()) {
+			return action, event
+		}
+
+		table.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(p tview.Primitive) {
+			tui.App.SetFocus(p)
+		})
+
+		return action, nil
+	})
+}
+
+// switchSlideOnClick switches to the slide whose title is clicked in the slide bar.
+// The slide bar never takes the focus, so that the key events keep going to the active slide.
+func (tui *TUI) switchSlideOnClick() {
+	tui.Mux.PageBar.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		switch action {
+		case tview.MouseLeftDown, tview.MouseScrollUp, tview.MouseScrollDown:
+			return action, nil
+		}
+		return action, event
+	})
+}
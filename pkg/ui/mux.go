@@ -0,0 +1,390 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Format of a slide title in the page bar, i.e the region ID, the broadcast marker, the slide number and the title
+const pageBarItemFmt = `["%d"]%s%d %s[""]  `
+
+// Marker of the slides receiving the broadcast input in the page bar
+const broadcastMarker = "⇄ "
+
+// Slide is a window of the terminal multiplexer.
+// A slide keeps its ID for its whole lifetime, its position changes when the slides are moved or removed.
+type Slide struct {
+	id    int
+	key   string
+	title string
+	view  *SplitView
+
+	// Set when the keys typed in the slide are broadcast to the other broadcast slides
+	broadcast bool
+}
+
+// ID returns the unique ID of the slide, which is also its page name and page bar region ID.
+func (s *Slide) ID() int {
+	return s.id
+}
+
+// Key returns the key the slide was added with, e.g. the ID of the cluster logged into.
+func (s *Slide) Key() string {
+	return s.key
+}
+
+// Title returns the title displayed in the page bar.
+func (s *Slide) Title() string {
+	return s.title
+}
+
+// View returns the panes of the slide.
+func (s *Slide) View() *SplitView {
+	return s.view
+}
+
+// Broadcast reports whether the slide sends and receives the broadcast input.
+func (s *Slide) Broadcast() bool {
+	return s.broadcast
+}
+
+// Mux is the terminal multiplexer, it displays the active slide and the page bar listing the slides.
+// The slides are tracked by identity: the active slide stays active when other slides are added, moved or removed.
+// All the methods must be called from the application event loop.
+type Mux struct {
+	Pages   *tview.Pages
+	PageBar *tview.TextView
+
+	slides []*Slide
+	active *Slide
+	nextID int
+
+	// Called when the last slide is removed, the slide is kept
+	onEmpty func()
+}
+
+// NewMux returns a multiplexer without any slide.
+func NewMux() *Mux {
+	m := &Mux{
+		Pages:   tview.NewPages(),
+		PageBar: tview.NewTextView(),
+	}
+
+	m.PageBar.
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false).
+		SetHighlightedFunc(func(added, removed, remaining []string) {
+			// Keep the active slide highlighted when clicking outside of the slide titles
+			if len(added) == 0 {
+				m.PageBar.Highlight(removed...)
+				return
+			}
+
+			// The slide can be switched by clicking its title
+			id, _ := strconv.Atoi(added[0])
+			if slide := m.slideByID(id); slide != nil {
+				m.Switch(slide)
+			}
+		})
+
+	return m
+}
+
+// SetEmptyFunc sets the function called when the last slide is removed.
+func (m *Mux) SetEmptyFunc(handler func()) *Mux {
+	m.onEmpty = handler
+	return m
+}
+
+// Slides returns the slides in the page bar order.
+func (m *Mux) Slides() []*Slide {
+	return append([]*Slide(nil), m.slides...)
+}
+
+// Len returns the number of slides.
+func (m *Mux) Len() int {
+	return len(m.slides)
+}
+
+// Active returns the active slide, nil if there is none.
+func (m *Mux) Active() *Slide {
+	return m.active
+}
+
+// ActiveIndex returns the position of the active slide, -1 if there is none.
+func (m *Mux) ActiveIndex() int {
+	return m.IndexOf(m.active)
+}
+
+// IndexOf returns the position of the given slide, -1 if it has been removed.
+func (m *Mux) IndexOf(slide *Slide) int {
+	for index, s := range m.slides {
+		if s == slide {
+			return index
+		}
+	}
+	return -1
+}
+
+// Slide returns the slide at the given position, nil if there is none.
+func (m *Mux) Slide(index int) *Slide {
+	if index < 0 || index >= len(m.slides) {
+		return nil
+	}
+	return m.slides[index]
+}
+
+// SlideByKey returns the slide added with the given key, nil if there is none.
+func (m *Mux) SlideByKey(key string) *Slide {
+	if key == "" {
+		return nil
+	}
+
+	for _, slide := range m.slides {
+		if slide.key == key {
+			return slide
+		}
+	}
+	return nil
+}
+
+// SlideOf returns the slide displaying the given primitive in one of its panes, nil if there is none.
+func (m *Mux) SlideOf(primitive tview.Primitive) *Slide {
+	for _, slide := range m.slides {
+		if slide.view.Find(primitive) != nil {
+			return slide
+		}
+	}
+	return nil
+}
+
+func (m *Mux) slideByID(id int) *Slide {
+	for _, slide := range m.slides {
+		if slide.id == id {
+			return slide
+		}
+	}
+	return nil
+}
+
+// Add adds a slide after the last one and switches to it.
+// The key identifies the slide for later lookups, e.g. to avoid logging into the same cluster twice.
+func (m *Mux) Add(key string, title string, view *SplitView) *Slide {
+	slide := &Slide{id: m.nextID, key: key, title: title, view: view}
+	m.nextID++
+
+	m.slides = append(m.slides, slide)
+	m.Pages.AddPage(strconv.Itoa(slide.id), view, true, false)
+	m.redrawPageBar()
+	m.Switch(slide)
+
+	return slide
+}
+
+// Remove removes the given slide, the previous slide becomes active if the slide was the active one.
+// It returns false if the slide has already been removed or is the last slide, which is not removed.
+func (m *Mux) Remove(slide *Slide) bool {
+	index := m.IndexOf(slide)
+
+	if index < 0 {
+		return false
+	}
+
+	if len(m.slides) == 1 {
+		if m.onEmpty != nil {
+			m.onEmpty()
+		}
+		return false
+	}
+
+	m.slides = append(m.slides[:index], m.slides[index+1:]...)
+	m.Pages.RemovePage(strconv.Itoa(slide.id))
+
+	if m.active == slide {
+		m.active = nil
+		if index > 0 {
+			index--
+		}
+		m.redrawPageBar()
+		m.Switch(m.slides[index])
+		return true
+	}
+
+	m.redrawPageBar()
+	return true
+}
+
+// Switch makes the given slide the active one.
+func (m *Mux) Switch(slide *Slide) {
+	if m.IndexOf(slide) < 0 {
+		return
+	}
+
+	m.active = slide
+	m.Pages.SwitchToPage(strconv.Itoa(slide.id))
+	m.PageBar.Highlight(strconv.Itoa(slide.id))
+	m.scrollPageBar()
+}
+
+// SwitchTo makes the slide with the given number the active one, slides are numbered from 1.
+func (m *Mux) SwitchTo(slideNum int) {
+	if slide := m.Slide(slideNum - 1); slide != nil {
+		m.Switch(slide)
+	}
+}
+
+// Next switches to the next slide, the first slide follows the last one.
+func (m *Mux) Next() {
+	if len(m.slides) > 0 {
+		m.Switch(m.slides[(m.ActiveIndex()+1)%len(m.slides)])
+	}
+}
+
+// Previous switches to the previous slide, the last slide precedes the first one.
+func (m *Mux) Previous() {
+	if len(m.slides) > 0 {
+		m.Switch(m.slides[(m.ActiveIndex()-1+len(m.slides))%len(m.slides)])
+	}
+}
+
+// Move moves the active slide by the given number of positions, e.g. -1 to move it to the left.
+func (m *Mux) Move(offset int) {
+	from := m.ActiveIndex()
+	to := from + offset
+
+	if from < 0 || to < 0 || to >= len(m.slides) {
+		return
+	}
+
+	m.slides[from], m.slides[to] = m.slides[to], m.slides[from]
+	m.redrawPageBar()
+}
+
+// Rename changes the title of the given slide.
+func (m *Mux) Rename(slide *Slide, title string) {
+	if m.IndexOf(slide) < 0 || title == "" {
+		return
+	}
+
+	slide.title = title
+	m.redrawPageBar()
+}
+
+// SetBroadcast adds the given slide to the slides sending and receiving the broadcast input, or removes it.
+func (m *Mux) SetBroadcast(slide *Slide, broadcast bool) {
+	if m.IndexOf(slide) < 0 {
+		return
+	}
+
+	slide.broadcast = broadcast
+	m.redrawPageBar()
+}
+
+// BroadcastSlides returns the slides sending and receiving the broadcast input, in the page bar order.
+func (m *Mux) BroadcastSlides() []*Slide {
+	var slides []*Slide
+
+	for _, slide := range m.slides {
+		if slide.broadcast {
+			slides = append(slides, slide)
+		}
+	}
+	return slides
+}
+
+// Find returns the slide matching the given slide number or name, nil if there is none.
+// Names are matched case-insensitively, exact matches take precedence over prefixes and substrings.
+func (m *Mux) Find(input string) *Slide {
+	input = strings.TrimSpace(input)
+
+	if slideNum, err := strconv.Atoi(input); err == nil && slideNum >= 0 {
+		return m.Slide(slideNum - 1)
+	}
+
+	input = strings.ToLower(input)
+
+	if input == "" {
+		return nil
+	}
+
+	for _, match := range []func(title string) bool{
+		func(title string) bool { return title == input },
+		func(title string) bool { return strings.HasPrefix(title, input) },
+		func(title string) bool { return strings.Contains(title, input) },
+	} {
+		for _, slide := range m.slides {
+			if match(strings.ToLower(slide.title)) {
+				return slide
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasTitlePrefix reports whether the title of a slide starts with the given text, ignoring the case.
+func (m *Mux) hasTitlePrefix(prefix string) bool {
+	prefix = strings.ToLower(prefix)
+
+	for _, slide := range m.slides {
+		if strings.HasPrefix(strings.ToLower(slide.title), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redrawPageBar redraws the slide titles in the page bar, the slides are numbered by their position.
+func (m *Mux) redrawPageBar() {
+	m.PageBar.Clear()
+
+	for index, slide := range m.slides {
+		fmt.Fprint(m.PageBar, m.pageBarItem(index, slide))
+	}
+
+	if m.active != nil {
+		m.PageBar.Highlight(strconv.Itoa(m.active.id))
+	}
+
+	m.scrollPageBar()
+}
+
+func (m *Mux) pageBarItem(index int, slide *Slide) string {
+	var marker string
+	if slide.broadcast {
+		marker = broadcastMarker
+	}
+
+	return fmt.Sprintf(pageBarItemFmt, slide.id, marker, index+1, tview.Escape(slide.title))
+}
+
+// scrollPageBar scrolls the page bar horizontally to keep the title of the active slide visible.
+func (m *Mux) scrollPageBar() {
+	_, _, width, _ := m.PageBar.GetInnerRect()
+	active := m.ActiveIndex()
+
+	if width <= 0 || active < 0 {
+		return
+	}
+
+	var start int
+	for index, slide := range m.slides[:active] {
+		start += tview.TaggedStringWidth(m.pageBarItem(index, slide))
+	}
+	end := start + tview.TaggedStringWidth(m.pageBarItem(active, m.active))
+
+	_, offset := m.PageBar.GetScrollOffset()
+
+	if start < offset {
+		offset = start
+	} else if end > offset+width {
+		offset = end - width
+	}
+
+	m.PageBar.ScrollTo(0, offset)
+}
@@ -0,0 +1,387 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+const (
+	// Size in percent of the panes created by a split
+	DefaultPaneSize = 50
+	// Size in percent by which a pane is grown or shrunk
+	PaneResizeStep = 5
+	// Minimum size in percent of a pane
+	MinPaneSize = 10
+)
+
+// Pane is a node of the layout of a slide.
+// A pane either hosts a primitive or is split in two or more panes,
+// either side by side (tview.FlexColumn) or stacked (tview.FlexRow).
+type Pane struct {
+	primitive tview.Primitive
+	commands  []command
+	state     PaneState
+	direction int
+	children  []*Pane
+	sizes     []int
+	parent    *Pane
+}
+
+// NewPane returns a pane hosting the given primitive.
+func NewPane(primitive tview.Primitive) *Pane {
+	return &Pane{primitive: primitive}
+}
+
+// Primitive returns the primitive hosted by the pane, nil for a split pane.
+func (p *Pane) Primitive() tview.Primitive {
+	return p.primitive
+}
+
+// isLeaf reports whether the pane hosts a primitive.
+func (p *Pane) isLeaf() bool {
+	return len(p.children) == 0
+}
+
+// leaves returns the panes hosting a primitive, in layout order.
+func (p *Pane) leaves() []*Pane {
+	if p.isLeaf() {
+		return []*Pane{p}
+	}
+
+	var leaves []*Pane
+
+	for _, child := range p.children {
+		leaves = append(leaves, child.leaves()...)
+	}
+
+	return leaves
+}
+
+// indexOf returns the index of the given child pane.
+func (p *Pane) indexOf(child *Pane) int {
+	for i, c := range p.children {
+		if c == child {
+			return i
+		}
+	}
+	return -1
+}
+
+// render builds the tview primitive displaying the pane.
+func (p *Pane) render() tview.Primitive {
+	if p.isLeaf() {
+		return p.primitive
+	}
+
+	flex := tview.NewFlex().SetDirection(p.direction)
+
+	for i, child := range p.children {
+		flex.AddItem(child.render(), 0, p.sizes[i], false)
+	}
+
+	return flex
+}
+
+// SplitView is the content of a slide, made of one or more panes.
+// The focus is given to the focused pane when the slide is displayed.
+type SplitView struct {
+	*tview.Flex
+	root    *Pane
+	focused *Pane
+}
+
+// NewSplitView returns a split view with a single pane hosting the given primitive.
+func NewSplitView(primitive tview.Primitive, commands []command) *SplitView {
+	pane := &Pane{primitive: primitive, commands: commands}
+	v := &SplitView{
+		Flex:    tview.NewFlex(),
+		root:    pane,
+		focused: pane,
+	}
+	v.build()
+	return v
+}
+
+// newSplitViewFromPane returns a split view displaying the given pane and the panes it is split in.
+func newSplitViewFromPane(root *Pane) *SplitView {
+	v := &SplitView{
+		Flex:    tview.NewFlex(),
+		root:    root,
+		focused: root.leaves()[0],
+	}
+	v.build()
+	return v
+}
+
+// build rebuilds the layout after the pane tree has changed.
+func (v *SplitView) build() {
+	v.Flex.Clear().AddItem(v.root.render(), 0, 1, true)
+}
+
+// Panes returns the panes hosting a primitive.
+func (v *SplitView) Panes() []*Pane {
+	return v.root.leaves()
+}
+
+// FocusedPane returns the pane which has the focus or had it last.
+func (v *SplitView) FocusedPane() *Pane {
+	for _, pane := range v.Panes() {
+		if pane.primitive.HasFocus() {
+			v.focused = pane
+		}
+	}
+	return v.focused
+}
+
+// Find returns the pane hosting the given primitive, or nil.
+func (v *SplitView) Find(primitive tview.Primitive) *Pane {
+	for _, pane := range v.Panes() {
+		if pane.primitive == primitive {
+			return pane
+		}
+	}
+	return nil
+}
+
+// Split splits the focused pane in two, the given pane is added next to it and gets the focus.
+// The given pane can itself be split, e.g. the root pane of another slide.
+func (v *SplitView) Split(pane *Pane, direction int) {
+	target := v.FocusedPane()
+
+	// Panes split in the same direction as their parent are added to the parent
+	if parent := target.parent; parent != nil && parent.direction == direction {
+		index := parent.indexOf(target)
+		size := parent.sizes[index] / 2
+
+		pane.parent = parent
+		parent.sizes[index] -= size
+		parent.children = append(parent.children[:index+1], append([]*Pane{pane}, parent.children[index+1:]...)...)
+		parent.sizes = append(parent.sizes[:index+1], append([]int{size}, parent.sizes[index+1:]...)...)
+	} else {
+		// The target becomes a split pane hosting its previous content and the new pane
+		existing := &Pane{primitive: target.primitive, commands: target.commands, state: target.state, parent: target}
+		pane.parent = target
+
+		target.primitive = nil
+		target.commands = nil
+		target.state = PaneState{}
+		target.direction = direction
+		target.children = []*Pane{existing, pane}
+		target.sizes = []int{DefaultPaneSize, DefaultPaneSize}
+	}
+
+	v.focused = pane.leaves()[0]
+	v.build()
+}
+
+// Close removes the given pane from the layout.
+// It returns false if the pane is the last one of the slide, which is not removed.
+func (v *SplitView) Close(pane *Pane) bool {
+	parent := pane.parent
+
+	if parent == nil {
+		return false
+	}
+
+	index := parent.indexOf(pane)
+	parent.children = append(parent.children[:index], parent.children[index+1:]...)
+	parent.sizes = append(parent.sizes[:index], parent.sizes[index+1:]...)
+
+	// A split pane with a single child is replaced by the child
+	if len(parent.children) == 1 {
+		child := parent.children[0]
+		parent.primitive = child.primitive
+		parent.commands = child.commands
+		parent.state = child.state
+		parent.direction = child.direction
+		parent.children = child.children
+		parent.sizes = child.sizes
+
+		for _, c := range parent.children {
+			c.parent = parent
+		}
+	}
+
+	// The remaining panes may have been moved up in the tree
+	focused := v.focused
+	v.focused = v.Panes()[0]
+
+	if focused != pane {
+		if p := v.Find(focused.primitive); p != nil {
+			v.focused = p
+		}
+	}
+
+	v.build()
+
+	return true
+}
+
+// Resize grows the focused pane by the given size in percent, shrinking its siblings.
+func (v *SplitView) Resize(delta int) {
+	pane := v.FocusedPane()
+	parent := pane.parent
+
+	if parent == nil {
+		return
+	}
+
+	index := parent.indexOf(pane)
+	sibling := index + 1
+
+	if sibling == len(parent.children) {
+		sibling = index - 1
+	}
+
+	size := parent.sizes[index] + delta
+	siblingSize := parent.sizes[sibling] - delta
+
+	if size < MinPaneSize || siblingSize < MinPaneSize {
+		return
+	}
+
+	parent.sizes[index] = size
+	parent.sizes[sibling] = siblingSize
+	v.build()
+}
+
+// MoveFocus gives the focus to the nearest pane in the given direction, e.g. (-1, 0) for the pane on the left.
+// It returns the pane which has the focus afterwards.
+func (v *SplitView) MoveFocus(dx int, dy int) *Pane {
+	current := v.FocusedPane()
+	x, y, w, h := current.primitive.GetRect()
+	cx, cy := 2*x+w, 2*y+h
+
+	var nearest *Pane
+	var nearestDistance int
+
+	for _, pane := range v.Panes() {
+		if pane == current {
+			continue
+		}
+
+		px, py, pw, ph := pane.primitive.GetRect()
+
+		// Only the panes entirely in the given direction are candidates
+		if (dx < 0 && px+pw > x) || (dx > 0 && px < x+w) || (dy < 0 && py+ph > y) || (dy > 0 && py < y+h) {
+			continue
+		}
+
+		// Distance between the centers of the panes
+		distance := abs(2*px+pw-cx) + abs(2*py+ph-cy)
+
+		if nearest == nil || distance < nearestDistance {
+			nearest = pane
+			nearestDistance = distance
+		}
+	}
+
+	if nearest != nil {
+		v.focused = nearest
+	}
+
+	return v.focused
+}
+
+// Focus gives the focus to the focused pane.
+func (v *SplitView) Focus(delegate func(p tview.Primitive)) {
+	delegate(v.FocusedPane().primitive)
+}
+
+// Draw keeps track of the focused pane, the focus can be changed by mouse clicks.
+func (v *SplitView) Draw(screen tcell.Screen) {
+	v.FocusedPane()
+	v.Flex.Draw(screen)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// splitPane splits the focused pane of the active slide, the new pane hosts a shell.
+func (tui *TUI) splitPane(direction int) {
+	slide := tui.Mux.Active()
+
+	if slide == nil {
+		return
+	}
+
+	cmd, env, err := tui.LaunchCommand(ShellSlide, os.Getenv("SHELL"), []string{})
+
+	if err != nil {
+		utils.ErrorLogger.Printf("Cannot open the shell pane: %v", err)
+		return
+	}
+
+	term := newTerminalView(cmd, tui)
+	term.env = env
+	slide.view.Split(NewPane(term), direction)
+	tui.App.SetFocus(slide.view)
+}
+
+// closePane closes the focused pane of the active slide.
+// The slide is exited when its last pane is closed.
+func (tui *TUI) closePane() {
+	slide := tui.Mux.Active()
+
+	if slide == nil {
+		return
+	}
+
+	pane := slide.view.FocusedPane()
+
+	if pane.primitive == tui.Layout {
+		utils.ErrorLogger.Print("The kite pane cannot be closed")
+		return
+	}
+
+	if !slide.view.Close(pane) {
+		tui.exitSlide(slide)
+		return
+	}
+
+	if term, ok := pane.primitive.(*Terminal); ok {
+		term.Close()
+	}
+
+	tui.App.SetFocus(slide.view)
+}
+
+// focusPane gives the focus to the nearest pane of the active slide in the given direction.
+func (tui *TUI) focusPane(dx int, dy int) {
+	if slide := tui.Mux.Active(); slide != nil {
+		tui.App.SetFocus(slide.view.MoveFocus(dx, dy).primitive)
+	}
+}
+
+// resizePane grows or shrinks the focused pane of the active slide.
+func (tui *TUI) resizePane(delta int) {
+	if slide := tui.Mux.Active(); slide != nil {
+		slide.view.Resize(delta)
+	}
+}
+
+// joinSlide moves the panes of the given slide to a new pane of the active slide.
+func (tui *TUI) joinSlide(source *Slide) {
+	target := tui.Mux.Active()
+
+	if target == nil {
+		return
+	}
+
+	if source == target || source.view.Find(tui.Layout) != nil {
+		utils.ErrorLogger.Printf("Slide '%s' cannot be moved to a pane of the active slide", source.title)
+		return
+	}
+
+	// The active slide stays active when the source slide is removed
+	target.view.Split(source.view.root, tview.FlexColumn)
+	tui.Mux.Remove(source)
+	tui.App.SetFocus(target.view)
+}
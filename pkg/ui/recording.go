@@ -0,0 +1,363 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/recording"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+// terminalRecorder records a terminal to an asciicast file.
+// The screen is recorded as it is when the recording starts, then the output of the program as it is read.
+type terminalRecorder struct {
+	cast *recording.Recorder
+
+	// Start of a character split between two reads of the output
+	pending []byte
+}
+
+// frameCell is a cell of a recorded frame.
+type frameCell struct {
+	content   rune
+	combining string
+	style     tcell.Style
+}
+
+// frameSurface is a terminal surface keeping the cells drawn on it.
+type frameSurface struct {
+	cells  []frameCell
+	width  int
+	height int
+}
+
+func (f *frameSurface) reset(width int, height int) {
+	if len(f.cells) != width*height {
+		f.cells = make([]frameCell, width*height)
+	} else {
+		for i := range f.cells {
+			f.cells[i] = frameCell{}
+		}
+	}
+
+	f.width = width
+	f.height = height
+}
+
+func (f *frameSurface) SetContent(x int, y int, mainc rune, combc []rune, style tcell.Style) {
+	if x < 0 || x >= f.width || y < 0 || y >= f.height {
+		return
+	}
+
+	if mainc == 0 {
+		mainc = ' '
+	}
+
+	f.cells[y*f.width+x] = frameCell{content: mainc, combining: string(combc), style: style}
+}
+
+func (f *frameSurface) Size() (int, int) {
+	return f.width, f.height
+}
+
+// encode returns the output writing the frame on a cleared screen, the cursor being at the given position.
+func (f *frameSurface) encode(cursorRow int, cursorCol int, cursorVisible bool) string {
+	var out strings.Builder
+
+	out.WriteString("\x1b[H\x1b[0m\x1b[2J")
+
+	var style tcell.Style
+	styleSet := false
+	row, col := -1, -1
+
+	for i, cell := range f.cells {
+		// The cells covered by wide characters are left empty
+		if cell.content == 0 {
+			continue
+		}
+
+		if i/f.width != row || i%f.width != col {
+			row, col = i/f.width, i%f.width
+			fmt.Fprintf(&out, "\x1b[%d;%dH", row+1, col+1)
+		}
+
+		if !styleSet || cell.style != style {
+			style, styleSet = cell.style, true
+			out.WriteString(sgr(style))
+		}
+
+		out.WriteRune(cell.content)
+		out.WriteString(cell.combining)
+		col += runewidth.RuneWidth(cell.content)
+	}
+
+	if styleSet {
+		out.WriteString("\x1b[0m")
+	}
+
+	fmt.Fprintf(&out, "\x1b[%d;%dH", cursorRow+1, cursorCol+1)
+
+	if cursorVisible {
+		out.WriteString("\x1b[?25h")
+	} else {
+		out.WriteString("\x1b[?25l")
+	}
+
+	return out.String()
+}
+
+// completeLength returns the length of the data without the start of a character it may end with.
+func completeLength(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(data[i]) {
+			continue
+		}
+
+		if utf8.FullRune(data[i:]) {
+			return len(data)
+		}
+
+		return i
+	}
+
+	return len(data)
+}
+
+// sgr returns the escape sequence setting the given style.
+func sgr(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+	params := []string{"0"}
+
+	for _, attr := range []struct {
+		mask  tcell.AttrMask
+		param string
+	}{
+		{tcell.AttrBold, "1"},
+		{tcell.AttrDim, "2"},
+		{tcell.AttrItalic, "3"},
+		{tcell.AttrUnderline, "4"},
+		{tcell.AttrBlink, "5"},
+		{tcell.AttrReverse, "7"},
+		{tcell.AttrStrikeThrough, "9"},
+	} {
+		if attrs&attr.mask != 0 {
+			params = append(params, attr.param)
+		}
+	}
+
+	params = append(params, colorParams(fg, 38)...)
+	params = append(params, colorParams(bg, 48)...)
+
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// colorParams returns the SGR parameters setting the given color, with the given parameter for extended colors.
+func colorParams(color tcell.Color, extended int) []string {
+	switch {
+	case color == tcell.ColorDefault || !color.Valid():
+		return nil
+	case color.IsRGB():
+		r, g, b := color.RGB()
+		return []string{strconv.Itoa(extended), "2", strconv.Itoa(int(r)), strconv.Itoa(int(g)), strconv.Itoa(int(b))}
+	default:
+		return []string{strconv.Itoa(extended), "5", strconv.Itoa(int(color - tcell.ColorValid))}
+	}
+}
+
+// StartRecording records the terminal to a new asciicast file with the given name and title.
+// It returns the name of the recording, a suffix is added to the given name if it is already used.
+func (t *Terminal) StartRecording(name string, title string) (string, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.recorder != nil {
+		return "", fmt.Errorf("the terminal is already recorded to '%s'", t.recorder.cast.Name())
+	}
+
+	if !t.running {
+		return "", fmt.Errorf("the terminal is not running")
+	}
+
+	cast, err := recording.Create(name, t.w, t.h, title)
+
+	if err != nil {
+		return "", err
+	}
+
+	// The recording starts with the screen as it is, the lines already in the scrollback are not recorded
+	var frame frameSurface
+	frame.reset(t.w, t.h)
+	t.term.SetSurface(&frame)
+	t.term.Draw()
+
+	row, col, _, visible := t.term.Cursor()
+
+	if err = cast.Output(frame.encode(row, col, visible)); err != nil {
+		cast.Close()
+		return "", err
+	}
+
+	t.recorder = &terminalRecorder{cast: cast}
+
+	return cast.Name(), nil
+}
+
+// StopRecording ends the recording of the terminal, it returns the name of the recording.
+func (t *Terminal) StopRecording() (string, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.recorder == nil {
+		return "", fmt.Errorf("the terminal is not recorded")
+	}
+
+	rec := t.recorder
+	t.recorder = nil
+
+	return rec.cast.Name(), rec.cast.Close()
+}
+
+// IsRecording reports whether the terminal is recorded.
+func (t *Terminal) IsRecording() bool {
+	t.RLock()
+	defer t.RUnlock()
+	return t.recorder != nil
+}
+
+// terminalOutput receives the output of the program of a terminal as it is read, it is recorded while the terminal is recorded.
+type terminalOutput struct {
+	t *Terminal
+}
+
+func (o terminalOutput) Write(data []byte) (int, error) {
+	o.t.Lock()
+	defer o.t.Unlock()
+
+	rec := o.t.recorder
+
+	if rec == nil {
+		return len(data), nil
+	}
+
+	// The characters split between two reads are recorded once complete
+	output := append(rec.pending, data...)
+	length := completeLength(output)
+	rec.pending = append([]byte(nil), output[length:]...)
+
+	if length > 0 {
+		if err := rec.cast.Output(string(output[:length])); err != nil {
+			utils.ErrorLogger.Printf("Cannot record the terminal: %v", err)
+		}
+	}
+
+	return len(data), nil
+}
+
+// recordResize records the new size of the terminal.
+// The terminal must be locked.
+func (t *Terminal) recordResize() {
+	if err := t.recorder.cast.Resize(t.w, t.h); err != nil {
+		utils.ErrorLogger.Printf("Cannot record the terminal: %v", err)
+	}
+}
+
+// drawRecordingIndicator marks the border of a terminal which is recorded.
+func (t *Terminal) drawRecordingIndicator(screen tcell.Screen) {
+	x, y, width, _ := t.GetRect()
+	tview.Print(screen, " ● REC ", x+1, y, width-2, tview.AlignRight, tcell.ColorRed)
+}
+
+// toggleRecording starts or stops the recording of the focused terminal.
+// The recordings are named after the incident and the cluster of the terminal, or the selected incident and the slide title.
+func (tui *TUI) toggleRecording() {
+	term := tui.focusedTerminal()
+
+	if term == nil {
+		utils.ErrorLogger.Println("Only the terminal slides can be recorded")
+		return
+	}
+
+	if term.IsRecording() {
+		name, err := term.StopRecording()
+
+		if err != nil {
+			utils.ErrorLogger.Printf("Cannot save the recording '%s': %v", name, err)
+			return
+		}
+
+		utils.InfoLogger.Printf("Recording saved as '%s'", name)
+		return
+	}
+
+	incidentID, cluster := term.incidentID, term.cluster
+	title := cluster
+
+	if slide := tui.Mux.Active(); slide != nil {
+		title = slide.Title()
+	}
+
+	if incidentID == "" {
+		incidentID = tui.IncidentID
+	}
+
+	if cluster == "" {
+		cluster = title
+	}
+
+	name, err := term.StartRecording(recording.Name(incidentID, cluster, time.Now()), title)
+
+	if err != nil {
+		utils.ErrorLogger.Printf("Cannot record the terminal: %v", err)
+		return
+	}
+
+	utils.InfoLogger.Printf("Recording the terminal as '%s'", name)
+}
+
+// showRecordingList displays the list of recordings, the selected recording is replayed in a new slide
+func (tui *TUI) showRecordingList() {
+	recordings, err := recording.List()
+
+	if err != nil {
+		utils.ErrorLogger.Printf("Cannot list the recordings: %v", err)
+		return
+	}
+
+	if len(recordings) == 0 {
+		utils.InfoLogger.Println("No recordings found, start one with the toggle recording key in a terminal slide")
+		return
+	}
+
+	var items []listItem
+
+	for _, rec := range recordings {
+		rec := rec
+		items = append(items, listItem{
+			text:      tview.Escape(rec.Name),
+			secondary: tview.Escape(fmt.Sprintf("%s, %s", rec.Start().Format(time.RFC822), rec.Duration.Round(time.Second))),
+			selected:  func() { tui.replayRecording(rec.Name) },
+		})
+	}
+
+	// The latest recordings are the most likely to be replayed
+	tui.showListModal(RecordingListPageTitle, "Recordings", 70, items, len(recordings)-1)
+}
+
+// replayRecording replays the recording with the given name in a new slide, with kite recordings play
+func (tui *TUI) replayRecording(name string) {
+	kite, err := os.Executable()
+
+	if err != nil {
+		utils.ErrorLogger.Printf("Cannot replay the recording '%s': %v", name, err)
+		return
+	}
+
+	AddNewSlide(tui, "replay "+name, kite, []string{"recordings", "play", "--wait", name}, false)
+}
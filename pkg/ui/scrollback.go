@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ScrollbackLines is the number of lines kept in the scrollback of a terminal
+const ScrollbackLines = 10000
+
+// Scrollback keeps the lines which scrolled off the top of a terminal screen.
+// The lines are added by the terminal as it scrolls, its program may be writing while they are read.
+type Scrollback struct {
+	sync.Mutex
+
+	limit int
+	lines []string
+}
+
+// NewScrollback returns an empty scrollback keeping up to limit lines.
+func NewScrollback(limit int) *Scrollback {
+	return &Scrollback{limit: limit}
+}
+
+// Lines returns the lines of the scrollback, the oldest first.
+func (sb *Scrollback) Lines() []string {
+	sb.Lock()
+	defer sb.Unlock()
+
+	lines := sb.lines
+
+	if len(lines) > sb.limit {
+		lines = lines[len(lines)-sb.limit:]
+	}
+
+	return append([]string(nil), lines...)
+}
+
+// Add adds the lines which scrolled off the screen, the oldest first.
+func (sb *Scrollback) Add(lines []string) {
+	sb.Lock()
+	defer sb.Unlock()
+
+	sb.lines = append(sb.lines, lines...)
+
+	// The oldest lines are dropped in batches rather than for every new line
+	if len(sb.lines) > sb.limit+sb.limit/10 {
+		sb.lines = append([]string(nil), sb.lines[len(sb.lines)-sb.limit:]...)
+	}
+}
+
+// screenCapture is a terminal surface keeping the text drawn on it.
+// The cells covered by wide characters are left empty.
+type screenCapture struct {
+	cells [][]rune
+}
+
+// reset empties the capture and gives it the given size.
+func (c *screenCapture) reset(width int, height int) {
+	if len(c.cells) != height || (height > 0 && len(c.cells[0]) != width) {
+		c.cells = make([][]rune, height)
+
+		for row := range c.cells {
+			c.cells[row] = make([]rune, width)
+		}
+
+		return
+	}
+
+	for _, cells := range c.cells {
+		for col := range cells {
+			cells[col] = 0
+		}
+	}
+}
+
+func (c *screenCapture) SetContent(x int, y int, mainc rune, combc []rune, style tcell.Style) {
+	if y < 0 || y >= len(c.cells) || x < 0 || x >= len(c.cells[y]) {
+		return
+	}
+
+	if mainc == 0 {
+		mainc = ' '
+	}
+
+	c.cells[y][x] = mainc
+}
+
+func (c *screenCapture) Size() (int, int) {
+	if len(c.cells) == 0 {
+		return 0, 0
+	}
+
+	return len(c.cells[0]), len(c.cells)
+}
+
+// lines returns the text of the captured rows without their trailing spaces.
+func (c *screenCapture) lines() []string {
+	lines := make([]string, len(c.cells))
+
+	var line strings.Builder
+
+	for row, cells := range c.cells {
+		line.Reset()
+
+		for _, r := range cells {
+			if r != 0 {
+				line.WriteRune(r)
+			}
+		}
+
+		lines[row] = strings.TrimRight(line.String(), " ")
+	}
+
+	return lines
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
@@ -31,7 +33,6 @@ func (tui *TUI) SeedAckIncidentsUI() {
 	tui.Incidents = ackIncidents
 
 	tui.InitIncidentsUI(tui.Incidents, AckIncidentsTableTitle, AckIncidentsPageTitle, false)
-	tui.Footer.SetText(FooterTextAckIncidents)
 	tui.Pages.SwitchToPage(AckIncidentsPageTitle)
 }
 
@@ -43,8 +44,14 @@ func (tui *TUI) SeedIncidentsUI() {
 	tui.IncidentOpts.Statuses = []string{constants.StatusTriggered}
 
 	// Override incidents limit when viewing triggered incidents
-	utils.InfoLogger.Printf("Incidents limit set to: %d", constants.TrigerredIncidentsLimit)
-	tui.IncidentOpts.Limit = constants.TrigerredIncidentsLimit
+	limit := tui.TriggeredLimit
+
+	if limit == 0 {
+		limit = constants.TrigerredIncidentsLimit
+	}
+
+	utils.InfoLogger.Printf("Incidents limit set to: %d", limit)
+	tui.IncidentOpts.Limit = limit
 
 	// Fetch new incidents via PD API
 	utils.InfoLogger.Print("GET: fetching incidents")
@@ -64,7 +71,6 @@ func (tui *TUI) SeedIncidentsUI() {
 	tui.Incidents = incidentsData
 
 	tui.InitIncidentsUI(tui.Incidents, IncidentsTableTitle, IncidentsPageTitle, true)
-	tui.Footer.SetText(FooterTextIncidents)
 }
 
 // SeedIncidentsUI fetches acknowledged incident alerts and initializes a TUI table/page component.
@@ -107,3 +113,33 @@ func (tui *TUI) SeedAlertsUI() {
 
 	tui.InitAlertsUI(tui.Alerts, AlertsTableTitle, AlertsPageTitle)
 }
+
+// StartAutoRefresh refreshes the alerts every interval while the alerts table is displayed, nothing is refreshed with no interval.
+// The alerts are refreshed as with the refresh key, the selected row is kept.
+func (tui *TUI) StartAutoRefresh(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	utils.InfoLogger.Printf("Alerts refreshed every %s", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			tui.App.QueueUpdateDraw(func() {
+				if page, _ := tui.Pages.GetFrontPage(); page != AlertsPageTitle {
+					return
+				}
+
+				row, _ := tui.Table.GetSelection()
+				tui.refreshAlerts()
+
+				if row < tui.Table.GetRowCount() {
+					tui.Table.Select(row, 0)
+				}
+			})
+		}
+	}()
+}
@@ -0,0 +1,371 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+	"github.com/rivo/tview"
+)
+
+const (
+	// Directory in the config directory containing the saved sessions
+	SessionsDir = "sessions"
+	// Name of the session saved when none is given
+	DefaultSession = "default"
+)
+
+// Types of the pane contents saved in a session
+const (
+	PaneKite     = "kite"
+	PaneTerminal = "terminal"
+	PaneSOP      = "sop"
+)
+
+// Directions of the split panes saved in a session, named after the split actions
+const (
+	SplitVertical   = "vertical"
+	SplitHorizontal = "horizontal"
+)
+
+// Session is the state of the terminal multiplexer saved to a session file.
+type Session struct {
+	Name    string    `json:"name"`
+	SavedAt time.Time `json:"saved_at"`
+
+	// Args are the kite arguments the session was started with, e.g. ["alerts", "--assigned-to=self"]
+	Args []string `json:"args,omitempty"`
+
+	// Active is the position of the active slide
+	Active int          `json:"active"`
+	Slides []SlideState `json:"slides"`
+}
+
+// SlideState is a slide saved in a session.
+type SlideState struct {
+	Title  string    `json:"title"`
+	Key    string    `json:"key,omitempty"`
+	Layout PaneState `json:"layout"`
+}
+
+// PaneState is a pane saved in a session, either split in several panes or hosting some content.
+type PaneState struct {
+	// Split pane
+	Split string      `json:"split,omitempty"`
+	Sizes []int       `json:"sizes,omitempty"`
+	Panes []PaneState `json:"panes,omitempty"`
+
+	// Content of the pane
+	Type    string   `json:"type,omitempty"`
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+	Dir     string   `json:"dir,omitempty"`
+	Env     []string `json:"env,omitempty"`
+	URL     string   `json:"url,omitempty"`
+
+	// Incident and cluster a terminal was opened for, its recordings are named after them
+	IncidentID string `json:"incident_id,omitempty"`
+	Cluster    string `json:"cluster,omitempty"`
+}
+
+// SessionPath returns the path of the session file with the given name.
+func SessionPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid session name '%s'", name)
+	}
+
+	configDir, err := config.Dir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, SessionsDir, name+".json"), nil
+}
+
+// LoadSession reads the session with the given name.
+func LoadSession(name string) (*Session, error) {
+	path, err := SessionPath(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read session '%s': %v", name, err)
+	}
+
+	var session Session
+
+	err = json.Unmarshal(data, &session)
+
+	if err != nil {
+		return nil, fmt.Errorf("error parsing session '%s': %v", name, err)
+	}
+
+	session.Name = name
+
+	return &session, nil
+}
+
+// SaveSession writes the given session to the session file of the same name.
+func SaveSession(session *Session) error {
+	path, err := SessionPath(session.Name)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// ListSessions returns the names of the saved sessions.
+func ListSessions() ([]string, error) {
+	configDir, err := config.Dir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(configDir, SessionsDir, "*.json"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(file), ".json"))
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// CurrentSession returns the current state of the terminal multiplexer.
+func (tui *TUI) CurrentSession() *Session {
+	session := &Session{
+		Name:    tui.SessionName,
+		SavedAt: time.Now(),
+		Args:    tui.SessionArgs,
+		Active:  tui.Mux.ActiveIndex(),
+	}
+
+	if session.Name == "" {
+		session.Name = DefaultSession
+	}
+
+	for _, slide := range tui.Mux.Slides() {
+		session.Slides = append(session.Slides, SlideState{
+			Title:  slide.title,
+			Key:    slide.key,
+			Layout: tui.paneState(slide.view.root),
+		})
+	}
+
+	return session
+}
+
+// paneState returns the state of the given pane and of the panes it is split in.
+func (tui *TUI) paneState(pane *Pane) PaneState {
+	if !pane.isLeaf() {
+		state := PaneState{Split: SplitVertical, Sizes: append([]int(nil), pane.sizes...)}
+
+		if pane.direction == tview.FlexRow {
+			state.Split = SplitHorizontal
+		}
+
+		for _, child := range pane.children {
+			state.Panes = append(state.Panes, tui.paneState(child))
+		}
+
+		return state
+	}
+
+	switch primitive := pane.primitive.(type) {
+	case *Terminal:
+		return PaneState{
+			Type:    PaneTerminal,
+			Command: primitive.cmd.Path,
+			Args:    primitive.cmd.Args[1:],
+			Dir:     primitive.workingDir(),
+			Env:     primitive.env,
+
+			IncidentID: primitive.incidentID,
+			Cluster:    primitive.cluster,
+		}
+	}
+
+	if pane.primitive == tui.Layout {
+		return PaneState{Type: PaneKite}
+	}
+
+	return pane.state
+}
+
+// SaveCurrentSession saves the slides to the session file.
+func (tui *TUI) SaveCurrentSession() error {
+	session := tui.CurrentSession()
+	err := SaveSession(session)
+
+	if err != nil {
+		return fmt.Errorf("cannot save session '%s': %v", session.Name, err)
+	}
+
+	return nil
+}
+
+// saveSession saves the slides on demand and reports the result in the log window.
+// The session is saved again when kite exits from then on.
+func (tui *TUI) saveSession() {
+	if err := tui.SaveCurrentSession(); err != nil {
+		utils.ErrorLogger.Print(err)
+		return
+	}
+
+	tui.sessionSaved = true
+
+	utils.InfoLogger.Printf("Session '%s' saved", tui.CurrentSession().Name)
+}
+
+// RestoreSession re-creates the slides of the given session.
+// The kite slide is moved to its saved position, the programs are launched again in their working directories.
+func (tui *TUI) RestoreSession(session *Session) {
+	kiteSlide := tui.Mux.Slide(0)
+	restored := make(map[int]*Slide)
+	var restoredKite bool
+
+	for index, state := range session.Slides {
+		root := tui.restorePane(state.Layout, &restoredKite)
+
+		if root == nil {
+			utils.ErrorLogger.Printf("Slide '%s' of session '%s' cannot be restored", state.Title, session.Name)
+			continue
+		}
+
+		restored[index] = tui.Mux.Add(state.Key, state.Title, newSplitViewFromPane(root))
+	}
+
+	// The kite layout has been moved to one of the restored slides
+	if restoredKite {
+		tui.Mux.Remove(kiteSlide)
+	}
+
+	if slide, ok := restored[session.Active]; ok {
+		tui.Mux.Switch(slide)
+	} else if !restoredKite {
+		tui.Mux.Switch(kiteSlide)
+	}
+}
+
+// restorePane re-creates a saved pane, nil if none of its content can be restored.
+// The kite layout is only displayed once, restoredKite reports whether it has already been restored.
+func (tui *TUI) restorePane(state PaneState, restoredKite *bool) *Pane {
+	if len(state.Panes) > 0 {
+		pane := &Pane{direction: tview.FlexColumn}
+
+		if state.Split == SplitHorizontal {
+			pane.direction = tview.FlexRow
+		}
+
+		for i, childState := range state.Panes {
+			child := tui.restorePane(childState, restoredKite)
+
+			if child == nil {
+				continue
+			}
+
+			size := DefaultPaneSize
+
+			if i < len(state.Sizes) && state.Sizes[i] > 0 {
+				size = state.Sizes[i]
+			}
+
+			child.parent = pane
+			pane.children = append(pane.children, child)
+			pane.sizes = append(pane.sizes, size)
+		}
+
+		switch len(pane.children) {
+		case 0:
+			return nil
+		case 1:
+			pane.children[0].parent = nil
+			return pane.children[0]
+		}
+
+		return pane
+	}
+
+	switch state.Type {
+	case PaneKite:
+		if !*restoredKite {
+			*restoredKite = true
+			return &Pane{primitive: tui.Layout}
+		}
+
+	case PaneTerminal:
+		command, err := lookPath(state.Command)
+
+		if err != nil {
+			utils.ErrorLogger.Printf("Cannot restore '%s': %v", state.Command, err)
+			return nil
+		}
+
+		cmd := exec.Command(command, state.Args...)
+
+		if info, err := os.Stat(state.Dir); err == nil && info.IsDir() {
+			cmd.Dir = state.Dir
+		}
+
+		if len(state.Env) > 0 {
+			cmd.Env = append(os.Environ(), state.Env...)
+		}
+
+		term := newTerminalView(cmd, tui)
+		term.env = state.Env
+		term.incidentID = state.IncidentID
+		term.cluster = state.Cluster
+
+		return &Pane{primitive: term}
+
+	case PaneSOP:
+		if state.URL != "" {
+			_, textView := tui.newSOPView(state.URL)
+			return &Pane{primitive: textView, commands: tui.sopCommands(), state: state}
+		}
+	}
+
+	return nil
+}
+
+// lookPath returns the saved command if it still exists, or the command of the same name found in the PATH.
+func lookPath(command string) (string, error) {
+	if path, err := exec.LookPath(command); err == nil {
+		return path, nil
+	}
+	return exec.LookPath(filepath.Base(command))
+}
@@ -6,42 +6,369 @@ import (
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/markdown"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 	"github.com/rivo/tview"
 )
 
+// sopBrowser displays the SOPs and follows their links, the SOPs visited are kept in a history.
+type sopBrowser struct {
+	*tview.TextView
+	tui *TUI
+
+	// location is the URL displayed, with the anchor scrolled to, document is the URL of the SOP rendered in doc
+	location string
+	document string
+	doc      *markdown.Document
+
+	back    []string
+	forward []string
+}
+
 // App Setup
 func ViewAlertSOP(tui *TUI, URL string) {
-	textView := tview.NewTextView().
-		SetDynamicColors(true).
-		SetRegions(true).
-		SetChangedFunc(func() {
-			tui.App.Draw()
-		})
-	utils.FetchHTMLContent(URL, textView)
-	readmePath := strings.Split(utils.GetReadmePath(URL), "/")
-	name := readmePath[len(readmePath)-1]
-	textView.Highlight("0").SetBorder(true).SetTitle(fmt.Sprintf(" %s ", name))
-	AddSOPSlide(name, textView, tui)
+	name, browser := tui.newSOPView(URL)
+	AddSOPSlide(name, browser, tui)
+
+	// The SOP is reopened when the session is restored
+	browser.saveState()
+}
+
+// newSOPView returns the name of the SOP and the browser displaying it
+func (tui *TUI) newSOPView(URL string) (string, *sopBrowser) {
+	b := &sopBrowser{
+		TextView: tview.NewTextView().
+			SetDynamicColors(true).
+			SetRegions(true).
+			SetChangedFunc(func() {
+				tui.App.Draw()
+			}),
+		tui: tui,
+		doc: &markdown.Document{},
+	}
+	document, _, _ := strings.Cut(URL, "#")
+	name := utils.SOPName(document)
+	b.SetBorder(true).SetTitle(fmt.Sprintf(" %s ", name))
+
+	if !b.load(URL) {
+		b.location = URL
+	}
+
+	// Mouse Handling, a link is highlighted by a click and opened by a double click
+	b.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action == tview.MouseLeftDoubleClick && b.InInnerRect(event.Position()) {
+			b.openHighlighted()
+			return action, nil
+		}
+		return action, event
+	})
 	// Input Handling
-	textView.SetDoneFunc(func(key tcell.Key) {
-		currentSelection := textView.GetHighlights()
-		if len(currentSelection) > 0 {
-			index, _ := strconv.Atoi(currentSelection[0])
-			if key == tcell.KeyEnter {
-				url := textView.GetRegionText(currentSelection[0])
-				utils.FetchHTMLContent(url, textView)
-				fmt.Println(url)
-			}
-			if key == tcell.KeyTab {
-				index = (index + 1) % tui.NumLinks
-			} else if key == tcell.KeyBacktab {
-				index = (index - 1 + tui.NumLinks) % tui.NumLinks
-			} else {
-				return
-			}
-			textView.Highlight(strconv.Itoa(index)).ScrollToHighlight()
+	b.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			b.openHighlighted()
+		case tcell.KeyTab:
+			b.cycleLinks(1)
+		case tcell.KeyBacktab:
+			b.cycleLinks(-1)
 		}
 	})
 
+	return name, b
+}
+
+// load displays the SOP with the given URL, scrolled to the heading of its anchor if it has one.
+// The SOP displayed is kept when the new one cannot be fetched.
+func (b *sopBrowser) load(URL string) bool {
+	document, anchor, _ := strings.Cut(URL, "#")
+
+	if document != b.document {
+		contents, err := utils.FetchMarkdown(document)
+
+		if err != nil {
+			utils.ErrorLogger.Printf("Error while fetching readme contents. The error message was : %s", err)
+			return false
+		}
+
+		b.document = document
+		b.doc = markdown.Render(contents, b.tui.Theme.markdownStyle())
+		b.SetText(b.doc.Text)
+		b.SetTitle(fmt.Sprintf(" %s ", utils.SOPName(document)))
+	}
+
+	b.location = URL
+	b.saveState()
+
+	if anchor == "" {
+		b.Highlight("0").ScrollToBeginning()
+		return true
+	}
+
+	region, ok := b.doc.Anchors[strings.ToLower(anchor)]
+
+	if !ok {
+		utils.InfoLogger.Printf("No heading found for the anchor #%s", anchor)
+		b.Highlight("0").ScrollToBeginning()
+		return true
+	}
+
+	b.Highlight(region).ScrollToHighlight()
+	return true
+}
+
+// saveState keeps the URL displayed in the state of the pane, so that it is reopened when the session is restored
+func (b *sopBrowser) saveState() {
+	if b.tui.Mux == nil {
+		return
+	}
+
+	if slide := b.tui.Mux.SlideOf(b); slide != nil {
+		slide.view.Find(b).state = PaneState{Type: PaneSOP, URL: b.location}
+	}
+}
+
+// follow opens the given link of the SOP displayed, relative to it.
+// The SOPs, i.e. the documents of the SOP sources, and the anchors are displayed in the browser,
+// the other links are opened with the desktop, e.g. in the web browser.
+func (b *sopBrowser) follow(link string) {
+	target := utils.ResolveLink(b.location, link)
+	document, _, _ := strings.Cut(target, "#")
+
+	if document != b.document && !utils.IsSOP(document) {
+		if err := utils.OpenURL(target); err != nil {
+			utils.ErrorLogger.Print(err)
+			return
+		}
+
+		utils.InfoLogger.Printf("Opened %s", target)
+		return
+	}
+
+	current := b.location
+
+	if b.load(target) {
+		b.back = append(b.back, current)
+		b.forward = nil
+	}
+}
+
+// goBack displays the previous location of the history
+func (b *sopBrowser) goBack() {
+	if len(b.back) == 0 {
+		utils.InfoLogger.Print("No previous SOP in the history")
+		return
+	}
+
+	current := b.location
+
+	if b.load(b.back[len(b.back)-1]) {
+		b.back = b.back[:len(b.back)-1]
+		b.forward = append(b.forward, current)
+	}
+}
+
+// goForward displays the location the history went back from
+func (b *sopBrowser) goForward() {
+	if len(b.forward) == 0 {
+		utils.InfoLogger.Print("No next SOP in the history")
+		return
+	}
+
+	current := b.location
+
+	if b.load(b.forward[len(b.forward)-1]) {
+		b.forward = b.forward[:len(b.forward)-1]
+		b.back = append(b.back, current)
+	}
+}
+
+// openHighlighted follows the highlighted link, or sends the highlighted code block to a terminal
+func (b *sopBrowser) openHighlighted() {
+	if highlights := b.GetHighlights(); len(highlights) > 0 {
+		if index, err := strconv.Atoi(highlights[0]); err == nil && index < len(b.doc.Links) {
+			b.follow(b.doc.Links[index])
+		}
+
+		if index, ok := codeBlockIndex(highlights[0]); ok && index < len(b.doc.CodeBlocks) {
+			b.tui.sendCodeBlock(b.doc.CodeBlocks[index])
+		}
+	}
+}
+
+// cycleCodeBlocks highlights the code block after the highlighted one, the first one when no code block is highlighted
+func (b *sopBrowser) cycleCodeBlocks() {
+	count := len(b.doc.CodeBlocks)
+
+	if count == 0 {
+		utils.InfoLogger.Print("No code blocks in the SOP")
+		return
+	}
+
+	index := -1
+
+	if highlights := b.GetHighlights(); len(highlights) > 0 {
+		if highlighted, ok := codeBlockIndex(highlights[0]); ok {
+			index = highlighted
+		}
+	}
+
+	b.Highlight(fmt.Sprintf("c%d", (index+1)%count)).ScrollToHighlight()
+}
+
+// cycleLinks highlights the link after the highlighted one, or before it when the step is negative.
+// The first link is highlighted when none is, e.g. after jumping to a heading.
+func (b *sopBrowser) cycleLinks(step int) {
+	count := len(b.doc.Links)
+
+	if count == 0 {
+		return
+	}
+
+	index := -1
+	if step < 0 {
+		index = 0
+	}
+
+	if highlights := b.GetHighlights(); len(highlights) > 0 {
+		if highlighted, err := strconv.Atoi(highlights[0]); err == nil {
+			index = highlighted
+		}
+	}
+
+	index = ((index+step)%count + count) % count
+	b.Highlight(strconv.Itoa(index)).ScrollToHighlight()
+}
+
+// showLinks lists the links of the SOP resolved against it, the selected link is followed
+func (b *sopBrowser) showLinks() {
+	if len(b.doc.Links) == 0 {
+		utils.InfoLogger.Print("No links in the SOP")
+		return
+	}
+
+	var items []listItem
+
+	for _, link := range b.doc.Links {
+		link := link
+		items = append(items, listItem{
+			text:     tview.Escape(utils.ResolveLink(b.location, link)),
+			selected: func() { b.follow(link) },
+		})
+	}
+
+	b.tui.showListModal(LinkListPageTitle, "Links", 100, items, 0)
+}
+
+// withFocusedSOP runs the given function with the SOP browser of the focused pane, if a SOP is focused
+func (tui *TUI) withFocusedSOP(run func(b *sopBrowser)) {
+	if slide := tui.Mux.Active(); slide != nil {
+		if b, ok := slide.view.FocusedPane().primitive.(*sopBrowser); ok {
+			run(b)
+		}
+	}
+}
+
+// resolveSOP finds the SOPs of the selected alert when it doesn't mention one, with the SOP rules and the names of the synchronized SOPs.
+// The SOP of a single rule matching the alert is opened, the candidates are listed otherwise.
+func (tui *TUI) resolveSOP() {
+	if tui.SelectedAlert == nil {
+		utils.InfoLogger.Print("No SOP mentioned for the alert")
+		return
+	}
+
+	rules, err := sop.ReadRules()
+
+	if err != nil {
+		utils.ErrorLogger.Print(err)
+	}
+
+	alert := sop.Alert{Name: tui.SelectedAlert.Name, Service: tui.SelectedAlert.Service, Labels: tui.SelectedAlert.Labels}
+	candidates := sop.Resolve(sop.DefaultOwner, sop.DefaultRepo, alert, rules, SOPCandidateLimit)
+
+	if len(candidates) == 0 {
+		if !sop.IsCached(sop.DefaultOwner, sop.DefaultRepo) {
+			utils.InfoLogger.Print("No SOP mentioned for the alert, run 'kite sop sync' to find the SOPs named after it")
+			return
+		}
+
+		utils.InfoLogger.Print("No SOP mentioned for the alert, and none named after it")
+		return
+	}
+
+	if candidates[0].Rule && (len(candidates) == 1 || !candidates[1].Rule) {
+		utils.InfoLogger.Print("Opening the SOP of the rule matching the alert in a new tab")
+		ViewAlertSOP(tui, candidates[0].URL)
+		return
+	}
+
+	var items []listItem
+
+	for _, candidate := range candidates {
+		candidate := candidate
+		name, origin := candidate.Path, fmt.Sprintf("match %d%%", candidate.Score)
+
+		if candidate.Path == "" {
+			name = candidate.URL
+		}
+
+		if candidate.Rule {
+			origin = "rule"
+		}
+
+		items = append(items, listItem{
+			text:     fmt.Sprintf("%s (%s)", tview.Escape(name), origin),
+			selected: func() { ViewAlertSOP(tui, candidate.URL) },
+		})
+	}
+
+	utils.InfoLogger.Printf("No SOP mentioned for the alert, %d candidates found", len(candidates))
+	tui.showListModal(SOPCandidatePageTitle, "SOP Candidates", 100, items, 0)
+}
+
+// promptSOPSearch prompts for the words searched in the synchronized SOPs
+func (tui *TUI) promptSOPSearch() {
+	tui.prompt(TerminalFooterSOPState, tui.searchSOPs)
+}
+
+// searchSOPs lists the synchronized SOPs containing all the given words, the selected SOP is opened in a new slide
+func (tui *TUI) searchSOPs(query string) {
+	matches, err := sop.Search(sop.DefaultOwner, sop.DefaultRepo, query, SOPSearchLimit)
+
+	if err != nil {
+		utils.ErrorLogger.Printf("Cannot search the SOPs: %v", err)
+		return
+	}
+
+	if len(matches) == 0 {
+		utils.InfoLogger.Printf("No SOPs found for '%s'", query)
+		return
+	}
+
+	var data [][]string
+
+	for _, match := range matches {
+		data = append(data, []string{match.Path, tview.Escape(match.Text)})
+	}
+
+	table := tui.InitTable([]string{"SOP", "MATCH"}, data, true, true, SOPSearchTableTitle)
+
+	table.SetSelectedFunc(func(row int, column int) {
+		if row > 0 && row <= len(matches) {
+			ViewAlertSOP(tui, sop.URL(sop.DefaultOwner, sop.DefaultRepo, matches[row-1].Path))
+		}
+	})
+
+	if page, _ := tui.Pages.GetFrontPage(); page != SOPSearchPageTitle {
+		tui.sopSearchReturnPage = page
+	}
+
+	utils.InfoLogger.Printf("%d SOPs found for '%s'", len(matches), query)
+	tui.Pages.AddAndSwitchToPage(SOPSearchPageTitle, table, true)
+}
+
+// closeSOPSearch goes back to the page displayed before the SOP search
+func (tui *TUI) closeSOPSearch() {
+	tui.Pages.SwitchToPage(tui.sopSearchReturnPage)
+	tui.Pages.RemovePage(SOPSearchPageTitle)
 }
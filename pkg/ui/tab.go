@@ -3,211 +3,95 @@ package ui
 import (
 	"fmt"
 	"os/exec"
-	"strconv"
+	"strings"
 
 	"github.com/rivo/tview"
 )
 
-// Declares the tab struct
-type TerminalTab struct {
-	index     int
-	regionID  int
-	title     string
-	primitive tview.Primitive
-}
-
-var CurrentActivePage int = 0
-var TotalPageCount int = 0
-var CursorPos int
-
-// Creates and return a new kite tab
-func InitKiteTab(tui *TUI, layout *tview.Flex) *TerminalTab {
-	tui.TerminalUIRegionIDs = append(tui.TerminalUIRegionIDs, TotalPageCount)
-	return &TerminalTab{
-		index:     0,
-		regionID:  0,
-		title:     "kite",
-		primitive: layout,
-	}
-}
+// Title of the slide displaying the kite layout
+const KiteSlideTitle = "kite"
 
-// Creates and returns a SOP tab
-func InitSOPTab(name string, layout *tview.TextView, tui *TUI) *TerminalTab {
-	TotalPageCount += 1
-	tui.TerminalUIRegionIDs = append(tui.TerminalUIRegionIDs, TotalPageCount)
-	index := len(tui.TerminalTabs)
-	return &TerminalTab{
-		index:     index,
-		regionID:  TotalPageCount,
-		title:     name,
-		primitive: layout,
-	}
-}
-
-// Creates and return a new tab
-func NewTab(name string, command string, args []string, tui *TUI) *TerminalTab {
-	TotalPageCount += 1
-	tui.TerminalUIRegionIDs = append(tui.TerminalUIRegionIDs, TotalPageCount)
-	index := len(tui.TerminalTabs)
-	return &TerminalTab{
-		index:     index,
-		regionID:  TotalPageCount,
-		title:     name,
-		primitive: newTabPrimitive(command, args, index, tui),
-	}
-}
+// Prefixes of the keys identifying the slides which are only opened once
+const (
+	clusterSlideKey = "cluster:"
+	sopSlideKey     = "sop:"
+)
 
-// Returns the primitive for a new tab
-func newTabPrimitive(command string, args []string, index int, tui *TUI) (content tview.Primitive) {
-	cmd := exec.Command(command, args...)
-	term := NewTerminal(cmd, index, tui)
+// Returns the terminal running the given command
+func newTerminalView(cmd *exec.Cmd, tui *TUI) *Terminal {
+	term := NewTerminal(cmd, tui)
 	term.SetBorder(true)
 	term.SetTitle(fmt.Sprintf(" Welcome to %s ", cmd))
 	return term
 }
 
-// Move to the previous slide
-func PreviousSlide(tui *TUI) {
-	CurrentActivePage = (CurrentActivePage - 1 + len(tui.TerminalTabs)) % len(tui.TerminalTabs)
-	tui.TerminalPageBar.Highlight(strconv.Itoa(tui.TerminalTabs[CurrentActivePage].regionID)).
-		ScrollToHighlight()
-	tui.TerminalInputBuffer = []rune{}
-}
-
-// Move to the next slide
-func NextSlide(tui *TUI) {
-	CurrentActivePage = (CurrentActivePage + 1 + len(tui.TerminalTabs)) % len(tui.TerminalTabs)
-	tui.TerminalPageBar.Highlight(strconv.Itoa(tui.TerminalTabs[CurrentActivePage].regionID)).
-		ScrollToHighlight()
-	tui.TerminalInputBuffer = []rune{}
-}
+// Adds a slide to the end of currently present slides
+// A cluster is only logged into once, its slide is switched to if it is already open
+func AddNewSlide(tui *TUI, name string, command string, args []string, isCluster bool) {
+	var key string
 
-func indexOf(ele int, tabs []TerminalTab) int {
-	for index, item := range tabs {
-		if item.regionID == ele {
-			return index
-		}
+	if isCluster && len(args) > 0 {
+		key = clusterSlideKey + args[0]
 	}
-	return -1
+
+	tui.addTerminalSlide(key, name, exec.Command(command, args...), nil)
 }
 
-// Remove the slide with the given index
-// Exit the app if only one slide is present
-func RemoveSlide(s int, tui *TUI) {
-	if len(tui.TerminalTabs) == 1 {
-		tui.App.Stop()
+// Adds a slide running the given command, the given alert context variables are saved with the sessions
+// A slide with a key is only opened once, it is switched to if it is already open
+func (tui *TUI) addTerminalSlide(key string, name string, cmd *exec.Cmd, env []string) {
+	if slide := tui.Mux.SlideByKey(key); slide != nil {
+		tui.Mux.Switch(slide)
 		return
 	}
-	index := indexOf(s, tui.TerminalTabs)
-	tui.TerminalTabs = append(tui.TerminalTabs[:index], tui.TerminalTabs[index+1:]...)
-	tui.TerminalUIRegionIDs = append(tui.TerminalUIRegionIDs[:index], tui.TerminalUIRegionIDs[index+1:]...)
-	tui.TerminalPageBar.Clear()
-	for index, tabSlide := range tui.TerminalTabs {
-		tabSlide.index = index
-		fmt.Fprintf(tui.TerminalPageBar, `["%d"]%s[white][""]  `, tabSlide.regionID, fmt.Sprintf("%d %s", tabSlide.index+1, tabSlide.title))
-	}
-	tui.TerminalPages.RemovePage(strconv.Itoa(s))
-	PreviousSlide(tui)
-	tui.TerminalInputBuffer = []rune{}
-}
 
-// Remove the slide with the given index
-// Exit the app if only one slide is present
-func ExitSlide(index int, tui *TUI) {
-	regionId := tui.TerminalTabs[index].regionID
-	tui.TerminalTabs = append(tui.TerminalTabs[:index], tui.TerminalTabs[index+1:]...)
-	tui.TerminalUIRegionIDs = append(tui.TerminalUIRegionIDs[:index], tui.TerminalUIRegionIDs[index+1:]...)
-	tui.TerminalPageBar.Clear()
-	for index, tabSlide := range tui.TerminalTabs {
-		tabSlide.index = index
-		fmt.Fprintf(tui.TerminalPageBar, `["%d"]%s[white][""]  `, tabSlide.regionID, fmt.Sprintf("%d %s", tabSlide.index+1, tabSlide.title))
-	}
-	tui.TerminalPages.RemovePage(strconv.Itoa(regionId))
-	PreviousSlide(tui)
-	tui.TerminalInputBuffer = []rune{}
-}
+	term := newTerminalView(cmd, tui)
+	term.env = env
 
-// Adds a slide to the end of currently present slides
-func AddNewSlide(tui *TUI, name string, command string, args []string, isCluster bool) {
-	if len(tui.TerminalTabs) < 9 {
-		if isCluster {
-			for i, tab := range tui.TerminalTabs {
-				if tab.primitive != nil && tab.title == args[0] {
-					tui.TerminalPageBar.Highlight(strconv.Itoa(i)).
-						ScrollToHighlight()
-					return
-				}
-			}
-		}
-		tabSlide := NewTab(name, command, args, tui)
-		tui.TerminalTabs = append(tui.TerminalTabs, *tabSlide)
-		tui.TerminalPages.AddPage(strconv.Itoa(tabSlide.regionID), tabSlide.primitive, true, true)
-		fmt.Fprintf(tui.TerminalPageBar, `["%d"]%s[white][""]  `, tabSlide.regionID, fmt.Sprintf("%d %s", tabSlide.index+1, tabSlide.title))
-		CurrentActivePage = tabSlide.index
-		tui.TerminalPageBar.Highlight(strconv.Itoa(tabSlide.regionID)).
-			ScrollToHighlight()
+	// The recordings of a cluster slide are named after the cluster and the incident it was logged into for
+	if strings.HasPrefix(key, clusterSlideKey) {
+		term.incidentID = tui.IncidentID
+		term.cluster = name
 	}
-	tui.TerminalInputBuffer = []rune{}
+
+	tui.Mux.Add(key, name, NewSplitView(term, nil))
 }
 
 // Adds a SOP slide to the end of currently present slides
-func AddSOPSlide(name string, textView *tview.TextView, tui *TUI) {
-	if len(tui.TerminalTabs) < 9 {
-		for i, tab := range tui.TerminalTabs {
-			if tab.primitive != nil && tab.title == name {
-				CurrentActivePage = tab.index
-				tui.TerminalPageBar.Highlight(strconv.Itoa(i)).
-					ScrollToHighlight()
-				return
-			}
-		}
-		tabSlide := InitSOPTab(name, textView, tui)
-		tui.TerminalTabs = append(tui.TerminalTabs, *tabSlide)
-		tui.TerminalPages.AddPage(strconv.Itoa(tabSlide.regionID), tabSlide.primitive, true, true)
-		fmt.Fprintf(tui.TerminalPageBar, `["%d"]%s[white][""]  `, tabSlide.regionID, fmt.Sprintf("%d %s", tabSlide.index+1, tabSlide.title))
-		CurrentActivePage = tabSlide.index
-		tui.TerminalPageBar.Highlight(strconv.Itoa(tabSlide.regionID)).
-			ScrollToHighlight()
+// A SOP is only opened once, its slide is switched to if it is already open
+func AddSOPSlide(name string, textView tview.Primitive, tui *TUI) {
+	key := sopSlideKey + name
+
+	if slide := tui.Mux.SlideByKey(key); slide != nil {
+		tui.Mux.Switch(slide)
+		return
 	}
-	tui.TerminalInputBuffer = []rune{}
+
+	tui.Mux.Add(key, name, NewSplitView(textView, tui.sopCommands()))
 }
 
-// Navigate to the specified slide
-func SwitchToSlide(slideNum int, tui *TUI) {
-	if slideNum > 0 && slideNum <= len(tui.TerminalTabs) {
-		slideNum = slideNum - 1
-		regionID := tui.TerminalTabs[slideNum].regionID
-		tui.TerminalPageBar.Highlight(strconv.Itoa(regionID)).
-			ScrollToHighlight()
-		CurrentActivePage = slideNum
-	}
+// Reports whether the kite layout is in the focused pane of the active slide
+func (tui *TUI) isKitePaneFocused() bool {
+	slide := tui.Mux.Active()
+	return slide != nil && slide.view.FocusedPane().primitive == tui.Layout
 }
 
 // Init the Layout for Terminal Multiplexer
-func InitTerminalMux(tui *TUI, kiteTab *TerminalTab) *tview.Flex {
-	// Initial Slides
-	tui.TerminalTabs = append(tui.TerminalTabs, *kiteTab)
+func InitTerminalMux(tui *TUI) *tview.Flex {
+	tui.Mux = NewMux().SetEmptyFunc(tui.App.Stop)
 
 	// Set the bottom navigation bar
-	tui.TerminalPageBar.
-		SetDynamicColors(true).
-		SetRegions(true).
-		SetWrap(false).
-		SetHighlightedFunc(func(added, removed, remaining []string) {
-			tui.TerminalPages.SwitchToPage(added[0])
-		})
-
-	for _, slide := range tui.TerminalTabs {
-		tui.TerminalPages.AddPage(strconv.Itoa(slide.index), slide.primitive, true, true)
-		fmt.Fprintf(tui.TerminalPageBar, `["%d"]%s[white][""]  `, slide.index, fmt.Sprintf("%d %s", slide.index+1, slide.title))
-	}
-	tui.TerminalPageBar.Highlight("0")
-	tui.TerminalFixedFooter.SetText(TerminalFooterText)
+	tui.Mux.PageBar.SetTextColor(tui.Theme.SlideBar)
+	tui.switchSlideOnClick()
+
+	// Initial Slides
+	tui.Mux.Add("", KiteSlideTitle, NewSplitView(tui.Layout, nil))
+	tui.TerminalFixedFooter.SetText(tui.terminalFooterText())
 
 	// Returns the main view & layout for the app
 	return tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(tui.TerminalPages, 0, 1, true).
-		AddItem(tui.TerminalPageBar, 1, 1, false).
+		AddItem(tui.Mux.Pages, 0, 1, true).
+		AddItem(tui.Mux.PageBar, 1, 1, false).
 		AddItem(tui.TerminalFixedFooter, 1, 1, false)
 }
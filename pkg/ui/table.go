@@ -2,8 +2,11 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
 	"github.com/rivo/tview"
 )
 
@@ -13,7 +16,7 @@ func (tui *TUI) InitTable(headers []string, data [][]string, isSelectable bool,
 	table := tview.NewTable().SetFixed(1, 1)
 
 	for k, v := range headers {
-		color := tcell.ColorYellow
+		color := tui.Theme.TableHeader
 
 		table.SetCell(
 			0,
@@ -33,7 +36,7 @@ func (tui *TUI) InitTable(headers []string, data [][]string, isSelectable bool,
 
 		for j, col := range row {
 
-			color := tcell.ColorWhite
+			color := tui.Theme.Text
 
 			tableCell := tview.NewTableCell(col).
 				SetTextColor(color).
@@ -56,14 +59,74 @@ func (tui *TUI) InitTable(headers []string, data [][]string, isSelectable bool,
 	table.
 		SetBorder(true).
 		SetBorderPadding(1, 1, 1, 1).
-		SetBorderColor(BorderColor).
+		SetBorderColor(tui.Theme.Border).
 		SetBorderAttributes(tcell.AttrDim)
 
 	table.SetTitle(fmt.Sprintf(TitleFmt, title))
 
 	if isSelectable {
 		table.SetSelectable(true, false)
+		tui.selectOnDoubleClick(table)
 	}
 
 	return table
 }
+
+// highlightAlertsTable color codes the rows of the alerts table according to the highlight rules.
+// The rows of the table are expected to follow the order of the given alerts.
+func (tui *TUI) highlightAlertsTable(headers []string, alerts []pdcli.Alert) {
+	rules := tui.Highlight
+	clusterAlerts := pdcli.ClusterAlertCounts(alerts)
+	now := time.Now()
+
+	for i, alert := range alerts {
+		row := i + 1
+		rowColor := tui.Theme.Text
+
+		if rules.Severity {
+			rowColor = tui.Theme.SeverityLow
+
+			if alert.IsHighSeverity() {
+				rowColor = tui.Theme.SeverityHigh
+			}
+		}
+
+		for col, header := range headers {
+			cell := tui.Table.GetCell(row, col)
+			cell.SetTextColor(rowColor)
+
+			switch header {
+			case "STATUS":
+				if !rules.Status {
+					continue
+				}
+
+				switch alert.IncidentState() {
+				case constants.StatusTriggered:
+					cell.SetTextColor(tui.Theme.StatusTriggered).SetAttributes(tcell.AttrBold)
+				case constants.StatusAcknowledged:
+					cell.SetTextColor(tui.Theme.StatusAcknowledged)
+				}
+
+			case "CLUSTER NAME", "CLUSTER ID":
+				// Mark the clusters with multiple active alerts with the number of alerts
+				if count := clusterAlerts[alert.ClusterID]; rules.Cluster && count > 1 {
+					cell.SetText(fmt.Sprintf("%s (%d)", cell.Text, count)).SetAttributes(tcell.AttrBold)
+				}
+
+			case "AGE":
+				if !rules.Age {
+					continue
+				}
+
+				// Older alerts are colored with the severity colors
+				switch bucket := alert.AgeBucket(now, rules.AgeBuckets); {
+				case bucket == len(rules.AgeBuckets):
+					cell.SetTextColor(tui.Theme.SeverityHigh)
+				case bucket > 0:
+					cell.SetTextColor(tui.Theme.SeverityLow)
+				}
+			}
+		}
+	}
+}
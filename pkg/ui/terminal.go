@@ -1,13 +1,18 @@
 package ui
 
 import (
+	"fmt"
 	"log"
+	"os"
 	"os/exec"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	tcellterm "git.sr.ht/~rockorager/tcell-term"
 	"github.com/gdamore/tcell/v2"
 	"github.com/gdamore/tcell/v2/views"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 	"github.com/rivo/tview"
 )
 
@@ -21,20 +26,52 @@ type Terminal struct {
 	w       int
 	h       int
 	sync.RWMutex
+
+	// Lines scrolled off the screen, and the screen as the copy mode starts
+	scrollback *Scrollback
+	capture    screenCapture
+
+	// Set while the application is asked to draw the terminal updates
+	redrawPending atomic.Bool
+
+	// Screen the terminal was last drawn on
+	screen tcell.Screen
+
+	// Set while the scrollback is browsed
+	copyMode *copyMode
+
+	// Set while the terminal is recorded
+	recorder *terminalRecorder
+
+	// Incident and cluster the terminal was opened for, the recordings are named after them
+	incidentID string
+	cluster    string
+
+	// Alert context variables added to the environment of the program
+	env []string
 }
 
-func NewTerminal(cmd *exec.Cmd, index int, tui *TUI) *Terminal {
+func NewTerminal(cmd *exec.Cmd, tui *TUI) *Terminal {
 	t := &Terminal{
-		Box:  tview.NewBox(),
-		term: tcellterm.New(),
-		cmd:  cmd,
-		tui:  tui,
+		Box:        tview.NewBox(),
+		term:       tcellterm.New(),
+		cmd:        cmd,
+		tui:        tui,
+		scrollback: NewScrollback(ScrollbackLines),
 	}
+	t.term.OnScroll = t.scrollback.Add
+	t.term.Output = terminalOutput{t}
 	return t
 }
 
 func (t *Terminal) Draw(s tcell.Screen) {
 	t.Box.DrawForSubclass(s, t)
+	t.redrawPending.Store(false)
+	t.screen = s
+
+	// The terminal is also drawn when the copy mode or a recording starts
+	t.Lock()
+	defer t.Unlock()
 
 	x, y, w, h := t.GetInnerRect()
 	view := views.NewViewPort(s, x, y, w, h)
@@ -43,6 +80,14 @@ func (t *Terminal) Draw(s tcell.Screen) {
 		t.w = w
 		t.h = h
 		t.term.Resize(w, h)
+
+		if t.recorder != nil {
+			t.recordResize()
+		}
+	}
+
+	if t.recorder != nil {
+		t.drawRecordingIndicator(s)
 	}
 
 	if !t.running {
@@ -55,6 +100,10 @@ func (t *Terminal) Draw(s tcell.Screen) {
 		t.term.Attach(t.HandleEvent)
 		t.running = true
 	}
+	if t.copyMode != nil {
+		t.copyMode.draw(s, x, y, w, h, t.tui.Theme, t.HasFocus())
+		return
+	}
 	if t.HasFocus() {
 		cy, cx, style, vis := t.term.Cursor()
 		if vis {
@@ -80,25 +129,197 @@ func (t *Terminal) HandleEvent(ev tcell.Event) {
 			})
 		}()
 	case *tcellterm.EventRedraw:
-		go func() {
-			t.tui.App.QueueUpdateDraw(func() {})
-		}()
+		// The application is asked once to draw all the updates made until the terminal is drawn
+		if t.redrawPending.CompareAndSwap(false, true) {
+			go func() {
+				t.tui.App.QueueUpdateDraw(func() {})
+			}()
+		}
+	}
+}
+
+// captureScreen draws the screen of the terminal on its capture.
+// The terminal must be locked.
+func (t *Terminal) captureScreen() {
+	t.capture.reset(t.w, t.h)
+	t.term.SetSurface(&t.capture)
+	t.term.Draw()
+}
+
+// enterCopyMode freezes the terminal on its scrollback and screen, the copy mode cursor starts at the terminal cursor.
+func (t *Terminal) enterCopyMode() {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.copyMode != nil || !t.running {
+		return
+	}
+
+	row, col, _, _ := t.term.Cursor()
+	t.captureScreen()
+
+	lines := append(t.scrollback.Lines(), t.capture.lines()...)
+	t.copyMode = newCopyMode(lines, len(lines)-t.h+row, col)
+
+	// The copy mode starts on the screen as it was
+	t.copyMode.top = len(lines) - t.h
+}
+
+// exitCopyMode displays the output of the program again.
+func (t *Terminal) exitCopyMode() {
+	t.copyMode = nil
+}
+
+// Scrollback returns the lines which scrolled off the screen of the terminal, the oldest first.
+func (t *Terminal) Scrollback() []string {
+	return t.scrollback.Lines()
+}
+
+// paste sends the given text to the program, the paste is bracketed if the program enabled it.
+func (t *Terminal) paste(text string) {
+	if !t.running {
+		return
+	}
+
+	t.term.HandleEvent(tcell.NewEventPaste(true))
+	t.sendText(text)
+	t.term.HandleEvent(tcell.NewEventPaste(false))
+}
+
+// PasteCode sends the given code to the program without running it, the user runs it with Enter.
+// The code is only sent when it fits on a line or when the program enabled the bracketed paste,
+// the programs without it would run the lines as they are received.
+func (t *Terminal) PasteCode(code string) error {
+	if !t.running {
+		return fmt.Errorf("the terminal has exited")
+	}
+
+	code = strings.TrimRight(code, "\n")
+
+	// The paste start is only sent if the program enabled the bracketed paste
+	bracketed := t.term.HandleEvent(tcell.NewEventPaste(true))
+
+	if !bracketed && strings.Contains(code, "\n") {
+		return fmt.Errorf("the program doesn't support the bracketed paste, the code would run line by line")
+	}
+
+	t.sendText(code)
+
+	if bracketed {
+		t.term.HandleEvent(tcell.NewEventPaste(false))
+	}
+
+	return nil
+}
+
+// sendText sends the given text to the program as if it was typed
+func (t *Terminal) sendText(text string) {
+	for _, r := range text {
+		if r == '\n' {
+			t.term.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+		} else {
+			t.term.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
 	}
 }
 
 func (t *Terminal) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
 	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		// The keys move the cursor of the copy mode rather than reaching the program
+		if t.copyMode != nil {
+			t.copyMode.handleKey(event, t.h)
+			return
+		}
 		t.term.HandleEvent(event)
+
+		for _, target := range t.broadcastTargets() {
+			target.term.HandleEvent(event)
+		}
 	})
 }
 
 func (t *Terminal) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
 	return t.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
-		return t.term.HandleEvent(event), nil
+		x, y := event.Position()
+
+		if !t.InInnerRect(x, y) {
+			return false, nil
+		}
+
+		switch action {
+		case tview.MouseLeftDown:
+			setFocus(t)
+		case tview.MouseLeftClick, tview.MouseLeftDoubleClick,
+			tview.MouseMiddleClick, tview.MouseMiddleDoubleClick,
+			tview.MouseRightClick, tview.MouseRightDoubleClick:
+			// The clicks are derived from the button events which have already been forwarded
+			return true, nil
+		}
+
+		if t.copyMode != nil {
+			switch action {
+			case tview.MouseScrollUp:
+				t.copyMode.moveTo(t.copyMode.row-1, t.copyMode.col)
+			case tview.MouseScrollDown:
+				t.copyMode.moveTo(t.copyMode.row+1, t.copyMode.col)
+			}
+			return true, nil
+		}
+
+		// The program running in the terminal expects coordinates relative to the terminal
+		rectX, rectY, _, _ := t.GetInnerRect()
+		t.term.HandleEvent(tcell.NewEventMouse(x-rectX, y-rectY, event.Buttons(), event.Modifiers()))
+
+		return true, nil
 	})
 }
 
 // Function for Closing a Terminal
+// The pane of the terminal is closed, or its slide if it is the last pane of the slide
+// Nothing is done if the slide has already been exited
 func (t *Terminal) Closed(ev *tcellterm.EventClosed) {
-	ExitSlide(CurrentActivePage, t.tui)
+	slide := t.tui.Mux.SlideOf(t)
+
+	if slide == nil {
+		return
+	}
+
+	hasFocus := t.HasFocus()
+
+	if !slide.view.Close(slide.view.Find(t)) {
+		t.tui.Mux.Remove(slide)
+	} else if hasFocus {
+		t.tui.App.SetFocus(slide.view)
+	}
+}
+
+// Close stops the program running in the terminal
+func (t *Terminal) Close() {
+	if t.IsRecording() {
+		if name, err := t.StopRecording(); err == nil {
+			utils.InfoLogger.Printf("Recording saved as '%s'", name)
+		}
+	}
+
+	t.term.Detach()
+	if t.running {
+		t.term.Close()
+	}
+}
+
+// workingDir returns the current directory of the program running in the terminal.
+// It is only known on systems with a proc filesystem, the directory the program was started in is returned otherwise.
+func (t *Terminal) workingDir() string {
+	if t.running && t.cmd.Process != nil {
+		if dir, err := os.Readlink(fmt.Sprintf("/proc/%d/cwd", t.cmd.Process.Pid)); err == nil {
+			return dir
+		}
+	}
+
+	if t.cmd.Dir != "" {
+		return t.cmd.Dir
+	}
+
+	dir, _ := os.Getwd()
+	return dir
 }
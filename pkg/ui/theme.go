@@ -0,0 +1,270 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/markdown"
+	"github.com/rivo/tview"
+)
+
+// Theme defines the colors used by the TUI components.
+type Theme struct {
+	Background                tcell.Color
+	Text                      tcell.Color
+	Border                    tcell.Color
+	Title                     tcell.Color
+	TableHeader               tcell.Color
+	Footer                    tcell.Color
+	InfoText                  tcell.Color
+	ErrorText                 tcell.Color
+	PromptText                tcell.Color
+	Selected                  tcell.Color
+	SlideBar                  tcell.Color
+	TerminalFooter            tcell.Color
+	TerminalFooterText        tcell.Color
+	TerminalFooterEscapeState tcell.Color
+	SeverityHigh              tcell.Color
+	SeverityLow               tcell.Color
+	StatusTriggered           tcell.Color
+	StatusAcknowledged        tcell.Color
+	SOPHeading                tcell.Color
+	SOPLink                   tcell.Color
+	SOPCode                   tcell.Color
+	SOPCodeBackground         tcell.Color
+	SOPQuote                  tcell.Color
+	SOPNote                   tcell.Color
+	SOPWarning                tcell.Color
+}
+
+// ThemeFile is the format of the user defined theme files stored in the themes config directory.
+// Base is the built-in theme which is extended, the colors are either names or hex codes.
+type ThemeFile struct {
+	Base   string            `json:"base,omitempty"`
+	Colors map[string]string `json:"colors"`
+}
+
+const (
+	DarkTheme         = "dark"
+	LightTheme        = "light"
+	HighContrastTheme = "high-contrast"
+
+	// Directory in the config directory containing the user defined themes
+	ThemesDir = "themes"
+)
+
+// Themes holds the built-in themes.
+var Themes = map[string]Theme{
+	DarkTheme: {
+		Background:                tcell.ColorBlack,
+		Text:                      tcell.ColorWhite,
+		Border:                    tcell.ColorLightGray,
+		Title:                     tcell.ColorLightCyan,
+		TableHeader:               tcell.ColorYellow,
+		Footer:                    tcell.ColorGray,
+		InfoText:                  tcell.ColorLightSlateGray,
+		ErrorText:                 tcell.ColorRed,
+		PromptText:                tcell.ColorLightGreen,
+		Selected:                  tcell.ColorLimeGreen,
+		SlideBar:                  tcell.ColorWhite,
+		TerminalFooter:            tcell.ColorGreen,
+		TerminalFooterText:        tcell.ColorWhite,
+		TerminalFooterEscapeState: tcell.ColorDarkGreen,
+		SeverityHigh:              tcell.ColorRed,
+		SeverityLow:               tcell.ColorYellow,
+		StatusTriggered:           tcell.ColorOrangeRed,
+		StatusAcknowledged:        tcell.ColorLightGreen,
+		SOPHeading:                tcell.ColorYellow,
+		SOPLink:                   tcell.ColorCornflowerBlue,
+		SOPCode:                   tcell.ColorWhite,
+		SOPCodeBackground:         tcell.ColorDarkSlateGray,
+		SOPQuote:                  tcell.ColorGray,
+		SOPNote:                   tcell.ColorDeepSkyBlue,
+		SOPWarning:                tcell.ColorOrange,
+	},
+	LightTheme: {
+		Background:                tcell.ColorWhite,
+		Text:                      tcell.ColorBlack,
+		Border:                    tcell.ColorGray,
+		Title:                     tcell.ColorNavy,
+		TableHeader:               tcell.ColorDarkBlue,
+		Footer:                    tcell.ColorDimGray,
+		InfoText:                  tcell.ColorDarkSlateGray,
+		ErrorText:                 tcell.ColorDarkRed,
+		PromptText:                tcell.ColorDarkGreen,
+		Selected:                  tcell.ColorGreen,
+		SlideBar:                  tcell.ColorBlack,
+		TerminalFooter:            tcell.ColorLightGreen,
+		TerminalFooterText:        tcell.ColorBlack,
+		TerminalFooterEscapeState: tcell.ColorLightSkyBlue,
+		SeverityHigh:              tcell.ColorDarkRed,
+		SeverityLow:               tcell.ColorDarkOrange,
+		StatusTriggered:           tcell.ColorRed,
+		StatusAcknowledged:        tcell.ColorDarkGreen,
+		SOPHeading:                tcell.ColorNavy,
+		SOPLink:                   tcell.ColorBlue,
+		SOPCode:                   tcell.ColorBlack,
+		SOPCodeBackground:         tcell.ColorLightGray,
+		SOPQuote:                  tcell.ColorDimGray,
+		SOPNote:                   tcell.ColorDarkCyan,
+		SOPWarning:                tcell.ColorDarkOrange,
+	},
+	HighContrastTheme: {
+		Background:                tcell.ColorBlack,
+		Text:                      tcell.ColorWhite,
+		Border:                    tcell.ColorWhite,
+		Title:                     tcell.ColorYellow,
+		TableHeader:               tcell.ColorYellow,
+		Footer:                    tcell.ColorWhite,
+		InfoText:                  tcell.ColorWhite,
+		ErrorText:                 tcell.ColorRed,
+		PromptText:                tcell.ColorAqua,
+		Selected:                  tcell.ColorLime,
+		SlideBar:                  tcell.ColorWhite,
+		TerminalFooter:            tcell.ColorYellow,
+		TerminalFooterText:        tcell.ColorBlack,
+		TerminalFooterEscapeState: tcell.ColorAqua,
+		SeverityHigh:              tcell.ColorRed,
+		SeverityLow:               tcell.ColorYellow,
+		StatusTriggered:           tcell.ColorFuchsia,
+		StatusAcknowledged:        tcell.ColorLime,
+		SOPHeading:                tcell.ColorYellow,
+		SOPLink:                   tcell.ColorAqua,
+		SOPCode:                   tcell.ColorWhite,
+		SOPCodeBackground:         tcell.ColorNavy,
+		SOPQuote:                  tcell.ColorWhite,
+		SOPNote:                   tcell.ColorAqua,
+		SOPWarning:                tcell.ColorOrange,
+	},
+}
+
+// colors maps the color names used in the theme files to the theme fields.
+func (t *Theme) colors() map[string]*tcell.Color {
+	return map[string]*tcell.Color{
+		"background":                   &t.Background,
+		"text":                         &t.Text,
+		"border":                       &t.Border,
+		"title":                        &t.Title,
+		"table_header":                 &t.TableHeader,
+		"footer":                       &t.Footer,
+		"info_text":                    &t.InfoText,
+		"error_text":                   &t.ErrorText,
+		"prompt_text":                  &t.PromptText,
+		"selected":                     &t.Selected,
+		"slide_bar":                    &t.SlideBar,
+		"terminal_footer":              &t.TerminalFooter,
+		"terminal_footer_text":         &t.TerminalFooterText,
+		"terminal_footer_escape_state": &t.TerminalFooterEscapeState,
+		"severity_high":                &t.SeverityHigh,
+		"severity_low":                 &t.SeverityLow,
+		"status_triggered":             &t.StatusTriggered,
+		"status_acknowledged":          &t.StatusAcknowledged,
+		"sop_heading":                  &t.SOPHeading,
+		"sop_link":                     &t.SOPLink,
+		"sop_code":                     &t.SOPCode,
+		"sop_code_background":          &t.SOPCodeBackground,
+		"sop_quote":                    &t.SOPQuote,
+		"sop_note":                     &t.SOPNote,
+		"sop_warning":                  &t.SOPWarning,
+	}
+}
+
+// LoadTheme returns the theme with the given name.
+// Built-in themes take precedence over the theme files in the themes config directory.
+func LoadTheme(name string) (*Theme, error) {
+	if name == "" {
+		name = DarkTheme
+	}
+
+	if theme, ok := Themes[name]; ok {
+		return &theme, nil
+	}
+
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid theme name '%s'", name)
+	}
+
+	configDir, err := config.Dir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, ThemesDir, name+".json"))
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read theme '%s': %v", name, err)
+	}
+
+	var themeFile ThemeFile
+
+	err = json.Unmarshal(data, &themeFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("error parsing theme '%s': %v", name, err)
+	}
+
+	if themeFile.Base == "" {
+		themeFile.Base = DarkTheme
+	}
+
+	base, ok := Themes[themeFile.Base]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown base theme '%s'", themeFile.Base)
+	}
+
+	theme := base
+	colors := theme.colors()
+
+	for key, value := range themeFile.Colors {
+		color, ok := colors[key]
+
+		if !ok {
+			return nil, fmt.Errorf("unknown color '%s' in theme '%s'", key, name)
+		}
+
+		*color = tcell.GetColor(value)
+
+		if *color == tcell.ColorDefault {
+			return nil, fmt.Errorf("invalid color '%s' for '%s' in theme '%s'", value, key, name)
+		}
+	}
+
+	return &theme, nil
+}
+
+// applyStyles sets the tview defaults from the theme.
+// It has to be called before any primitive is created.
+func (t *Theme) applyStyles() {
+	tview.Styles.PrimitiveBackgroundColor = t.Background
+	tview.Styles.ContrastBackgroundColor = t.Selected
+	tview.Styles.MoreContrastBackgroundColor = t.Selected
+	tview.Styles.BorderColor = t.Border
+	tview.Styles.TitleColor = t.Title
+	tview.Styles.GraphicsColor = t.Border
+	tview.Styles.PrimaryTextColor = t.Text
+	tview.Styles.SecondaryTextColor = t.TableHeader
+	tview.Styles.TertiaryTextColor = t.PromptText
+	tview.Styles.InverseTextColor = t.Background
+	tview.Styles.ContrastSecondaryTextColor = t.Text
+}
+
+// markdownStyle returns the style of the SOPs rendered with the theme.
+func (t *Theme) markdownStyle() markdown.Style {
+	return markdown.Style{
+		Text:           t.Text,
+		Heading:        t.SOPHeading,
+		Link:           t.SOPLink,
+		Code:           t.SOPCode,
+		CodeBackground: t.SOPCodeBackground,
+		Quote:          t.SOPQuote,
+		Note:           t.SOPNote,
+		Warning:        t.SOPWarning,
+	}
+}
@@ -2,12 +2,17 @@ package ui
 
 import (
 	"fmt"
-	"strings"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/PagerDuty/go-pagerduty"
 	"github.com/gdamore/tcell/v2"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/server"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
 	"github.com/rivo/tview"
 )
@@ -16,6 +21,7 @@ type TUI struct {
 
 	// Main UI elements
 	App                 *tview.Application
+	Root                *tview.Pages
 	AlertMetadata       *tview.TextView
 	Table               *tview.Table
 	IncidentsTable      *tview.Table
@@ -42,31 +48,66 @@ type TUI struct {
 	Username          string
 	Role              string
 	Columns           string
+	TriggeredLimit    uint
+	Highlight         pdcli.HighlightRules
 	ClusterID         string
 	ClusterName       string
+	IncidentID        string
+	SelectedAlert     *pdcli.Alert
+	LaunchTemplates   []config.LaunchTemplate
+	Launchers         []config.Launcher
 	CurrentOnCallPage int
+	Keymap            *Keymap
+	Theme             *Theme
+	Mouse             bool
+	OSC52             bool
+	isPrefixed        bool
+	isEscapeSequence  bool
+	promptText        string
+	promptInput       []rune
+	isSlidePrompt     bool
+	onPromptInput     func(input string)
+
+	// Page of the list modal displayed, if any
+	listModal string
 
 	// SOP Related
-	SOPLink  string
-	NumLinks int
-	SOPView  *tview.TextView
+	SOPLink string
+	SOPView *tview.TextView
+
+	// Page displayed before the SOP search results
+	sopSearchReturnPage string
 
 	// Multi-Window Terminals Related
 	TerminalLayout      *tview.Flex
-	TerminalPages       *tview.Pages
-	TerminalPageBar     *tview.TextView
 	TerminalFixedFooter *tview.TextView
-	TerminalTabs        []TerminalTab
-	TerminalUIRegionIDs []int
-	TerminalInputBuffer []rune
-	TerminalLastChars   []rune
+	Mux                 *Mux
+
+	// Text copied from the terminals, pasted back with the paste action
+	Clipboard string
+
+	// Session Related
+	SessionName     string
+	SessionArgs     []string
+	RestoredSession *Session
+
+	// Set once the session is saved on demand
+	sessionSaved bool
+
+	// Server sharing the TUI with the attached clients, nil when kite runs in the terminal
+	Server *server.Server
 }
 
 // InitAlertsUI initializes TUI table component.
 // It adds the returned table as a new TUI page view.
 func (tui *TUI) InitAlertsUI(alerts []pdcli.Alert, tableTitle string, pageTitle string) {
-	headers, data := pdcli.GetTableData(alerts, tui.Columns)
+	if tui.Highlight.AgeBuckets == nil {
+		tui.Highlight.AgeBuckets = pdcli.DefaultAgeBuckets
+	}
+
+	headers, data := pdcli.GetTableData(alerts, tui.Columns, tui.Highlight.AgeBuckets)
 	tui.Table = tui.InitTable(headers, data, true, false, tableTitle)
+	tui.highlightAlertsTable(headers, alerts)
 	tui.SetAlertsTableEvents(alerts)
 
 	if len(alerts) == 0 && tui.Username == tui.AssignedTo {
@@ -76,11 +117,6 @@ func (tui *TUI) InitAlertsUI(alerts []pdcli.Alert, tableTitle string, pageTitle
 	tui.Pages.AddPage(pageTitle, tui.Table, true, true)
 	tui.FrontPage = pageTitle
 
-	if pageTitle == TrigerredAlertsPageTitle {
-		tui.Footer.SetText(FooterTextTrigerredAlerts)
-	} else {
-		tui.Footer.SetText(FooterTextAlerts)
-	}
 }
 
 // InitIncidentsUI initializes TUI table component.
@@ -107,17 +143,17 @@ func (tui *TUI) InitAlertsSecondaryView() {
 			tui.Username,
 			tui.AssignedTo,
 			tui.Role)).
-		SetTextColor(InfoTextColor)
+		SetTextColor(tui.Theme.InfoText)
 }
 
 func (tui *TUI) InitAlertDataSecondaryView() {
 	var secondaryViewText string
 
-	PromptClusterLogin := fmt.Sprintf("Press 'Y' to log into the cluster: %s\n", tui.ClusterName)
-	PromptServiceLogs := "Press 'L' to view service logs"
+	PromptClusterLogin := fmt.Sprintf("Press '%s' to log into the cluster: %s\n", tui.Keymap.Key(ActionClusterLogin), tui.ClusterName)
+	PromptServiceLogs := fmt.Sprintf("Press '%s' to view service logs", tui.Keymap.Key(ActionServiceLogs))
 
 	secondaryViewText = PromptClusterLogin + PromptServiceLogs
-	tui.SecondaryWindow.SetText(secondaryViewText).SetTextColor(PromptTextColor)
+	tui.SecondaryWindow.SetText(secondaryViewText).SetTextColor(tui.Theme.PromptText)
 }
 
 func (tui *TUI) InitOnCallSecondaryView(user string, primary string, secondary string) {
@@ -129,25 +165,91 @@ func (tui *TUI) InitOnCallSecondaryView(user string, primary string, secondary s
 	)
 }
 
-// initFooter initializes the footer text depending on the page currently visible.
-func (t *TUI) initFooter() {
-	name, _ := t.Pages.GetFrontPage()
+// updateFooter sets the footer text from the commands registered for the page currently visible.
+func (tui *TUI) updateFooter() {
+	var pageActions, actions []Action
+
+	for _, cmd := range tui.pageCommands() {
+		if cmd.action == ActionBack || cmd.action == ActionHelp {
+			actions = append(actions, cmd.action)
+		} else {
+			pageActions = append(pageActions, cmd.action)
+		}
+	}
+
+	footer := tui.Keymap.Help(actions...)
+
+	if len(pageActions) > 0 {
+		footer = tui.Keymap.Help(pageActions...) + "\n" + footer
+	}
+
+	tui.Footer.SetText(footer)
+}
+
+func (tui *TUI) terminalFooterText() string {
+	var actions []Action
+
+	for _, cmd := range tui.muxCommands() {
+		actions = append(actions, cmd.action)
+	}
+
+	return tui.Keymap.Help(actions...)
+}
 
-	switch name {
-	case AlertsPageTitle:
-		t.Footer.SetText(FooterTextAlerts)
+// clusterPromptText returns the prompt displayed in the secondary view for the selected alert.
+func (tui *TUI) clusterPromptText(clusterName string) string {
+	return fmt.Sprintf("Press '%s' to log into the cluster: %s\nPress '%s' to view the SOP\nPress '%s' to view service logs",
+		tui.Keymap.Key(ActionClusterLogin),
+		clusterName,
+		tui.Keymap.Key(ActionViewSOP),
+		tui.Keymap.Key(ActionServiceLogs))
+}
 
-	default:
-		t.Footer.SetText(FooterText)
+// resetTerminalFooter restores the multiplexer footer after a slide command.
+func (tui *TUI) resetTerminalFooter() {
+	tui.TerminalFixedFooter.
+		SetText(tui.terminalFooterText()).
+		SetBackgroundColor(tui.Theme.TerminalFooter)
+}
+
+// initKeymap loads the user configured key bindings, falling back to the defaults on errors.
+func (tui *TUI) initKeymap(cfg *config.Config) {
+	var err error
+
+	if cfg != nil {
+		tui.Keymap, err = NewKeymap(cfg.Keymap)
+
+		if err != nil {
+			utils.ErrorLogger.Printf("Using the default keymap: %v", err)
+		}
 	}
 
-	if strings.Contains(name, OncallPageTitle) {
-		t.Footer.SetText(FooterTextOncall)
+	if tui.Keymap == nil {
+		tui.Keymap, _ = NewKeymap(nil)
 	}
 }
 
+// initTheme loads the user configured theme, falling back to the dark theme on errors.
+func (tui *TUI) initTheme(cfg *config.Config) (err error) {
+	if cfg != nil {
+		tui.Theme, err = LoadTheme(cfg.Theme)
+	}
+
+	if tui.Theme == nil {
+		tui.Theme, _ = LoadTheme(DarkTheme)
+	}
+
+	tui.Theme.applyStyles()
+
+	return err
+}
+
 // Init initializes all the TUI main elements.
 func (tui *TUI) Init() {
+	// User preferences, the credentials are validated by the commands
+	cfg, _ := config.Read()
+	themeErr := tui.initTheme(cfg)
+
 	tui.App = tview.NewApplication()
 	tui.Pages = tview.NewPages()
 	tui.SecondaryWindow = tview.NewTextView()
@@ -155,8 +257,6 @@ func (tui *TUI) Init() {
 	tui.Footer = tview.NewTextView()
 	tui.AlertMetadata = tview.NewTextView()
 	tui.ServiceLogView = tview.NewTextView()
-	tui.TerminalPages = tview.NewPages()
-	tui.TerminalPageBar = tview.NewTextView()
 	tui.TerminalFixedFooter = tview.NewTextView()
 
 	tui.SOPView = tview.NewTextView().
@@ -168,11 +268,11 @@ func (tui *TUI) Init() {
 
 	tui.SecondaryWindow.
 		SetChangedFunc(func() { tui.App.Draw() }).
-		SetTextColor(InfoTextColor).
+		SetTextColor(tui.Theme.InfoText).
 		SetScrollable(true).
 		ScrollToEnd().
 		SetBorder(true).
-		SetBorderColor(BorderColor).
+		SetBorderColor(tui.Theme.Border).
 		SetBorderAttributes(tcell.AttrDim).
 		SetBorderPadding(1, 1, 1, 1)
 
@@ -181,22 +281,24 @@ func (tui *TUI) Init() {
 		SetScrollable(true).
 		ScrollToEnd().
 		SetBorder(true).
-		SetBorderColor(BorderColor).
+		SetBorderColor(tui.Theme.Border).
 		SetBorderAttributes(tcell.AttrDim).
 		SetBorderPadding(0, 0, 1, 1)
 
 	tui.Footer.
 		SetTextAlign(tview.AlignLeft).
-		SetTextColor(FooterTextColor).
+		SetTextColor(tui.Theme.Footer).
 		SetBorderPadding(1, 0, 1, 1)
 
 	tui.TerminalFixedFooter.
-		Clear().SetBackgroundColor(TerminalFooterTextColor)
+		Clear().
+		SetTextColor(tui.Theme.TerminalFooterText).
+		SetBackgroundColor(tui.Theme.TerminalFooter)
 
 	tui.AlertMetadata.
 		SetScrollable(true).
 		SetBorder(true).
-		SetBorderColor(BorderColor).
+		SetBorderColor(tui.Theme.Border).
 		SetBorderPadding(1, 1, 1, 1).
 		SetBorderAttributes(tcell.AttrDim).
 		SetTitle(fmt.Sprintf(TitleFmt, AlertMetadataViewTitle))
@@ -204,7 +306,7 @@ func (tui *TUI) Init() {
 	tui.ServiceLogView.
 		SetScrollable(true).
 		SetBorder(true).
-		SetBorderColor(BorderColor).
+		SetBorderColor(tui.Theme.Border).
 		SetBorderPadding(1, 1, 1, 1).
 		SetBorderAttributes(tcell.AttrDim).
 		SetTitle(fmt.Sprintf(TitleFmt, ServiceLogsPageTitle))
@@ -212,6 +314,34 @@ func (tui *TUI) Init() {
 	// Initialize logger to output to log view
 	utils.InitLogger(tui.LogWindow)
 
+	if themeErr != nil {
+		utils.ErrorLogger.Printf("Using the default theme: %v", themeErr)
+	}
+
+	// Load the key bindings
+	tui.initKeymap(cfg)
+	tui.initMouse(cfg)
+	tui.initClipboard(cfg)
+	tui.initLaunchers(cfg)
+
+	if cfg != nil {
+		tui.LaunchTemplates = cfg.LaunchTemplates
+	}
+
+	// Without a GitHub token, the SOPs of the private repositories are only read from their local copy
+	if (cfg == nil || cfg.AccessToken == "") && !sop.IsCached(sop.DefaultOwner, sop.DefaultRepo) {
+		utils.InfoLogger.Print("No GitHub token configured, the private SOPs are unavailable: run 'kite login' to add one or 'kite sop sync' to read them offline")
+	}
+
+	// Only the interactive views take the focus when clicked
+	ignoreFocusOnClick(tui.SecondaryWindow)
+	ignoreFocusOnClick(tui.LogWindow)
+	ignoreFocusOnClick(tui.Footer)
+	ignoreFocusOnClick(tui.TerminalFixedFooter)
+
+	// Keep the footer in sync with the visible page
+	tui.Pages.SetChangedFunc(tui.updateFooter)
+
 	// Create the main layout
 	tui.Layout = tview.NewFlex().
 		SetDirection(tview.FlexRow).
@@ -223,14 +353,64 @@ func (tui *TUI) Init() {
 			0, 2, false).
 		AddItem(tui.Footer, 0, 1, false)
 
-	kiteTab := InitKiteTab(tui, tui.Layout)
-	tui.TerminalLayout = InitTerminalMux(tui, kiteTab)
+	tui.TerminalLayout = InitTerminalMux(tui)
+
+	// Modals are displayed on top of the multiplexer
+	tui.Root = tview.NewPages().
+		AddPage(MainPageTitle, tui.TerminalLayout, true, true)
+
+	tui.initKeyboard()
 }
 
 // StartApp sets the UI layout and renders all the TUI elements.
 func (t *TUI) StartApp() error {
-	t.initFooter()
-	t.initKeyboard()
+	t.updateFooter()
+
+	if t.RestoredSession != nil {
+		t.RestoreSession(t.RestoredSession)
+	}
+
+	if t.Server != nil {
+		// The TUI is drawn to the terminals of the attached clients
+		t.App.SetScreen(server.Placeholder())
+		defer t.Server.Close()
+
+		go func() {
+			if err := t.Server.Serve(t.App); err != nil {
+				utils.ErrorLogger.Print(err)
+			}
+		}()
+	}
+
+	// kite is stopped gracefully when it is terminated, a server outlives the terminal it was started from
+	signals := []os.Signal{os.Interrupt, syscall.SIGTERM}
+	if t.Server == nil {
+		signals = append(signals, syscall.SIGHUP)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, signals...)
+
+	defer func() {
+		signal.Stop(stop)
+		close(stop)
+	}()
+
+	go func() {
+		if _, ok := <-stop; ok {
+			t.App.Stop()
+		}
+	}()
+
+	err := t.App.SetRoot(t.Root, true).EnableMouse(t.Mouse).Run()
+
+	// The session restored or saved on demand is saved however kite exits, e.g. when the last slide exits.
+	// The other runs leave the saved sessions as they are.
+	if t.RestoredSession != nil || t.sessionSaved {
+		if saveErr := t.SaveCurrentSession(); err == nil {
+			err = saveErr
+		}
+	}
 
-	return t.App.SetRoot(t.TerminalLayout, true).EnableMouse(false).Run()
+	return err
 }
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// OpenURL opens the given URL with the default application of the desktop, e.g. the web browser.
+func OpenURL(URL string) error {
+	command := "xdg-open"
+
+	if runtime.GOOS == "darwin" {
+		command = "open"
+	}
+
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Errorf("cannot open %s: %s is not found", URL, command)
+	}
+
+	// The application isn't waited for, it may run as long as the desktop session
+	cmd := exec.Command(command, URL)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cannot open %s: %v", URL, err)
+	}
+
+	go cmd.Wait()
+
+	return nil
+}
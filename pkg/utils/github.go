@@ -2,38 +2,165 @@ package utils
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/google/go-github/v50/github"
-	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
 	"golang.org/x/oauth2"
 )
 
-func GetGHReadme(owner, repo, path string) (string, error) {
-	cfg, err := config.Load()
+// GitHubAPIURL is the URL of the github.com API, the tests replace it by a fake server.
+var GitHubAPIURL = "https://api.github.com/"
+
+// gitHubSource fetches the SOPs browsed on GitHub with the GitHub API, e.g. https://github.com/openshift/ops-sop/blob/master/README.md
+type gitHubSource struct {
+	host  string
+	token string
+}
+
+// gitHubDocument is a file of a GitHub repository
+type gitHubDocument struct {
+	owner, repo, ref, path string
+}
+
+// parseGitHubURL returns the file of the GitHub URL, e.g. https://github.com/owner/repo/blob/branch/path.
+// The branches with a slash aren't supported, their name is taken as the first part of the path.
+func parseGitHubURL(u *url.URL) (gitHubDocument, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 5)
+
+	if len(parts) != 5 || (parts[2] != "blob" && parts[2] != "tree") || parts[0] == "" || parts[1] == "" || parts[3] == "" || parts[4] == "" {
+		return gitHubDocument{}, false
+	}
+
+	return gitHubDocument{owner: parts[0], repo: parts[1], ref: parts[3], path: parts[4]}, true
+}
+
+func (s *gitHubSource) Match(u *url.URL) bool {
+	_, ok := parseGitHubURL(u)
+	return ok && matchHost(u, s.host) && isDocument(u.Path)
+}
+
+// Fetch reads the SOPs of github.com from the repository synchronized with kite sop sync when it exists,
+// and the SOP is on the branch it was synchronized from. The other branches, the tags and the commits are fetched with the API.
+func (s *gitHubSource) Fetch(u *url.URL) (string, error) {
+	document, _ := parseGitHubURL(u)
+
+	if branch, ok := sop.Branch(document.owner, document.repo); ok && s.host == "github.com" && branch == document.ref {
+		if content, err := sop.Read(document.owner, document.repo, document.path); err == nil {
+			return toMarkdown(content, document.path, "")
+		}
+	}
+
+	client, err := s.client(u)
+
 	if err != nil {
 		return "", err
 	}
-	// Use Backgound Context
-	ctx := context.Background()
 
-	// Generate Token Source and Token Client
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: cfg.AccessToken},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+	ctx := context.Background()
+	options := github.RepositoryContentGetOptions{Ref: document.ref}
 
-	// Create GitHub Client
-	client := github.NewClient(tc)
-	options := github.RepositoryContentGetOptions{}
+	content, _, _, err := client.Repositories.GetContents(ctx, document.owner, document.repo, document.path, &options)
 
-	// Get Contents Accordingly
-	content, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &options)
 	if err != nil {
-		return "", err
+		return "", s.explain(err, document)
 	}
+
+	if content == nil {
+		return "", fmt.Errorf("%s is a directory", u.Redacted())
+	}
+
 	decodedContent, err := content.GetContent()
+
 	if err != nil {
 		return "", err
 	}
-	return decodedContent, nil
+
+	return toMarkdown(decodedContent, document.path, "")
+}
+
+func (s *gitHubSource) Root(u *url.URL) string {
+	document, _ := parseGitHubURL(u)
+	return fmt.Sprintf("/%s/%s/blob/%s", document.owner, document.repo, document.ref)
+}
+
+// client returns the client of the GitHub API, the API of GitHub Enterprise is served by the host under /api/v3
+func (s *gitHubSource) client(u *url.URL) (*github.Client, error) {
+	var tc *http.Client
+
+	if s.token != "" {
+		tc = oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: s.token}))
+	}
+
+	if s.host == "github.com" {
+		return newGitHubClient(tc)
+	}
+
+	baseURL := fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Host)
+	uploadURL := fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Host)
+
+	return github.NewEnterpriseClient(baseURL, uploadURL, tc)
+}
+
+// explain turns the errors of the GitHub API into the steps fixing them, the GitHub token being optional
+func (s *gitHubSource) explain(err error, document gitHubDocument) error {
+	var response *github.ErrorResponse
+
+	if !errors.As(err, &response) || response.Response == nil {
+		return fmt.Errorf("cannot reach %s: %v", s.host, err)
+	}
+
+	repository := document.owner + "/" + document.repo
+
+	switch status := response.Response.StatusCode; {
+	case status == http.StatusUnauthorized:
+		return fmt.Errorf("the GitHub token of %s is invalid or expired, run 'kite login --access-token <token>'", s.host)
+	case status == http.StatusNotFound && s.token == "":
+		return fmt.Errorf("%s isn't public and no GitHub token is configured for %s, run 'kite login' to add one or 'kite sop sync' to read the SOPs offline", repository, s.host)
+	case status == http.StatusNotFound && document.path == "":
+		return fmt.Errorf("the GitHub token has no access to %s", repository)
+	case status == http.StatusNotFound:
+		return fmt.Errorf("%s doesn't exist in %s, or the GitHub token has no access to it", document.path, repository)
+	case status == http.StatusForbidden && s.token == "":
+		return fmt.Errorf("the GitHub API rate limit is reached without token, run 'kite login' to add one or 'kite sop sync' to read the SOPs offline")
+	}
+
+	return err
+}
+
+// newGitHubClient returns the client of the github.com API
+func newGitHubClient(tc *http.Client) (*github.Client, error) {
+	client := github.NewClient(tc)
+	baseURL, err := url.Parse(GitHubAPIURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client.BaseURL = baseURL
+
+	return client, nil
+}
+
+// ValidateGitHubToken checks that the GitHub token grants access to the SOP repository, e.g. on login.
+func ValidateGitHubToken(token string) error {
+	tc := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	client, err := newGitHubClient(tc)
+
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.Repositories.Get(context.Background(), sop.DefaultOwner, sop.DefaultRepo)
+
+	if err != nil {
+		source := &gitHubSource{host: "github.com", token: token}
+		return source.explain(err, gitHubDocument{owner: sop.DefaultOwner, repo: sop.DefaultRepo})
+	}
+
+	return nil
 }
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitLabSource fetches the SOPs browsed on GitLab with the GitLab API, e.g. https://gitlab.com/group/project/-/blob/main/README.md
+type gitLabSource struct {
+	host  string
+	token string
+}
+
+// gitLabDocument is a file of a GitLab project
+type gitLabDocument struct {
+	project, ref, path string
+}
+
+// parseGitLabURL returns the file of the GitLab URL, e.g. https://gitlab.com/group/subgroup/project/-/blob/branch/path.
+// The branches with a slash aren't supported, their name is taken as the first part of the path.
+func parseGitLabURL(u *url.URL) (gitLabDocument, bool) {
+	project, file, found := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/-/")
+
+	if !found || project == "" {
+		return gitLabDocument{}, false
+	}
+
+	parts := strings.SplitN(file, "/", 3)
+
+	if len(parts) != 3 || (parts[0] != "blob" && parts[0] != "raw") || parts[1] == "" || parts[2] == "" {
+		return gitLabDocument{}, false
+	}
+
+	return gitLabDocument{project: project, ref: parts[1], path: parts[2]}, true
+}
+
+func (s *gitLabSource) Match(u *url.URL) bool {
+	_, ok := parseGitLabURL(u)
+	return ok && matchHost(u, s.host) && isDocument(u.Path)
+}
+
+func (s *gitLabSource) Fetch(u *url.URL) (string, error) {
+	document, _ := parseGitLabURL(u)
+
+	// The project and the file path are encoded as a single path segment each
+	endpoint := fmt.Sprintf("%s://%s/api/v4/projects/%s/repository/files/%s/raw?ref=%s",
+		u.Scheme, u.Host, url.PathEscape(document.project), url.PathEscape(document.path), url.QueryEscape(document.ref))
+
+	request, err := http.NewRequest(http.MethodGet, endpoint, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	if s.token != "" {
+		request.Header.Set("PRIVATE-TOKEN", s.token)
+	}
+
+	content, _, err := httpGet(request)
+
+	if err != nil {
+		return "", err
+	}
+
+	return toMarkdown(content, document.path, "")
+}
+
+func (s *gitLabSource) Root(u *url.URL) string {
+	document, _ := parseGitLabURL(u)
+	return fmt.Sprintf("/%s/-/blob/%s", document.project, document.ref)
+}
@@ -1,55 +1,43 @@
 package utils
 
 import (
-	"bytes"
-	"io"
+	"net/url"
+	"path"
 	"strings"
-
-	"github.com/gomarkdown/markdown"
-	"github.com/gomarkdown/markdown/html"
-	"github.com/gomarkdown/markdown/parser"
 )
 
-func getGitHubMdURL(URL string) (owner, repo, path string) {
-	end := URL[len(URL)-3:]
-	host := URL[8:18]
-	if host == "github.com" && end == ".md" {
-		URL = strings.Replace(URL, "tree", "blob", -1)
-		owner, repo := GetOwnerAndRepoName(URL)
-		path := GetReadmePath(URL)
-		return owner, repo, path
+// IsGitHubMarkdown reports whether the URL is a markdown file browsed on GitHub, e.g. https://github.com/openshift/ops-sop/blob/master/README.md
+func IsGitHubMarkdown(URL string) bool {
+	u, err := url.Parse(URL)
+
+	if err != nil || u.Host != "github.com" {
+		return false
 	}
-	return URL, "", ""
-}
 
-func GetOwnerAndRepoName(str string) (owner, repo string) {
-	startS, endS := ".com/", "/blob"
-	s := strings.Index(str, startS)
-	newS := str[s+len(startS):]
-	e := strings.Index(newS, endS)
-	result := newS[:e]
-	combined := strings.SplitN(result, "/", -1)
-	return combined[0], combined[1]
+	_, ok := parseGitHubURL(u)
+	return ok && documentFormats[strings.ToLower(path.Ext(u.Path))] == "markdown"
 }
 
-func GetReadmePath(str string) string {
-	e := strings.Index(str, "/blob/")
-	result := str[e+len("/blob/"):]
-	combined := strings.SplitN(result, "/", 2)
-	return combined[1]
-}
+// ResolveLink returns the absolute URL of the link found in the document with the given URL.
+// The links of the GitHub and GitLab documents starting with a slash are relative to the root of the repository, as on GitHub.
+func ResolveLink(base string, link string) string {
+	baseURL, err := url.Parse(base)
 
-func ConvertMarkdownToHTML(body string) io.Reader {
-	md := []byte(body)
-	extensions := parser.CommonExtensions
-	p := parser.NewWithExtensions(extensions)
-	doc := p.Parse(md)
+	if err != nil {
+		return link
+	}
 
-	// create HTML renderer with extensions
-	htmlFlags := html.CommonFlags | html.HrefTargetBlank
-	opts := html.RendererOptions{Flags: htmlFlags}
-	renderer := html.NewRenderer(opts)
+	linkURL, err := url.Parse(link)
+
+	if err != nil {
+		return link
+	}
+
+	if linkURL.Scheme == "" && linkURL.Host == "" && strings.HasPrefix(linkURL.Path, "/") {
+		if source, _, err := FindSOPSource(base); err == nil {
+			linkURL.Path = source.Root(baseURL) + linkURL.Path
+		}
+	}
 
-	data := markdown.Render(doc, renderer)
-	return bytes.NewReader(data)
+	return baseURL.ResolveReference(linkURL).String()
 }
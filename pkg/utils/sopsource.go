@@ -0,0 +1,264 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/markdown"
+)
+
+// Types of the SOP hosts in the configuration
+const (
+	GitHubSOPHost = "github"
+	GitLabSOPHost = "gitlab"
+	HTTPSOPHost   = "http"
+)
+
+// Formats of the SOP documents, by extension
+var documentFormats = map[string]string{
+	".md":       "markdown",
+	".markdown": "markdown",
+	".html":     "html",
+	".htm":      "html",
+	".adoc":     "text",
+	".asciidoc": "text",
+	".txt":      "text",
+}
+
+// SOPSource fetches the SOPs from a kind of server, e.g. GitHub.
+type SOPSource interface {
+	// Match reports whether the document with the given URL is fetched from the source
+	Match(u *url.URL) bool
+
+	// Fetch returns the document with the given URL, converted to markdown
+	Fetch(u *url.URL) (string, error)
+
+	// Root returns the path the links starting with a slash are relative to, e.g. the root of the repository, empty for the host
+	Root(u *url.URL) string
+}
+
+// SOPSources returns the sources of the SOPs, the hosts of the configuration come first.
+// github.com, gitlab.com, the local files and the documents served over HTTP are always supported.
+func SOPSources() ([]SOPSource, error) {
+	var sources []SOPSource
+	var gitHubToken string
+
+	if cfg, err := config.Read(); err == nil {
+		gitHubToken = cfg.AccessToken
+
+		for _, host := range cfg.SOPHosts {
+			switch host.Type {
+			case GitHubSOPHost:
+				sources = append(sources, &gitHubSource{host: host.Host, token: host.Token})
+			case GitLabSOPHost:
+				sources = append(sources, &gitLabSource{host: host.Host, token: host.Token})
+			case HTTPSOPHost:
+				sources = append(sources, &httpSource{host: host.Host, token: host.Token})
+			default:
+				return nil, fmt.Errorf("unknown type '%s' of the SOP host '%s', expected %s, %s or %s",
+					host.Type, host.Host, GitHubSOPHost, GitLabSOPHost, HTTPSOPHost)
+			}
+		}
+	}
+
+	return append(sources,
+		&gitHubSource{host: "github.com", token: gitHubToken},
+		&gitLabSource{host: "gitlab.com"},
+		&fileSource{},
+		&httpSource{},
+	), nil
+}
+
+// FindSOPSource returns the source of the document with the given URL, and the parsed URL.
+// The local files are given by their absolute path or a file:// URL.
+func FindSOPSource(URL string) (SOPSource, *url.URL, error) {
+	u, err := url.Parse(URL)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid SOP URL '%s': %v", URL, err)
+	}
+
+	sources, err := SOPSources()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, source := range sources {
+		if source.Match(u) {
+			return source, u, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("no SOP source for '%s', the supported documents are the markdown, HTML, AsciiDoc and text files", URL)
+}
+
+// FetchMarkdown returns the markdown of the SOP with the given URL, the HTML and text documents are converted.
+// The SOPs of github.com synchronized with kite sop sync are read offline.
+func FetchMarkdown(URL string) (string, error) {
+	source, u, err := FindSOPSource(URL)
+
+	if err != nil {
+		return "", err
+	}
+
+	return source.Fetch(u)
+}
+
+// IsSOP reports whether the URL is a document displayed by kite, the other links are opened with the desktop.
+func IsSOP(URL string) bool {
+	_, _, err := FindSOPSource(URL)
+	return err == nil
+}
+
+// SOPName returns the file name of the SOP with the given URL, e.g. "KubeAPIDown.md"
+func SOPName(URL string) string {
+	if u, err := url.Parse(URL); err == nil && u.Path != "" {
+		return path.Base(u.Path)
+	}
+
+	return path.Base(URL)
+}
+
+// documentFormat returns the format of the document, from its name or its content type
+func documentFormat(name string, contentType string) (string, bool) {
+	if format, ok := documentFormats[strings.ToLower(path.Ext(name))]; ok {
+		return format, true
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		return "html", true
+	case "text/markdown", "text/x-markdown":
+		return "markdown", true
+	case "text/plain", "text/asciidoc":
+		return "text", true
+	}
+
+	return "", false
+}
+
+// toMarkdown converts the document to markdown
+func toMarkdown(content string, name string, contentType string) (string, error) {
+	format, ok := documentFormat(name, contentType)
+
+	if !ok {
+		return "", fmt.Errorf("unsupported SOP format '%s'", name)
+	}
+
+	switch format {
+	case "html":
+		return markdown.FromHTML(content)
+	case "text":
+		return markdown.FromText(content), nil
+	}
+
+	return content, nil
+}
+
+// matchHost reports whether the URL is on the given host, over HTTP or HTTPS
+func matchHost(u *url.URL, host string) bool {
+	return (u.Scheme == "https" || u.Scheme == "http") && strings.EqualFold(u.Host, host)
+}
+
+// isDocument reports whether the path is a document kite displays, from its extension
+func isDocument(name string) bool {
+	_, ok := documentFormats[strings.ToLower(path.Ext(name))]
+	return ok
+}
+
+// httpGet returns the body of the response to the request, and its content type.
+// The error responses are reported with their status.
+func httpGet(request *http.Request) (string, string, error) {
+	response, err := http.DefaultClient.Do(request)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("cannot fetch %s: %s", request.URL.Redacted(), response.Status)
+	}
+
+	body, err := io.ReadAll(response.Body)
+
+	if err != nil {
+		return "", "", fmt.Errorf("cannot fetch %s: %v", request.URL.Redacted(), err)
+	}
+
+	return string(body), response.Header.Get("Content-Type"), nil
+}
+
+// fileSource reads the SOPs from the local files
+type fileSource struct{}
+
+func (s *fileSource) Match(u *url.URL) bool {
+	return (u.Scheme == "file" || (u.Scheme == "" && u.Host == "" && filepath.IsAbs(u.Path))) && isDocument(u.Path)
+}
+
+func (s *fileSource) Fetch(u *url.URL) (string, error) {
+	content, err := os.ReadFile(filepath.FromSlash(u.Path))
+
+	if err != nil {
+		return "", err
+	}
+
+	return toMarkdown(string(content), u.Path, "")
+}
+
+func (s *fileSource) Root(u *url.URL) string {
+	return ""
+}
+
+// httpSource fetches the HTML and markdown pages as they are served.
+// Without host, it fetches the documents of any host with a known extension, without a token.
+type httpSource struct {
+	host  string
+	token string
+}
+
+func (s *httpSource) Match(u *url.URL) bool {
+	if s.host == "" {
+		return (u.Scheme == "https" || u.Scheme == "http") && isDocument(u.Path)
+	}
+
+	return matchHost(u, s.host)
+}
+
+func (s *httpSource) Fetch(u *url.URL) (string, error) {
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Accept", "text/markdown, text/html;q=0.9, text/plain;q=0.8")
+
+	if s.token != "" {
+		request.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	content, contentType, err := httpGet(request)
+
+	if err != nil {
+		return "", err
+	}
+
+	return toMarkdown(content, u.Path, contentType)
+}
+
+func (s *httpSource) Root(u *url.URL) string {
+	return ""
+}
@@ -2,7 +2,10 @@ package utils
 
 import "time"
 
-// formatTimestamp formats a given timestamp into a UTC format time and returns the string.
+// TimeZone is the time zone of the formatted timestamps, UTC by default
+var TimeZone = time.UTC
+
+// formatTimestamp formats a given timestamp into a time of the configured time zone and returns the string.
 func FormatTimestamp(timestamp string) (string, error) {
 	t, err := time.Parse("2006-01-02T15:04:05Z", timestamp)
 
@@ -10,5 +13,5 @@ func FormatTimestamp(timestamp string) (string, error) {
 		return "", err
 	}
 
-	return t.Format("01-02-2006 15:04 UTC"), nil
+	return t.In(TimeZone).Format("01-02-2006 15:04 MST"), nil
 }
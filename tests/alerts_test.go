@@ -17,6 +17,8 @@ limitations under the License.
 package tests
 
 import (
+	"time"
+
 	pdApi "github.com/PagerDuty/go-pagerduty"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
@@ -183,6 +185,8 @@ var _ = Describe("view alerts", func() {
 
 			mockClient.EXPECT().ListIncidentAlerts(gomock.Any()).Return(alertResponse, nil).Times(1)
 
+			mockClient.EXPECT().ListIncidentNotes(gomock.Any()).Return(nil, nil).Times(1)
+
 			result, err := pdcli.GetIncidentAlerts(mockClient, incident)
 
 			Expect(err).ShouldNot(HaveOccurred())
@@ -331,4 +335,64 @@ var _ = Describe("view alerts", func() {
 
 		})
 	})
+
+	When("the alerts table highlight rules are parsed", func() {
+		It("enables the given rules with the given age buckets", func() {
+			rules, err := pdcli.ParseHighlightRules("severity,cluster", "30m,2h")
+
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(rules).To(Equal(pdcli.HighlightRules{
+				Severity:   true,
+				Cluster:    true,
+				AgeBuckets: []time.Duration{30 * time.Minute, 2 * time.Hour},
+			}))
+		})
+
+		It("throws an error for an unknown rule", func() {
+			_, err := pdcli.ParseHighlightRules("severity,color", "")
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("throws an error for age buckets not in ascending order", func() {
+			_, err := pdcli.ParseHighlightRules("age", "4h,1h")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the alerts are color coded", func() {
+		It("counts the alerts of each cluster", func() {
+			alerts := []pdcli.Alert{
+				{ClusterID: "cluster-1"},
+				{ClusterID: "cluster-2"},
+				{ClusterID: "cluster-1"},
+				{ClusterID: "N/A"},
+			}
+
+			Expect(pdcli.ClusterAlertCounts(alerts)).To(Equal(map[string]int{
+				"cluster-1": 2,
+				"cluster-2": 1,
+			}))
+		})
+
+		It("places the alerts in age buckets", func() {
+			now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+			buckets := pdcli.DefaultAgeBuckets
+
+			recent := pdcli.Alert{CreatedAt: "2024-01-02T11:30:00Z"}
+			old := pdcli.Alert{CreatedAt: "2024-01-01T06:00:00Z"}
+			unknown := pdcli.Alert{}
+
+			Expect(recent.AgeBucket(now, buckets)).To(Equal(0))
+			Expect(pdcli.AgeBucketLabel(recent.AgeBucket(now, buckets), buckets)).To(Equal("< 1h"))
+
+			Expect(old.AgeBucket(now, buckets)).To(Equal(3))
+			Expect(pdcli.AgeBucketLabel(old.AgeBucket(now, buckets), buckets)).To(Equal("> 1d"))
+
+			Expect(unknown.AgeBucket(now, buckets)).To(Equal(-1))
+			Expect(pdcli.AgeBucketLabel(2, buckets)).To(Equal("4h - 1d"))
+		})
+	})
 })
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"os/exec"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+var _ = Describe("broadcast input", func() {
+	var (
+		app    *tview.Application
+		tui    *ui.TUI
+		terms  []*ui.Terminal
+		slides []*ui.Slide
+	)
+
+	BeforeEach(func() {
+		app = tview.NewApplication().SetScreen(tcell.NewSimulationScreen("UTF-8"))
+		tui = &ui.TUI{App: app, Mux: ui.NewMux()}
+		root := tview.NewFlex()
+		terms, slides = nil, nil
+
+		for _, title := range []string{"first", "second", "third"} {
+			term := ui.NewTerminal(exec.Command("sh", "-c", "read line; echo got-$line; seq 1 20; sleep 10"), tui)
+			terms = append(terms, term)
+			slides = append(slides, tui.Mux.Add("", title, ui.NewSplitView(term, nil)))
+			root.AddItem(term, 0, 1, false)
+		}
+
+		root.SetRect(0, 0, 78, 10)
+		app.SetRoot(root, false)
+
+		go app.Run()
+	})
+
+	AfterEach(func() {
+		app.Stop()
+		for _, term := range terms {
+			term.Close()
+		}
+	})
+
+	When("slides broadcast their input", func() {
+		BeforeEach(func() {
+			tui.Mux.SetBroadcast(slides[0], true)
+			tui.Mux.SetBroadcast(slides[1], true)
+		})
+
+		It("marks them in the page bar", func() {
+			Expect(tui.Mux.BroadcastSlides()).To(Equal(slides[:2]))
+			Expect(tui.Mux.PageBar.GetText(true)).To(ContainSubstring("⇄ 1 first"))
+			Expect(tui.Mux.PageBar.GetText(true)).To(ContainSubstring("⇄ 2 second"))
+			Expect(tui.Mux.PageBar.GetText(true)).ToNot(ContainSubstring("⇄ 3 third"))
+		})
+
+		It("sends the keys typed in one of them to the others", func() {
+			// The programs are started when the terminals are first drawn
+			drawn := make(chan struct{})
+			app.QueueUpdateDraw(func() {})
+			app.QueueUpdate(func() { close(drawn) })
+			Eventually(drawn, "5s").Should(BeClosed())
+
+			for _, key := range []*tcell.EventKey{
+				tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone),
+				tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone),
+			} {
+				terms[0].InputHandler()(key, nil)
+			}
+
+			Eventually(terms[0].Scrollback, "5s").Should(ContainElement("got-x"))
+			Eventually(terms[1].Scrollback, "5s").Should(ContainElement("got-x"))
+			Consistently(terms[2].Scrollback, "500ms").ShouldNot(ContainElement("got-x"))
+		})
+	})
+})
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+var _ = Describe("credentials", func() {
+	var (
+		tmpDir    string
+		server    *httptest.Server
+		gitHubAPI string
+		requests  int
+	)
+
+	const sopURL = "https://github.com/openshift/ops-sop/blob/master/v4/alerts/KubeAPIDown.md"
+
+	// login writes the configuration of kite login
+	login := func(apiKey string, token string) {
+		data, err := json.Marshal(config.Config{ApiKey: apiKey, AccessToken: token})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0600)).To(Succeed())
+	}
+
+	useTempConfig(&tmpDir, "config.json")
+
+	BeforeEach(func() {
+		// The fake GitHub API only grants access to ops-sop with the "good" token
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+
+			switch r.Header.Get("Authorization") {
+			case "Bearer good":
+			case "":
+				http.NotFound(w, r)
+				return
+			default:
+				http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+				return
+			}
+
+			switch r.URL.Path {
+			case "/repos/openshift/ops-sop":
+				fmt.Fprint(w, `{"full_name": "openshift/ops-sop"}`)
+			case "/repos/openshift/ops-sop/contents/v4/alerts/KubeAPIDown.md":
+				fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": "%s"}`, base64.StdEncoding.EncodeToString([]byte("# KubeAPIDown\n")))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+
+		gitHubAPI = utils.GitHubAPIURL
+		utils.GitHubAPIURL = server.URL + "/"
+	})
+
+	AfterEach(func() {
+		utils.GitHubAPIURL = gitHubAPI
+		server.Close()
+	})
+
+	It("loads the configuration without GitHub", func() {
+		login(constants.SampleKey, "bad")
+
+		cfg, err := config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.AccessToken).To(Equal("bad"))
+
+		login(constants.SampleKey, "")
+		_, err = config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(BeZero())
+	})
+
+	It("rejects the invalid PagerDuty API keys", func() {
+		login("invalid", "good")
+
+		_, err := config.Load()
+		Expect(err).To(MatchError("invalid API key"))
+	})
+
+	It("validates the GitHub tokens", func() {
+		Expect(utils.ValidateGitHubToken("good")).To(Succeed())
+		Expect(utils.ValidateGitHubToken("bad")).To(MatchError(ContainSubstring("invalid or expired")))
+	})
+
+	It("validates the GitHub token when the SOPs are fetched", func() {
+		login(constants.SampleKey, "good")
+		Expect(utils.FetchMarkdown(sopURL)).To(Equal("# KubeAPIDown\n"))
+
+		login(constants.SampleKey, "bad")
+		_, err := utils.FetchMarkdown(sopURL)
+		Expect(err).To(MatchError(ContainSubstring("kite login --access-token")))
+	})
+
+	It("explains how to read the private SOPs without GitHub token", func() {
+		login(constants.SampleKey, "")
+
+		_, err := utils.FetchMarkdown(sopURL)
+		Expect(err).To(MatchError(ContainSubstring("no GitHub token is configured")))
+		Expect(err).To(MatchError(ContainSubstring("kite sop sync")))
+	})
+})
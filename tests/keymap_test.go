@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+var _ = Describe("keymap", func() {
+
+	When("a key binding is parsed", func() {
+		It("matches the corresponding key events", func() {
+			ctrlN, err := ui.ParseKeyBinding("Ctrl+N")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ctrlN.Matches(tcell.NewEventKey(tcell.KeyCtrlN, 0, tcell.ModCtrl))).To(BeTrue())
+
+			letter, err := ui.ParseKeyBinding("r")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(letter.Matches(tcell.NewEventKey(tcell.KeyRune, 'R', tcell.ModNone))).To(BeTrue())
+			Expect(letter.String()).To(Equal("R"))
+
+			_, err = ui.ParseKeyBinding("Ctrl+Shift+Nope")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("a key binding with the Alt modifier is parsed", func() {
+		It("only matches the key events with the Alt modifier", func() {
+			altLeft, err := ui.ParseKeyBinding("Alt+Left")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(altLeft.Matches(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModAlt))).To(BeTrue())
+			Expect(altLeft.Matches(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone))).To(BeFalse())
+			Expect(altLeft.String()).To(Equal("Alt+Left"))
+
+			altPlus, err := ui.ParseKeyBinding("alt++")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(altPlus.Matches(tcell.NewEventKey(tcell.KeyRune, '+', tcell.ModAlt))).To(BeTrue())
+
+			left, err := ui.ParseKeyBinding("Left")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(left.Matches(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModAlt))).To(BeFalse())
+		})
+	})
+
+	When("no keymap is configured", func() {
+		It("uses the default bindings", func() {
+			km, err := ui.NewKeymap(nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(km.Prefix).To(BeNil())
+			Expect(km.Matches(ui.ActionNextSlide, tcell.NewEventKey(tcell.KeyCtrlN, 0, tcell.ModCtrl))).To(BeTrue())
+			Expect(km.Help(ui.ActionNextSlide, ui.ActionQuit)).To(Equal("[Ctrl+N] Next Slide | [Ctrl+Q] Quit"))
+		})
+	})
+
+	When("a prefix key and custom bindings are configured", func() {
+		It("overrides the default bindings", func() {
+			km, err := ui.NewKeymap(&config.KeymapConfig{
+				Prefix:   "Ctrl+B",
+				Bindings: map[string]string{"exit_slide": "&", "refresh_alerts": "F5"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(km.Prefix).ToNot(BeNil())
+			Expect(km.Matches(ui.ActionNextSlide, tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone))).To(BeTrue())
+			Expect(km.Key(ui.ActionExitSlide)).To(Equal("Ctrl+B &"))
+			Expect(km.Key(ui.ActionRefreshAlerts)).To(Equal("F5"))
+		})
+	})
+
+	When("the prefix key is already bound to an action", func() {
+		It("throws an error", func() {
+			_, err := ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+A"})
+			Expect(err).To(MatchError(ContainSubstring("'acknowledge_incidents'")))
+
+			_, err = ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+B", Bindings: map[string]string{"refresh_alerts": "Ctrl+B"}})
+			Expect(err).To(MatchError(ContainSubstring("'refresh_alerts'")))
+		})
+	})
+
+	When("two actions of the same mode are bound to the same key", func() {
+		It("throws an error", func() {
+			// The multiplexer bindings are active on every page without a prefix key
+			_, err := ui.NewKeymap(&config.KeymapConfig{Bindings: map[string]string{"next_slide": "R"}})
+			Expect(err).To(MatchError(ContainSubstring("'refresh_alerts'")))
+
+			_, err = ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+B", Bindings: map[string]string{"exit_slide": "x"}})
+			Expect(err).To(MatchError(ContainSubstring("'close_pane'")))
+
+			_, err = ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+B", Bindings: map[string]string{"slide_list": "3"}})
+			Expect(err).To(MatchError(ContainSubstring("switches to a slide")))
+
+			_, err = ui.NewKeymap(&config.KeymapConfig{Bindings: map[string]string{"view_sop": "L"}})
+			Expect(err).To(MatchError(ContainSubstring("'service_logs'")))
+		})
+
+		It("accepts the keys of other modes and swapped keys", func() {
+			km, err := ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+B", Bindings: map[string]string{"next_slide": "R"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(km.Key(ui.ActionNextSlide)).To(Equal("Ctrl+B R"))
+
+			km, err = ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+B", Bindings: map[string]string{"next_slide": "P", "previous_slide": "N"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(km.Help(ui.ActionTriggeredIncidents)).To(Equal("[2] Triggered Incidents"))
+		})
+	})
+
+	When("an unknown action is configured", func() {
+		It("throws an error", func() {
+			_, err := ui.NewKeymap(&config.KeymapConfig{Bindings: map[string]string{"launch_rockets": "L"}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("a launcher is bound to a key", func() {
+		It("is described and prefixed like the multiplexer bindings", func() {
+			km, err := ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+B"})
+			Expect(err).ToNot(HaveOccurred())
+
+			action := ui.LaunchAction("k9s")
+			Expect(km.Bind(action, "Alt+9", "Launch k9s")).To(Succeed())
+
+			Expect(km.Matches(action, tcell.NewEventKey(tcell.KeyRune, '9', tcell.ModAlt))).To(BeTrue())
+			Expect(km.Key(action)).To(Equal("Ctrl+B Alt+9"))
+			Expect(km.Help(action)).To(Equal("[Ctrl+B Alt+9] Launch k9s"))
+
+			Expect(km.Bind(action, "Ctrl+Nope", "Launch k9s")).ToNot(Succeed())
+		})
+
+		It("rejects the keys of the other actions", func() {
+			km, err := ui.NewKeymap(nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(km.Bind(ui.LaunchAction("k9s"), "Alt+D", "Launch k9s")).To(MatchError(ContainSubstring("'detach'")))
+			Expect(km.Bind(ui.LaunchAction("k9s"), "Alt+9", "Launch k9s")).To(Succeed())
+			Expect(km.Bind(ui.LaunchAction("htop"), "Alt+9", "Launch htop")).To(MatchError(ContainSubstring("'launch:k9s'")))
+
+			// After the prefix key, only the multiplexer keys are taken
+			km, err = ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+B"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(km.Bind(ui.LaunchAction("k9s"), "N", "Launch k9s")).To(MatchError(ContainSubstring("'next_slide'")))
+			Expect(km.Bind(ui.LaunchAction("k9s"), "Ctrl+B", "Launch k9s")).To(MatchError(ContainSubstring("prefix key")))
+			Expect(km.Bind(ui.LaunchAction("k9s"), "R", "Launch k9s")).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	pdcli "github.com/openshift/pagerduty-short-circuiter/pkg/pdcli/alerts"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+var _ = Describe("slide launch", func() {
+	var tui *ui.TUI
+
+	alert := pdcli.Alert{
+		IncidentID:  "Q1ABCDEF",
+		AlertID:     "P2ALERT",
+		Name:        "ClusterOperatorDown",
+		ClusterID:   "123",
+		ClusterName: "my-cluster",
+		Console:     "N/A",
+		Sop:         "https://github.com/openshift/ops-sop/blob/master/v4/alerts/ClusterOperatorDown.md",
+		Labels:      "<nil>",
+	}
+
+	BeforeEach(func() {
+		tui = &ui.TUI{}
+	})
+
+	When("an alert is selected", func() {
+		BeforeEach(func() {
+			tui.SelectedAlert = &alert
+		})
+
+		It("passes the known fields of the alert in the environment", func() {
+			Expect(ui.AlertEnv(&alert)).To(Equal([]string{
+				"INCIDENT_ID=Q1ABCDEF",
+				"ALERT_ID=P2ALERT",
+				"ALERT_NAME=ClusterOperatorDown",
+				"CLUSTER_ID=123",
+				"CLUSTER_NAME=my-cluster",
+				"SOP_URL=https://github.com/openshift/ops-sop/blob/master/v4/alerts/ClusterOperatorDown.md",
+			}))
+
+			cmd, env, err := tui.LaunchCommand(ui.ShellSlide, "sh", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env).To(Equal(ui.AlertEnv(&alert)))
+			Expect(cmd.Env).To(ContainElement("CLUSTER_ID=123"))
+		})
+
+		It("runs the launch template matching the alert", func() {
+			tui.LaunchTemplates = []config.LaunchTemplate{
+				{Slide: ui.ClusterSlide, Alert: "^KubeAPIDown$", Args: []string{"--other"}},
+				{
+					Slide:   ui.ClusterSlide,
+					Alert:   "Operator",
+					Command: "sh",
+					Args:    []string{"-c", "echo {{.ClusterID}}"},
+					Env:     map[string]string{"OPERATOR_ALERT": "{{.Name}}"},
+				},
+			}
+
+			cmd, env, err := tui.LaunchCommand(ui.ClusterSlide, "ocm-container", []string{"123"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cmd.Args[1:]).To(Equal([]string{"-c", "echo 123"}))
+			Expect(env).To(ContainElement("OPERATOR_ALERT=ClusterOperatorDown"))
+		})
+
+		It("substitutes the alert fields in the SOP code", func() {
+			code := "ocm describe cluster <cluster-id>\nocm backplane login ${CLUSTER_ID} # $CLUSTER_NAME\noc get <namespace>"
+
+			Expect(ui.SubstitutePlaceholders(code, &alert)).
+				To(Equal("ocm describe cluster 123\nocm backplane login 123 # my-cluster\noc get <namespace>"))
+			Expect(ui.SubstitutePlaceholders("echo $CLUSTER_IDS <CLUSTER_ID>", &alert)).To(Equal("echo $CLUSTER_IDS 123"))
+		})
+
+		It("reports the invalid launch templates", func() {
+			tui.LaunchTemplates = []config.LaunchTemplate{{Slide: ui.ShellSlide, Args: []string{"{{.Unknown}}"}}}
+
+			_, _, err := tui.LaunchCommand(ui.ShellSlide, "sh", nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the program of a slide is not installed", func() {
+		It("reports it", func() {
+			tui.LaunchTemplates = []config.LaunchTemplate{{Slide: ui.ShellSlide, Command: "kite-missing-command"}}
+
+			_, _, err := tui.LaunchCommand(ui.ShellSlide, "sh", nil)
+			Expect(err).To(MatchError(ContainSubstring("'kite-missing-command' is not found")))
+		})
+	})
+
+	When("no alert is selected", func() {
+		It("runs the command without any context", func() {
+			cmd, env, err := tui.LaunchCommand(ui.ShellSlide, "sh", []string{"-l"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env).To(BeEmpty())
+			Expect(cmd.Env).To(BeNil())
+			Expect(cmd.Args).To(Equal([]string{"sh", "-l"}))
+		})
+	})
+})
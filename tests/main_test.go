@@ -38,6 +38,22 @@ var _ = BeforeSuite(func() {
 	Expect(err).ToNot(HaveOccurred(), "Error creating binary file for test suite", executable)
 })
 
+// useTempConfig creates a temporary directory before each spec of the container, the KITE_CONFIG variable
+// points to the given config file in it. The directory is removed after each spec.
+func useTempConfig(dir *string, configFile string) {
+	BeforeEach(func() {
+		var err error
+		*dir, err = os.MkdirTemp("", "kite-test-*.d")
+		Expect(err).ToNot(HaveOccurred())
+		os.Setenv("KITE_CONFIG", filepath.Join(*dir, filepath.FromSlash(configFile)))
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("KITE_CONFIG")
+		Expect(os.RemoveAll(*dir)).To(Succeed())
+	})
+}
+
 type TestCommand struct {
 	args   []string
 	config string
@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/markdown"
+)
+
+var _ = Describe("SOP markdown rendering", func() {
+	style := markdown.Style{
+		Heading:        tcell.ColorYellow,
+		Link:           tcell.ColorBlue,
+		Code:           tcell.ColorWhite,
+		CodeBackground: tcell.ColorNavy,
+		Quote:          tcell.ColorGray,
+		Note:           tcell.ColorAqua,
+		Warning:        tcell.ColorOrange,
+	}
+
+	var screen tcell.SimulationScreen
+
+	// draw renders the markdown in a text view and returns the lines displayed
+	draw := func(source string) []string {
+		doc := markdown.Render(source, style)
+
+		screen = tcell.NewSimulationScreen("UTF-8")
+		Expect(screen.Init()).To(Succeed())
+		screen.SetSize(60, 30)
+
+		textView := tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetText(doc.Text)
+		textView.SetRect(0, 0, 60, 30)
+		textView.Draw(screen)
+		screen.Show()
+
+		cells, width, height := screen.GetContents()
+		var lines []string
+
+		for y := 0; y < height; y++ {
+			var line strings.Builder
+			for x := 0; x < width; x++ {
+				line.WriteString(string(cells[y*width+x].Runes))
+			}
+			lines = append(lines, strings.TrimRight(line.String(), " "))
+		}
+
+		return lines
+	}
+
+	// styleAt returns the style of the first cell of the line displaying the given text
+	styleAt := func(lines []string, text string) tcell.Style {
+		for y, line := range lines {
+			if x := strings.Index(line, text); x >= 0 {
+				_, _, style, _ := screen.GetContent(len([]rune(line[:x])), y)
+				return style
+			}
+		}
+		Fail("text not displayed: " + text)
+		return tcell.StyleDefault
+	}
+
+	AfterEach(func() {
+		if screen != nil {
+			screen.Fini()
+			screen = nil
+		}
+	})
+
+	It("indents the nested lists", func() {
+		lines := draw("- first\n  - nested\n    1. ordered\n- second\n\n3. third\n4. fourth\n")
+
+		Expect(lines).To(ContainElements("• first", "  ◦ nested", "    1. ordered", "• second", "3. third", "4. fourth"))
+	})
+
+	It("formats the emphasis and the headings", func() {
+		lines := draw("# Cluster Down\n\nCheck **the nodes** and *the pods*, ~~not the console~~.\n")
+
+		Expect(lines[0]).To(Equal("Cluster Down"))
+		Expect(lines[2]).To(Equal("Check the nodes and the pods, not the console."))
+
+		fg, _, attrs := styleAt(lines, "Cluster").Decompose()
+		Expect(fg).To(Equal(tcell.ColorYellow))
+		Expect(attrs & tcell.AttrBold).ToNot(BeZero())
+
+		_, _, attrs = styleAt(lines, "the nodes").Decompose()
+		Expect(attrs & tcell.AttrBold).ToNot(BeZero())
+
+		_, _, attrs = styleAt(lines, "the pods").Decompose()
+		Expect(attrs & tcell.AttrItalic).ToNot(BeZero())
+		Expect(attrs & tcell.AttrBold).To(BeZero())
+	})
+
+	It("displays the code blocks on their background", func() {
+		lines := draw("Run:\n\n```sh\noc get pods\noc get nodes -o wide\n```\n")
+
+		Expect(lines).To(ContainElements(" oc get pods", " oc get nodes -o wide"))
+
+		_, bg, _ := styleAt(lines, "oc get pods").Decompose()
+		Expect(bg).To(Equal(tcell.ColorNavy))
+
+		// The background is padded to the longest line
+		for y, line := range lines {
+			if strings.Contains(line, "oc get pods") {
+				_, _, padding, _ := screen.GetContent(len(" oc get nodes -o wide"), y)
+				_, bg, _ = padding.Decompose()
+				Expect(bg).To(Equal(tcell.ColorNavy))
+			}
+		}
+	})
+
+	It("aligns the table columns", func() {
+		lines := draw("| Alert | Count |\n|:--|--:|\n| KubeAPIDown | 3 |\n| etcd | 12 |\n")
+
+		Expect(lines).To(ContainElements(
+			"Alert       │ Count",
+			"────────────┼──────",
+			"KubeAPIDown │     3",
+			"etcd        │    12",
+		))
+	})
+
+	It("displays the admonitions with their title", func() {
+		lines := draw("> [!WARNING]\n> Never delete the etcd pods.\n\nText.\n\n> **Note:** the alert resolves itself.\n\nText.\n\n> A quote.\n")
+
+		Expect(lines).To(ContainElements("│ Warning", "│ Never delete the etcd pods.", "│ Note", "│ the alert resolves itself.", "│ A quote."))
+		Expect(strings.Join(lines, "\n")).ToNot(ContainSubstring("[!WARNING]"))
+
+		fg, _, _ := styleAt(lines, "│ Warning").Decompose()
+		Expect(fg).To(Equal(tcell.ColorOrange))
+
+		fg, _, _ = styleAt(lines, "│ Note").Decompose()
+		Expect(fg).To(Equal(tcell.ColorAqua))
+
+		fg, _, _ = styleAt(lines, "│ A quote").Decompose()
+		Expect(fg).To(Equal(tcell.ColorGray))
+	})
+
+	It("puts the links in regions", func() {
+		doc := markdown.Render("See [the runbook](../runbook.md) and ![graph](graph.png).\n", style)
+
+		Expect(doc.Links).To(Equal([]string{"../runbook.md", "graph.png"}))
+		Expect(doc.Text).To(ContainSubstring(`["0"]`))
+		Expect(doc.Text).To(ContainSubstring(`["1"]`))
+
+		lines := draw("See [the runbook](../runbook.md) and ![graph](graph.png).\n")
+		Expect(lines[0]).To(Equal("See the runbook and [image: graph]."))
+	})
+
+	It("anchors the headings like GitHub", func() {
+		doc := markdown.Render("# Cluster Down\n\n## Check the `etcd` pods!\n\n## Cluster Down\n", style)
+
+		Expect(doc.Anchors).To(Equal(map[string]string{
+			"cluster-down":        "h0",
+			"check-the-etcd-pods": "h1",
+			"cluster-down-1":      "h2",
+		}))
+		Expect(doc.Text).To(ContainSubstring(`["h1"]`))
+	})
+
+	It("puts the code blocks in regions", func() {
+		doc := markdown.Render("Run:\n\n```sh\noc get pods -n <namespace>\n```\n\n    oc get nodes\n", style)
+
+		Expect(doc.CodeBlocks).To(Equal([]markdown.CodeBlock{
+			{Language: "sh", Code: "oc get pods -n <namespace>"},
+			{Code: "oc get nodes"},
+		}))
+		Expect(doc.Text).To(ContainSubstring(`["c0"]`))
+		Expect(doc.Text).To(ContainSubstring(`["c1"]`))
+	})
+
+	It("converts the HTML pages", func() {
+		source, err := markdown.FromHTML(`<html><head><script>alert("x")</script></head><body>
+<h2>Steps</h2>
+<ol><li>Check the <a href="../nodes.html">nodes</a></li><li>Run <code>oc get pods</code><ul><li>in <em>openshift-etcd</em></li></ul></li></ol>
+<pre><code class="language-sh">oc adm top nodes
+oc get co</code></pre>
+<table><tr><th>Alert</th><th>Severity</th></tr><tr><td>KubeAPIDown</td><td>critical</td></tr></table>
+<p>Use [brackets] and a * star.</p>
+</body></html>`)
+		Expect(err).ToNot(HaveOccurred())
+
+		lines := draw(source)
+		Expect(lines).To(ContainElements("Steps", " oc adm top nodes"))
+		Expect(strings.Join(lines, "\n")).To(ContainSubstring("1. Check the nodes\n2. Run oc get pods\n   ◦ in openshift-etcd\n"))
+		Expect(lines).To(ContainElements(
+			"Alert       │ Severity",
+			"KubeAPIDown │ critical",
+			"Use [brackets] and a * star.",
+		))
+		Expect(source).ToNot(ContainSubstring("alert"))
+		Expect(markdown.Render(source, style).Links).To(Equal([]string{"../nodes.html"}))
+	})
+
+	It("doesn't interpret the brackets of the text as tags", func() {
+		lines := draw("Set the label [red] on the `[node]`.\n")
+
+		Expect(lines[0]).To(Equal("Set the label [red] on the [node]."))
+	})
+})
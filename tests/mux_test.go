@@ -0,0 +1,378 @@
+package tests
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+// titles returns the titles of the slides in the page bar order.
+func titles(mux *ui.Mux) []string {
+	var titles []string
+	for _, slide := range mux.Slides() {
+		titles = append(titles, slide.Title())
+	}
+	return titles
+}
+
+// highlightedSlide returns the ID of the slide highlighted in the page bar.
+func highlightedSlide(mux *ui.Mux) string {
+	highlights := mux.PageBar.GetHighlights()
+	Expect(highlights).To(HaveLen(1))
+	return highlights[0]
+}
+
+var _ = Describe("terminal multiplexer", func() {
+	var (
+		mux     *ui.Mux
+		isEmpty bool
+	)
+
+	addSlide := func(title string) *ui.Slide {
+		return mux.Add("", title, ui.NewSplitView(tview.NewTextView().SetText(title), nil))
+	}
+
+	BeforeEach(func() {
+		isEmpty = false
+		mux = ui.NewMux().SetEmptyFunc(func() { isEmpty = true })
+
+		for i := 0; i <= 11; i++ {
+			addSlide(fmt.Sprintf("sop-%d", i))
+		}
+	})
+
+	When("slides are added", func() {
+		It("keeps more than 9 slides", func() {
+			Expect(mux.Len()).To(Equal(12))
+		})
+
+		It("switches to the new slide", func() {
+			slide := addSlide("new")
+
+			Expect(mux.Active()).To(Equal(slide))
+			Expect(highlightedSlide(mux)).To(Equal(fmt.Sprint(slide.ID())))
+
+			page, _ := mux.Pages.GetFrontPage()
+			Expect(page).To(Equal(fmt.Sprint(slide.ID())))
+		})
+
+		It("gives unique IDs to the slides, even after removals", func() {
+			last := mux.Active()
+			mux.Remove(last)
+
+			Expect(addSlide("new").ID()).ToNot(Equal(last.ID()))
+		})
+
+		It("finds the slides by key", func() {
+			slide := mux.Add("cluster:123", "my-cluster", ui.NewSplitView(tview.NewTextView(), nil))
+
+			Expect(mux.SlideByKey("cluster:123")).To(Equal(slide))
+			Expect(mux.SlideByKey("cluster:456")).To(BeNil())
+			Expect(mux.SlideByKey("")).To(BeNil())
+		})
+	})
+
+	When("slides are switched", func() {
+		It("switches to slides with multi-digit numbers", func() {
+			mux.SwitchTo(12)
+
+			Expect(mux.ActiveIndex()).To(Equal(11))
+			Expect(highlightedSlide(mux)).To(Equal(fmt.Sprint(mux.Slide(11).ID())))
+		})
+
+		It("ignores slide numbers out of range", func() {
+			mux.SwitchTo(13)
+			mux.SwitchTo(0)
+
+			Expect(mux.ActiveIndex()).To(Equal(11))
+		})
+
+		It("wraps around the first and last slides", func() {
+			mux.Next()
+			Expect(mux.ActiveIndex()).To(Equal(0))
+
+			mux.Previous()
+			Expect(mux.ActiveIndex()).To(Equal(11))
+		})
+
+		It("switches to the slide whose title is clicked", func() {
+			slide := mux.Slide(3)
+			mux.PageBar.Highlight(fmt.Sprint(slide.ID()))
+
+			Expect(mux.Active()).To(Equal(slide))
+		})
+	})
+
+	When("a slide is looked up", func() {
+		It("matches the slide number", func() {
+			Expect(mux.Find("10")).To(Equal(mux.Slide(9)))
+			Expect(mux.Find("13")).To(BeNil())
+		})
+
+		It("prefers exact names over prefixes and substrings", func() {
+			Expect(mux.Find("SOP-1")).To(Equal(mux.Slide(1)))
+			Expect(mux.Find("sop-1")).To(Equal(mux.Slide(1)))
+			Expect(mux.Find("sop")).To(Equal(mux.Slide(0)))
+			Expect(mux.Find("-11")).To(Equal(mux.Slide(11)))
+			Expect(mux.Find("unknown")).To(BeNil())
+		})
+	})
+
+	When("a slide is renamed", func() {
+		It("is found by its new name", func() {
+			mux.Rename(mux.Slide(3), "logs")
+
+			Expect(mux.Find("logs")).To(Equal(mux.Slide(3)))
+			Expect(mux.PageBar.GetText(true)).To(ContainSubstring("4 logs"))
+		})
+	})
+
+	When("a slide is moved", func() {
+		It("stays active", func() {
+			mux.SwitchTo(3)
+			slide := mux.Active()
+
+			mux.Move(-1)
+
+			Expect(mux.ActiveIndex()).To(Equal(1))
+			Expect(mux.Active()).To(Equal(slide))
+			Expect(highlightedSlide(mux)).To(Equal(fmt.Sprint(slide.ID())))
+			Expect(titles(mux)[:3]).To(Equal([]string{"sop-0", "sop-2", "sop-1"}))
+		})
+
+		It("does not move past the last slide", func() {
+			mux.Move(1)
+
+			Expect(mux.ActiveIndex()).To(Equal(11))
+			Expect(mux.Find("sop-11")).To(Equal(mux.Active()))
+		})
+	})
+
+	When("a slide is removed", func() {
+		It("switches to the previous slide if it was the active one", func() {
+			mux.SwitchTo(5)
+			previous := mux.Slide(3)
+
+			Expect(mux.Remove(mux.Active())).To(BeTrue())
+
+			Expect(mux.Active()).To(Equal(previous))
+			Expect(highlightedSlide(mux)).To(Equal(fmt.Sprint(previous.ID())))
+		})
+
+		It("switches to the next slide if the first slide was the active one", func() {
+			mux.SwitchTo(1)
+			next := mux.Slide(1)
+
+			mux.Remove(mux.Active())
+
+			Expect(mux.Active()).To(Equal(next))
+		})
+
+		It("keeps the active slide if another slide is removed", func() {
+			mux.SwitchTo(5)
+			active := mux.Active()
+
+			mux.Remove(mux.Slide(1))
+			mux.Remove(mux.Slide(9))
+
+			Expect(mux.Active()).To(Equal(active))
+			Expect(mux.ActiveIndex()).To(Equal(3))
+			Expect(highlightedSlide(mux)).To(Equal(fmt.Sprint(active.ID())))
+		})
+
+		It("ignores a slide which has already been removed", func() {
+			slide := mux.Slide(4)
+
+			Expect(mux.Remove(slide)).To(BeTrue())
+			Expect(mux.Remove(slide)).To(BeFalse())
+			Expect(mux.Len()).To(Equal(11))
+
+			mux.Switch(slide)
+			mux.Rename(slide, "renamed")
+
+			Expect(mux.Active()).ToNot(Equal(slide))
+			Expect(mux.Find("renamed")).To(BeNil())
+		})
+
+		It("keeps the last slide", func() {
+			for mux.Len() > 1 {
+				mux.Remove(mux.Active())
+			}
+
+			Expect(mux.Remove(mux.Active())).To(BeFalse())
+			Expect(isEmpty).To(BeTrue())
+			Expect(mux.Len()).To(Equal(1))
+		})
+	})
+})
+
+var _ = Describe("terminal multiplexer slides", func() {
+	var tui *ui.TUI
+
+	BeforeEach(func() {
+		tui = &ui.TUI{}
+		tui.Init()
+	})
+
+	// typeKeys sends the keys to the application as if they were typed, the keys which aren't captured go to the focused primitive
+	typeKeys := func(keys ...*tcell.EventKey) {
+		for _, key := range keys {
+			if event := tui.App.GetInputCapture()(key); event != nil && tui.App.GetFocus() != nil {
+				tui.App.GetFocus().InputHandler()(event, func(p tview.Primitive) { tui.App.SetFocus(p) })
+			}
+		}
+	}
+
+	When("a cluster is logged into twice", func() {
+		It("switches to the slide of the cluster", func() {
+			ui.AddNewSlide(tui, "my-cluster", "true", []string{"123"}, true)
+			slide := tui.Mux.Active()
+
+			ui.AddNewSlide(tui, "other-cluster", "true", []string{"456"}, true)
+			ui.AddNewSlide(tui, "my-cluster", "true", []string{"123"}, true)
+
+			Expect(tui.Mux.Len()).To(Equal(3))
+			Expect(tui.Mux.Active()).To(Equal(slide))
+		})
+	})
+
+	When("a SOP is opened twice", func() {
+		It("switches to the slide of the SOP, even if it has been renamed", func() {
+			ui.AddSOPSlide("sop", tview.NewTextView(), tui)
+			slide := tui.Mux.Active()
+			tui.Mux.Rename(slide, "renamed")
+
+			tui.Mux.SwitchTo(1)
+			ui.AddSOPSlide("sop", tview.NewTextView(), tui)
+
+			Expect(tui.Mux.Len()).To(Equal(2))
+			Expect(tui.Mux.Active()).To(Equal(slide))
+		})
+	})
+
+	When("a slide is switched to with the prompt", func() {
+		It("waits for Enter when a slide name starts with the digits", func() {
+			ui.AddNewSlide(tui, "2fa-cluster", "true", nil, false)
+			ui.AddNewSlide(tui, "other", "true", nil, false)
+			slide := tui.Mux.Find("2fa")
+
+			typeKeys(tcell.NewEventKey(tcell.KeyCtrlB, 0, tcell.ModCtrl), tcell.NewEventKey(tcell.KeyRune, '2', tcell.ModNone))
+			Expect(tui.Mux.Active().Title()).To(Equal("other"))
+
+			typeKeys(tcell.NewEventKey(tcell.KeyRune, 'f', tcell.ModNone), tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+			Expect(tui.Mux.Active()).To(Equal(slide))
+
+			typeKeys(tcell.NewEventKey(tcell.KeyCtrlB, 0, tcell.ModCtrl), tcell.NewEventKey(tcell.KeyRune, '3', tcell.ModNone))
+			Expect(tui.Mux.Active().Title()).To(Equal("other"))
+		})
+	})
+
+	When("a prefix key is configured", func() {
+		var textView *tview.TextView
+		var typed []rune
+
+		BeforeEach(func() {
+			var err error
+			tui.Keymap, err = ui.NewKeymap(&config.KeymapConfig{Prefix: "Ctrl+B"})
+			Expect(err).ToNot(HaveOccurred())
+
+			for i := 2; i <= 12; i++ {
+				ui.AddNewSlide(tui, fmt.Sprintf("cluster-%d", i), "true", nil, false)
+			}
+
+			// The slide records the keys it receives
+			typed = nil
+			textView = tview.NewTextView()
+			textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				typed = append(typed, event.Rune())
+				return nil
+			})
+			tui.Mux.Add("", "typed", ui.NewSplitView(textView, nil))
+			tui.App.SetFocus(textView)
+		})
+
+		It("switches to the slides numbered from 10 after the prefix key", func() {
+			typeKeys(
+				tcell.NewEventKey(tcell.KeyCtrlB, 0, tcell.ModCtrl),
+				tcell.NewEventKey(tcell.KeyRune, '1', tcell.ModNone),
+				tcell.NewEventKey(tcell.KeyRune, '2', tcell.ModNone),
+			)
+			Expect(tui.Mux.Active().Title()).To(Equal("cluster-12"))
+
+			typeKeys(
+				tcell.NewEventKey(tcell.KeyCtrlB, 0, tcell.ModCtrl),
+				tcell.NewEventKey(tcell.KeyRune, '2', tcell.ModNone),
+			)
+			Expect(tui.Mux.Active().Title()).To(Equal("cluster-2"))
+		})
+
+		It("drops the keys which aren't bound after the prefix key", func() {
+			typeKeys(
+				tcell.NewEventKey(tcell.KeyCtrlB, 0, tcell.ModCtrl),
+				tcell.NewEventKey(tcell.KeyRune, 'z', tcell.ModNone),
+				tcell.NewEventKey(tcell.KeyRune, 'y', tcell.ModNone),
+			)
+			Expect(typed).To(Equal([]rune{'y'}))
+			Expect(tui.Mux.Active().Title()).To(Equal("typed"))
+		})
+	})
+
+	When("the slides are listed", func() {
+		It("closes the list with the back key or once a slide is selected", func() {
+			ui.AddNewSlide(tui, "first", "true", nil, false)
+			ui.AddNewSlide(tui, "second", "true", nil, false)
+
+			typeKeys(tcell.NewEventKey(tcell.KeyRune, 'w', tcell.ModAlt))
+			Expect(tui.Root.HasPage(ui.SlideListPageTitle)).To(BeTrue())
+
+			typeKeys(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+			Expect(tui.Root.HasPage(ui.SlideListPageTitle)).To(BeFalse())
+
+			typeKeys(
+				tcell.NewEventKey(tcell.KeyRune, 'w', tcell.ModAlt),
+				tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone),
+				tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone),
+			)
+			Expect(tui.Root.HasPage(ui.SlideListPageTitle)).To(BeFalse())
+			Expect(tui.Mux.Active().Title()).To(Equal("first"))
+		})
+	})
+
+	When("a terminal exits", func() {
+		var (
+			first  *ui.Terminal
+			second *ui.Terminal
+		)
+
+		BeforeEach(func() {
+			first = ui.NewTerminal(exec.Command("true"), tui)
+			second = ui.NewTerminal(exec.Command("true"), tui)
+			tui.Mux.Add("", "first", ui.NewSplitView(first, nil))
+			tui.Mux.Add("", "second", ui.NewSplitView(second, nil))
+		})
+
+		It("exits the slide of the terminal rather than the active slide", func() {
+			active := tui.Mux.Active()
+
+			first.Closed(nil)
+
+			Expect(titles(tui.Mux)).To(Equal([]string{ui.KiteSlideTitle, "second"}))
+			Expect(tui.Mux.Active()).To(Equal(active))
+		})
+
+		It("ignores a terminal whose slide has already been exited", func() {
+			tui.Mux.Remove(tui.Mux.Find("first"))
+
+			first.Closed(nil)
+
+			Expect(titles(tui.Mux)).To(Equal([]string{ui.KiteSlideTitle, "second"}))
+		})
+	})
+})
@@ -58,7 +58,10 @@ var _ = Describe("kite oncall", func() {
 				},
 			}
 
-			mockClient.EXPECT().ListOnCalls(gomock.Any()).Return(listOnCallsResponse, nil).Times(1)
+			mockClient.EXPECT().ListOnCalls(gomock.Any()).DoAndReturn(func(opts pdApi.ListOnCallOptions) (*pdApi.ListOnCallsResponse, error) {
+				Expect(opts.ScheduleIDs).To(Equal([]string{"P995J2A", "P4TU2IT"}))
+				return listOnCallsResponse, nil
+			}).Times(1)
 			// TODO: Fix unit tests
 			// expectedResponse := []pdcli.OncallLayer{
 			// 	{
@@ -74,10 +77,32 @@ var _ = Describe("kite oncall", func() {
 			// 	},
 			// }
 
-			_, err := pdcli.TeamSREOnCall(mockClient)
+			_, err := pdcli.TeamSREOnCall(mockClient, []string{"P995J2A", "P4TU2IT"})
 			Expect(err).ToNot(HaveOccurred())
 			// TODO: Fix unit tests
 			// Expect(result).To(Equal(expectedResponse))
 		})
+
+		It("names the layers after their UTC start time", func() {
+			onCall := func(start string, end string) pdApi.OnCall {
+				return pdApi.OnCall{
+					Schedule: pdApi.Schedule{APIObject: pdApi.APIObject{Summary: "0-SREP Weekday Primary"}},
+					Start:    start,
+					End:      end,
+				}
+			}
+
+			mockClient.EXPECT().ListOnCalls(gomock.Any()).Return(&pdApi.ListOnCallsResponse{
+				OnCalls: []pdApi.OnCall{
+					onCall("2021-10-25T03:30:00Z", "2021-10-25T08:30:00Z"),
+					onCall("2021-10-25T08:30:00Z", "2021-10-25T13:30:00Z"),
+				},
+			}, nil).Times(1)
+
+			layers, err := pdcli.TeamSREOnCall(mockClient, []string{"P995J2A"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(layers).To(HaveLen(1))
+			Expect(layers[0].LayerId).To(Equal("Layer 2 [ APAC-W ]"))
+		})
 	})
 })
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+// tcellScreen returns a simulation screen the panes can be drawn on.
+func tcellScreen() tcell.Screen {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	_ = screen.Init()
+	screen.SetSize(100, 40)
+	return screen
+}
+
+var _ = Describe("split panes", func() {
+	var (
+		first  *tview.TextView
+		second *tview.TextView
+		third  *tview.TextView
+		view   *ui.SplitView
+	)
+
+	BeforeEach(func() {
+		first = tview.NewTextView()
+		second = tview.NewTextView()
+		third = tview.NewTextView()
+		view = ui.NewSplitView(first, nil)
+		view.SetRect(0, 0, 100, 40)
+	})
+
+	When("a pane is split", func() {
+		It("adds a pane which gets the focus", func() {
+			view.Split(ui.NewPane(second), tview.FlexColumn)
+
+			Expect(view.Panes()).To(HaveLen(2))
+			Expect(view.FocusedPane().Primitive()).To(Equal(second))
+			Expect(view.Find(first)).ToNot(BeNil())
+		})
+	})
+
+	When("the focus is moved", func() {
+		It("focuses the nearest pane in the given direction", func() {
+			view.Split(ui.NewPane(second), tview.FlexColumn)
+			view.Split(ui.NewPane(third), tview.FlexRow)
+			view.Draw(tcellScreen())
+
+			// second is on the top right, third on the bottom right
+			Expect(view.MoveFocus(0, -1).Primitive()).To(Equal(second))
+			Expect(view.MoveFocus(-1, 0).Primitive()).To(Equal(first))
+			Expect(view.MoveFocus(-1, 0).Primitive()).To(Equal(first))
+			Expect(view.MoveFocus(1, 0).Primitive()).ToNot(Equal(first))
+		})
+	})
+
+	When("a pane is closed", func() {
+		It("gives its space to the remaining panes", func() {
+			view.Split(ui.NewPane(second), tview.FlexColumn)
+			view.Split(ui.NewPane(third), tview.FlexRow)
+
+			Expect(view.Close(view.Find(third))).To(BeTrue())
+			Expect(view.Panes()).To(HaveLen(2))
+			Expect(view.Close(view.Find(second))).To(BeTrue())
+			Expect(view.Panes()).To(HaveLen(1))
+			Expect(view.FocusedPane().Primitive()).To(Equal(first))
+		})
+
+		It("does not close the last pane", func() {
+			Expect(view.Close(view.Find(first))).To(BeFalse())
+			Expect(view.Panes()).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/session"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secret"
+)
+
+var _ = Describe("profiles", func() {
+	var tmpDir string
+
+	const stagingKey = "s_NbAkKc66ryYTWUXYEu"
+
+	// readConfig returns the config file as is
+	readConfig := func() config.Config {
+		data, err := os.ReadFile(filepath.Join(tmpDir, "config.json"))
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg := config.Config{}
+		Expect(json.Unmarshal(data, &cfg)).To(Succeed())
+
+		return cfg
+	}
+
+	useTempConfig(&tmpDir, "config.json")
+
+	BeforeEach(func() {
+		os.Setenv(secret.PassphraseEnv, "correct horse battery staple")
+
+		data, err := json.Marshal(config.Config{
+			ApiKey: constants.SampleKey,
+			TeamID: "PT4KHLK",
+			Team:   "production",
+			Theme:  "dark",
+			Profiles: map[string]*config.Profile{
+				"staging": {ApiKey: stagingKey, TeamID: "PSTAGE1", Team: "staging"},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		config.SetProfile("")
+		os.Unsetenv(config.ProfileEnv)
+		os.Unsetenv(secret.PassphraseEnv)
+	})
+
+	It("reads the credentials and the team of the selected profile", func() {
+		cfg, err := config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ActiveProfile()).To(Equal(config.DefaultProfile))
+		Expect(cfg.Team).To(Equal("production"))
+		Expect(cfg.ProfileNames()).To(Equal([]string{config.DefaultProfile, "staging"}))
+
+		config.SetProfile("staging")
+		cfg, err = config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ActiveProfile()).To(Equal("staging"))
+		Expect(cfg.ApiKey).To(Equal(stagingKey))
+		Expect(cfg.Team).To(Equal("staging"))
+		Expect(cfg.Theme).To(Equal("dark"))
+	})
+
+	It("saves the team to the selected profile", func() {
+		config.SetProfile("staging")
+		cfg, err := config.Load()
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg.TeamID = "PSTAGE2"
+		cfg.Team = "staging-2"
+		Expect(config.Save(cfg)).To(Succeed())
+
+		saved := readConfig()
+		Expect(saved.Team).To(Equal("production"))
+		Expect(saved.ApiKey).To(Equal(constants.SampleKey))
+		Expect(saved.Profiles["staging"].Team).To(Equal("staging-2"))
+		Expect(saved.Profiles["staging"].ApiKey).To(Equal(stagingKey))
+	})
+
+	It("selects the profile with the environment and kite profile use", func() {
+		Expect(config.UseProfile("staging")).To(Succeed())
+		Expect(readConfig().Profile).To(Equal("staging"))
+
+		cfg, err := config.Read()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Team).To(Equal("staging"))
+
+		os.Setenv(config.ProfileEnv, config.DefaultProfile)
+		cfg, err = config.Read()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Team).To(Equal("production"))
+
+		// The flag takes precedence over the environment
+		config.SetProfile("staging")
+		cfg, err = config.Read()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Team).To(Equal("staging"))
+
+		Expect(config.UseProfile("unknown")).To(MatchError(ContainSubstring("unknown profile")))
+	})
+
+	It("selects the profile of the restored and served kite commands", func() {
+		var team string
+
+		root := &cobra.Command{Use: "kite"}
+		root.PersistentFlags().String(config.ProfileFlag, "", "")
+		root.AddCommand(&cobra.Command{
+			Use: "alerts",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := config.Read()
+				if err == nil {
+					team = cfg.Team
+				}
+				return err
+			},
+		})
+
+		Expect(session.Run(root, []string{"alerts"})).To(Succeed())
+		Expect(team).To(Equal("production"))
+
+		Expect(session.Run(root, []string{"alerts", "--profile", "staging"})).To(Succeed())
+		Expect(team).To(Equal("staging"))
+	})
+
+	It("rejects the unknown profiles", func() {
+		config.SetProfile("stagng")
+
+		_, err := config.Load()
+		Expect(err).To(MatchError(ContainSubstring("kite profile add stagng")))
+	})
+
+	It("keeps the secrets of the profiles apart in the secret storage", func() {
+		Expect(config.MoveSecrets(secret.File)).To(Succeed())
+		Expect(readConfig().ApiKey).To(BeEmpty())
+		Expect(readConfig().Profiles["staging"].ApiKey).To(BeEmpty())
+
+		store, err := secret.New(secret.File, tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Get(secret.APIKey)).To(Equal(constants.SampleKey))
+		Expect(store.Get("staging/" + secret.APIKey)).To(Equal(stagingKey))
+
+		config.SetProfile("staging")
+		cfg, err := config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ApiKey).To(Equal(stagingKey))
+
+		Expect(config.DeleteProfile("staging")).To(Succeed())
+		Expect(readConfig().Profiles).ToNot(HaveKey("staging"))
+
+		_, err = store.Get("staging/" + secret.APIKey)
+		Expect(err).To(MatchError(secret.ErrNotFound))
+
+		Expect(config.DeleteProfile(config.DefaultProfile)).To(MatchError(ContainSubstring("cannot be deleted")))
+	})
+})
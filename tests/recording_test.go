@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/recording"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+var _ = Describe("terminal recordings", func() {
+	var tmpDir string
+
+	useTempConfig(&tmpDir, "config.json")
+
+	When("a recording is named", func() {
+		start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+		It("is named after the incident, the cluster and the start time", func() {
+			Expect(recording.Name("Q1ABCDEF", "my-cluster", start)).To(Equal("Q1ABCDEF_my-cluster_20240102-150405"))
+		})
+
+		It("leaves out the unknown parts and the invalid characters", func() {
+			Expect(recording.Name("", "N/A", start)).To(Equal("20240102-150405"))
+			Expect(recording.Name("Q1ABCDEF", "../my cluster", start)).To(Equal("Q1ABCDEF_my-cluster_20240102-150405"))
+		})
+	})
+
+	When("a terminal is recorded", func() {
+		It("writes an asciicast file which can be listed and replayed", func() {
+			cast, err := recording.Create("incident", 40, 10, "my-cluster")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cast.Output("hello ")).To(Succeed())
+			Expect(cast.Output("world")).To(Succeed())
+			Expect(cast.Close()).To(Succeed())
+
+			recordings, err := recording.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recordings).To(HaveLen(1))
+			Expect(recordings[0].Name).To(Equal("incident"))
+			Expect(recordings[0].Header.Width).To(Equal(40))
+			Expect(recordings[0].Header.Height).To(Equal(10))
+			Expect(recordings[0].Header.Title).To(Equal("my-cluster"))
+
+			var out bytes.Buffer
+			Expect(recording.Play("incident", &out, 1, 0)).To(Succeed())
+			Expect(out.String()).To(HaveSuffix("hello world"))
+		})
+
+		It("adds a suffix to the name of a recording which already exists", func() {
+			first, err := recording.Create("incident", 40, 10, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(first.Close()).To(Succeed())
+
+			second, err := recording.Create("incident", 40, 10, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second.Close()).To(Succeed())
+
+			Expect(second.Name()).To(Equal("incident-2"))
+		})
+	})
+
+	When("a terminal slide is recorded", func() {
+		var (
+			app  *tview.Application
+			term *ui.Terminal
+		)
+
+		BeforeEach(func() {
+			app = tview.NewApplication().SetScreen(tcell.NewSimulationScreen("UTF-8"))
+			term = ui.NewTerminal(exec.Command("sh", "-c", "read line; seq 1 50; echo recorded-$line-é; sleep 10"), &ui.TUI{App: app})
+			term.SetRect(0, 0, 40, 10)
+			app.SetRoot(term, false)
+
+			go app.Run()
+		})
+
+		AfterEach(func() {
+			app.Stop()
+			term.Close()
+		})
+
+		It("records the output of the program", func() {
+			var name string
+
+			Eventually(func() error {
+				var err error
+				name, err = term.StartRecording("slide", "shell")
+				return err
+			}, "5s").Should(Succeed())
+
+			Expect(term.IsRecording()).To(BeTrue())
+			term.InputHandler()(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone), nil)
+			term.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+
+			Eventually(func() string {
+				var out bytes.Buffer
+				_ = recording.Play(name, &out, 100, 0)
+				return out.String()
+			}, "5s").Should(ContainSubstring("recorded-x-é"))
+
+			// The lines scrolled off the screen are recorded as the program wrote them
+			var out bytes.Buffer
+			Expect(recording.Play(name, &out, 100, 0)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring(strings.Join(numbers(1, 50), "\r\n")))
+
+			_, err := term.StopRecording()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(term.IsRecording()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+// numbers returns the lines printed by seq first last.
+func numbers(first int, last int) []string {
+	var lines []string
+	for i := first; i <= last; i++ {
+		lines = append(lines, fmt.Sprint(i))
+	}
+	return lines
+}
+
+var _ = Describe("terminal scrollback", func() {
+
+	When("lines scroll off the screen", func() {
+		It("keeps them, the oldest first", func() {
+			scrollback := ui.NewScrollback(100)
+
+			scrollback.Add([]string{"1"})
+			scrollback.Add([]string{"2", "2", ""})
+
+			Expect(scrollback.Lines()).To(Equal([]string{"1", "2", "2", ""}))
+		})
+
+		It("drops the oldest lines beyond its limit", func() {
+			scrollback := ui.NewScrollback(2)
+
+			for i := 1; i <= 10; i++ {
+				scrollback.Add([]string{fmt.Sprint(i)})
+			}
+
+			Expect(scrollback.Lines()).To(Equal([]string{"9", "10"}))
+		})
+	})
+
+	When("a program prints more lines than the terminal height", func() {
+		var (
+			app  *tview.Application
+			term *ui.Terminal
+		)
+
+		start := func(script string) {
+			app = tview.NewApplication().SetScreen(tcell.NewSimulationScreen("UTF-8"))
+			term = ui.NewTerminal(exec.Command("sh", "-c", script+"; sleep 10"), &ui.TUI{App: app})
+			term.SetRect(0, 0, 40, 10)
+			app.SetRoot(term, false)
+
+			go app.Run()
+		}
+
+		AfterEach(func() {
+			app.Stop()
+			term.Close()
+		})
+
+		It("keeps the lines scrolled off the terminal in its scrollback", func() {
+			start("seq 1 100")
+
+			Eventually(term.Scrollback, "5s").Should(Equal(numbers(1, 91)))
+		})
+
+		It("keeps all the lines of a single write", func() {
+			start("printf '%s\\n' $(seq 1 200)")
+
+			Eventually(term.Scrollback, "5s").Should(Equal(numbers(1, 191)))
+		})
+
+		It("keeps the repeated lines", func() {
+			start("printf 'same\\n\\n%.0s' $(seq 1 20)")
+
+			// The last 9 of the 40 lines remain on the screen
+			var lines []string
+			for i := 0; i < 20; i++ {
+				lines = append(lines, "same", "")
+			}
+
+			Eventually(term.Scrollback, "5s").Should(Equal(lines[:31]))
+			Consistently(term.Scrollback, "500ms").Should(Equal(lines[:31]))
+		})
+
+		It("doesn't keep the lines of the alternate screen", func() {
+			start("printf '\\033[?1049h'; seq 1 100; printf '\\033[?1049l'; seq 1 12")
+
+			Eventually(term.Scrollback, "5s").Should(Equal(numbers(1, 3)))
+			Consistently(term.Scrollback, "500ms").Should(Equal(numbers(1, 3)))
+		})
+	})
+})
@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/secret"
+)
+
+var _ = Describe("secrets", func() {
+	var (
+		tmpDir string
+		path   string
+	)
+
+	// writeConfig writes the config file as kite login did before the secret storages
+	writeConfig := func(cfg config.Config) {
+		data, err := json.Marshal(cfg)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0600)).To(Succeed())
+	}
+
+	// readConfig returns the fields of the config file
+	readConfig := func() map[string]interface{} {
+		data, err := os.ReadFile(filepath.Join(tmpDir, "config.json"))
+		Expect(err).ToNot(HaveOccurred())
+
+		fields := map[string]interface{}{}
+		Expect(json.Unmarshal(data, &fields)).To(Succeed())
+
+		return fields
+	}
+
+	useTempConfig(&tmpDir, "config.json")
+
+	BeforeEach(func() {
+		os.Setenv(secret.PassphraseEnv, "correct horse battery staple")
+
+		// The fake secret-tool keeps the secrets in files, by account
+		keyring := filepath.Join(tmpDir, "keyring")
+		bin := filepath.Join(tmpDir, "bin")
+		Expect(os.MkdirAll(keyring, 0700)).To(Succeed())
+		Expect(os.MkdirAll(bin, 0700)).To(Succeed())
+
+		script := fmt.Sprintf(`#!/bin/sh
+case "$1" in
+lookup) cat "%[1]s/$5" 2>/dev/null || exit 1 ;;
+store) cat > "%[1]s/$7" ;;
+clear) rm -f "%[1]s/$5" ;;
+esac
+`, keyring)
+		Expect(os.WriteFile(filepath.Join(bin, "secret-tool"), []byte(script), 0700)).To(Succeed())
+
+		path = os.Getenv("PATH")
+		os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+	})
+
+	AfterEach(func() {
+		os.Setenv("PATH", path)
+		os.Unsetenv(secret.PassphraseEnv)
+		os.Unsetenv(secret.EnvVar(secret.APIKey))
+	})
+
+	It("keeps the secrets in the config file by default", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey, AccessToken: "token"})
+
+		cfg, err := config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.SecretSource(secret.APIKey)).To(Equal(secret.Plain))
+		Expect(config.Save(cfg)).To(Succeed())
+		Expect(readConfig()).To(HaveKeyWithValue("api_key", constants.SampleKey))
+	})
+
+	It("encrypts the secrets in the secret file", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey, AccessToken: "token", Secrets: secret.File})
+
+		// The plain-text secrets are only moved to the secret file when they are migrated
+		cfg, err := config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ApiKey).To(Equal(constants.SampleKey))
+		Expect(cfg.HasPlainSecrets()).To(BeTrue())
+		Expect(readConfig()).To(HaveKey("api_key"))
+
+		Expect(config.MoveSecrets(secret.File)).To(Succeed())
+		Expect(readConfig()).ToNot(HaveKey("api_key"))
+		Expect(readConfig()).ToNot(HaveKey("gh_token"))
+
+		data, err := os.ReadFile(filepath.Join(tmpDir, secret.FileName))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).ToNot(ContainSubstring(constants.SampleKey))
+
+		store, err := secret.New(secret.File, tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Get(secret.GitHubToken)).To(Equal("token"))
+
+		os.Setenv(secret.PassphraseEnv, "wrong")
+		_, err = store.Get(secret.GitHubToken)
+		Expect(err).To(MatchError(ContainSubstring("the passphrase is wrong")))
+	})
+
+	It("stores the secrets in the keyring", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey, Secrets: secret.Keyring})
+
+		cfg, err := config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Save(cfg)).To(Succeed())
+		Expect(readConfig()).ToNot(HaveKey("api_key"))
+		Expect(os.ReadFile(filepath.Join(tmpDir, "keyring", secret.APIKey))).To(BeEquivalentTo(constants.SampleKey))
+
+		cfg, err = config.Read()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ApiKey).To(Equal(constants.SampleKey))
+		Expect(cfg.SecretSource(secret.APIKey)).To(Equal(secret.Keyring))
+		Expect(cfg.SecretSource(secret.GitHubToken)).To(Equal(config.SecretNotSet))
+	})
+
+	It("moves the secrets between the storages", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey, Secrets: secret.Keyring})
+
+		Expect(config.MoveSecrets(secret.Keyring)).To(Succeed())
+		Expect(config.MoveSecrets(secret.File)).To(Succeed())
+		Expect(filepath.Join(tmpDir, "keyring", secret.APIKey)).ToNot(BeAnExistingFile())
+		Expect(filepath.Join(tmpDir, secret.FileName)).To(BeAnExistingFile())
+
+		Expect(config.MoveSecrets(secret.Plain)).To(Succeed())
+		Expect(readConfig()).To(HaveKeyWithValue("api_key", constants.SampleKey))
+		Expect(readConfig()).ToNot(HaveKey("secrets"))
+	})
+
+	It("only moves the secrets which are set", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey})
+
+		Expect(config.MoveSecrets(secret.Keyring)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(tmpDir, "keyring", secret.APIKey))).To(BeEquivalentTo(constants.SampleKey))
+		Expect(filepath.Join(tmpDir, "keyring", secret.GitHubToken)).ToNot(BeAnExistingFile())
+	})
+
+	It("stores the tokens of the SOP hosts with the other secrets", func() {
+		writeConfig(config.Config{
+			ApiKey:   constants.SampleKey,
+			Secrets:  secret.File,
+			SOPHosts: []config.SOPHost{{Host: "gitlab.example.com", Type: "gitlab", Token: "glpat-token"}},
+			Profiles: map[string]*config.Profile{"staging": {ApiKey: constants.SampleKey}},
+		})
+
+		// sopHostToken returns the token of the SOP host in the config file
+		sopHostToken := func() interface{} {
+			return readConfig()["sop_hosts"].([]interface{})[0].(map[string]interface{})["token"]
+		}
+
+		Expect(config.MoveSecrets(secret.File)).To(Succeed())
+		Expect(sopHostToken()).To(BeNil())
+
+		store, err := secret.New(secret.File, tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Get(config.SOPHostSecret("gitlab.example.com"))).To(Equal("glpat-token"))
+
+		// The token is shared by the profiles
+		config.SetProfile("staging")
+		defer config.SetProfile("")
+
+		cfg, err := config.Read()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.SOPHosts[0].Token).To(Equal("glpat-token"))
+		Expect(cfg.SecretSource(config.SOPHostSecret("gitlab.example.com"))).To(Equal(secret.File))
+
+		Expect(config.MoveSecrets(secret.Plain)).To(Succeed())
+		Expect(sopHostToken()).To(Equal("glpat-token"))
+	})
+
+	It("reads the secrets from the environment and the commands without saving them", func() {
+		writeConfig(config.Config{
+			ApiKey:         "u+plaintextkey1234567",
+			SecretCommands: map[string]string{secret.GitHubToken: "printf 'token\\nmetadata'"},
+		})
+		os.Setenv(secret.EnvVar(secret.APIKey), constants.SampleKey)
+
+		cfg, err := config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ApiKey).To(Equal(constants.SampleKey))
+		Expect(cfg.AccessToken).To(Equal("token"))
+		Expect(cfg.SecretSource(secret.GitHubToken)).To(Equal(config.SecretFromCommand))
+
+		Expect(config.Save(cfg)).To(Succeed())
+		Expect(readConfig()).To(HaveKeyWithValue("api_key", "u+plaintextkey1234567"))
+		Expect(readConfig()).ToNot(HaveKey("gh_token"))
+	})
+
+	It("reports the secrets which cannot be read", func() {
+		writeConfig(config.Config{SecretCommands: map[string]string{secret.APIKey: "exit 1"}})
+
+		_, err := config.Load()
+		Expect(err).To(MatchError(ContainSubstring("the secret command 'exit 1' failed")))
+
+		_, err = secret.New("vault", tmpDir)
+		Expect(err).To(MatchError(ContainSubstring("unknown secret storage")))
+	})
+})
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/server"
+)
+
+var _ = Describe("kite server", func() {
+	var (
+		tmpDir string
+		app    *tview.Application
+		srv    *server.Server
+	)
+
+	// attach attaches a client to the server, the returned channel receives the result of Attach
+	attach := func(out *gbytes.Buffer) (*os.File, chan error) {
+		in, input, err := os.Pipe()
+		Expect(err).ToNot(HaveOccurred())
+
+		done := make(chan error, 1)
+
+		go func() {
+			defer GinkgoRecover()
+			done <- server.Attach("test", in, out)
+		}()
+
+		return input, done
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "kite-server-*.d")
+		Expect(err).ToNot(HaveOccurred())
+		os.Setenv("XDG_RUNTIME_DIR", tmpDir)
+		os.Setenv("TERM", "xterm")
+
+		textView := tview.NewTextView().SetText("kite-server-test")
+		textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Rune() == 'x' {
+				textView.SetText("kite-server-input")
+			}
+			return event
+		})
+
+		app = tview.NewApplication().SetRoot(textView, true)
+		app.SetScreen(server.Placeholder())
+
+		srv, err = server.New("test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(srv.Listen()).To(Succeed())
+
+		go app.Run()
+		go srv.Serve(app)
+	})
+
+	AfterEach(func() {
+		app.Stop()
+		Expect(srv.Close()).To(Succeed())
+		os.Unsetenv("XDG_RUNTIME_DIR")
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("rejects server names which are not file names", func() {
+		_, err := server.SocketPath("../test")
+		Expect(err).To(HaveOccurred())
+
+		path, err := server.SocketPath("test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(path).To(Equal(filepath.Join(tmpDir, "kite", "test.sock")))
+	})
+
+	It("refuses a socket directory other users can access", func() {
+		dir := filepath.Join(tmpDir, "kite")
+		Expect(os.Chmod(dir, 0755)).To(Succeed())
+
+		_, err := server.SocketDir()
+		Expect(err).To(MatchError(ContainSubstring("0700")))
+
+		Expect(os.Rename(dir, filepath.Join(tmpDir, "other"))).To(Succeed())
+		Expect(os.Symlink(filepath.Join(tmpDir, "other"), dir)).To(Succeed())
+
+		_, err = server.SocketDir()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("attaches a client while another one doesn't introduce itself", func() {
+		path, err := server.SocketPath("test")
+		Expect(err).ToNot(HaveOccurred())
+
+		silent, err := net.Dial("unix", path)
+		Expect(err).ToNot(HaveOccurred())
+		defer silent.Close()
+
+		out := gbytes.NewBuffer()
+		input, done := attach(out)
+		defer input.Close()
+
+		Eventually(out, "2s").Should(gbytes.Say("kite-server-test"))
+
+		srv.Detach()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("refuses to start twice", func() {
+		other, err := server.New("test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(other.Listen()).ToNot(Succeed())
+
+		Expect(server.List()).To(Equal([]string{"test"}))
+	})
+
+	It("displays the application on the attached client and forwards its input", func() {
+		out := gbytes.NewBuffer()
+		input, done := attach(out)
+		defer input.Close()
+
+		Eventually(out).Should(gbytes.Say("kite-server-test"))
+		Expect(srv.Attached()).To(BeTrue())
+
+		_, err := input.Write([]byte("x"))
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(out).Should(gbytes.Say("kite-server-input"))
+
+		srv.Detach()
+
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(srv.Attached()).To(BeFalse())
+		Expect(server.IsRunning("test")).To(BeTrue())
+	})
+
+	It("detaches the previous client when another one is attached", func() {
+		first := gbytes.NewBuffer()
+		firstInput, firstDone := attach(first)
+		defer firstInput.Close()
+		Eventually(first).Should(gbytes.Say("kite-server-test"))
+
+		second := gbytes.NewBuffer()
+		secondInput, secondDone := attach(second)
+		defer secondInput.Close()
+
+		Eventually(firstDone).Should(Receive(BeNil()))
+		Eventually(second).Should(gbytes.Say("kite-server-test"))
+		Expect(srv.Attached()).To(BeTrue())
+
+		Expect(srv.Close()).To(Succeed())
+
+		Eventually(secondDone).Should(Receive(BeNil()))
+		Expect(server.IsRunning("test")).To(BeFalse())
+	})
+})
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"os/exec"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+var _ = Describe("sessions", func() {
+	var (
+		tmpDir string
+		tui    *ui.TUI
+	)
+
+	useTempConfig(&tmpDir, "config.json")
+
+	BeforeEach(func() {
+		tui = &ui.TUI{}
+		tui.Init()
+		tui.SessionName = "work"
+		tui.SessionArgs = []string{"alerts", "--assigned-to=team"}
+	})
+
+	When("a session is saved", func() {
+		It("is listed and loaded by name", func() {
+			Expect(tui.SaveCurrentSession()).To(Succeed())
+
+			names, err := ui.ListSessions()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"work"}))
+
+			session, err := ui.LoadSession("work")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(session.Args).To(Equal([]string{"alerts", "--assigned-to=team"}))
+			Expect(session.Slides).To(HaveLen(1))
+			Expect(session.Slides[0].Layout.Type).To(Equal(ui.PaneKite))
+		})
+
+		It("rejects session names which are not file names", func() {
+			_, err := ui.SessionPath("../config")
+			Expect(err).To(HaveOccurred())
+
+			_, err = ui.LoadSession("missing")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("kite exits", func() {
+		// exit runs kite until its last slide exits
+		exit := func() {
+			tui.App.SetScreen(tcell.NewSimulationScreen("UTF-8"))
+
+			exited := make(chan error, 1)
+			go func() { exited <- tui.StartApp() }()
+			tui.App.QueueUpdate(func() { tui.Mux.Remove(tui.Mux.Active()) })
+
+			Eventually(exited, "5s").Should(Receive(BeNil()))
+		}
+
+		It("saves the restored session, even when the last slide exits", func() {
+			tui.RestoredSession = &ui.Session{Name: "work"}
+
+			exit()
+			Expect(ui.ListSessions()).To(Equal([]string{"work"}))
+		})
+
+		It("leaves the saved sessions as they are when no session was restored", func() {
+			exit()
+			Expect(ui.ListSessions()).To(BeEmpty())
+		})
+	})
+
+	When("a session is restored", func() {
+		var restored *ui.TUI
+
+		BeforeEach(func() {
+			tui.IncidentID = "Q0RIJJZL24RC6W"
+			ui.AddNewSlide(tui, "my-cluster", "true", []string{"123"}, true)
+			cluster := tui.Mux.Active()
+			cluster.View().Split(ui.NewPane(ui.NewTerminal(exec.Command("true"), tui)), tview.FlexRow)
+			tui.Mux.Move(-1)
+			Expect(tui.SaveCurrentSession()).To(Succeed())
+
+			session, err := ui.LoadSession("work")
+			Expect(err).ToNot(HaveOccurred())
+
+			restored = &ui.TUI{}
+			restored.Init()
+			restored.RestoreSession(session)
+		})
+
+		It("re-creates the slides in the saved order", func() {
+			Expect(titles(restored.Mux)).To(Equal([]string{"my-cluster", ui.KiteSlideTitle}))
+			Expect(restored.Mux.ActiveIndex()).To(Equal(0))
+			Expect(restored.Mux.Slide(1).View().Find(restored.Layout)).ToNot(BeNil())
+		})
+
+		It("re-creates the panes of the slides", func() {
+			Expect(restored.Mux.Slide(0).View().Panes()).To(HaveLen(2))
+
+			state := restored.CurrentSession().Slides[0].Layout
+			Expect(state.Split).To(Equal(ui.SplitHorizontal))
+			Expect(state.Panes[0].Type).To(Equal(ui.PaneTerminal))
+			Expect(state.Panes[0].Args).To(Equal([]string{"123"}))
+			Expect(state.Panes[0].IncidentID).To(Equal("Q0RIJJZL24RC6W"))
+			Expect(state.Panes[0].Cluster).To(Equal("my-cluster"))
+		})
+
+		It("keeps the cluster slides unique", func() {
+			ui.AddNewSlide(restored, "my-cluster", "true", []string{"123"}, true)
+
+			Expect(restored.Mux.Len()).To(Equal(2))
+		})
+	})
+
+	When("a saved program cannot be found", func() {
+		It("skips its pane", func() {
+			session := tui.CurrentSession()
+			session.Slides = append(session.Slides, ui.SlideState{
+				Title: "split",
+				Layout: ui.PaneState{
+					Split: ui.SplitVertical,
+					Panes: []ui.PaneState{
+						{Type: ui.PaneTerminal, Command: "true"},
+						{Type: ui.PaneTerminal, Command: "/missing/kite-test-command"},
+					},
+				},
+			})
+			session.Slides = append(session.Slides, ui.SlideState{
+				Title:  "missing",
+				Layout: ui.PaneState{Type: ui.PaneTerminal, Command: "/missing/kite-test-command"},
+			})
+
+			restored := &ui.TUI{}
+			restored.Init()
+			restored.RestoreSession(session)
+
+			Expect(titles(restored.Mux)).To(Equal([]string{ui.KiteSlideTitle, "split"}))
+			Expect(restored.Mux.Slide(1).View().Panes()).To(HaveLen(1))
+		})
+	})
+})
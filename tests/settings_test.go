@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+var _ = Describe("settings", func() {
+	var tmpDir string
+
+	// writeConfig writes the config file
+	writeConfig := func(cfg config.Config) {
+		data, err := json.Marshal(cfg)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0600)).To(Succeed())
+	}
+
+	useTempConfig(&tmpDir, "config.json")
+
+	AfterEach(func() {
+		os.Unsetenv("KITE_COLUMNS")
+		os.Unsetenv("KITE_REFRESH_INTERVAL")
+		config.SetProfile("")
+		utils.TimeZone = time.UTC
+	})
+
+	It("layers the defaults, the config file, the environment and the flags", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey, Columns: "alert,status", IncidentsLimit: 20})
+
+		flags := pflag.NewFlagSet("alerts", pflag.ContinueOnError)
+		flags.String("columns", "", "")
+		flags.Uint("limit", 0, "")
+
+		settings, err := config.LoadSettings(flags)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.AssignedTo).To(Equal("self"))
+		Expect(settings.Columns).To(Equal("alert,status"))
+		Expect(settings.IncidentsLimit).To(Equal(uint(20)))
+		Expect(settings.TimeZone).To(Equal(time.UTC))
+		Expect(settings.ScheduleIDs).To(HaveLen(5))
+
+		os.Setenv("KITE_COLUMNS", "alert,age")
+		os.Setenv("KITE_REFRESH_INTERVAL", "2m")
+		settings, err = config.LoadSettings(flags)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.Columns).To(Equal("alert,age"))
+		Expect(settings.RefreshInterval).To(Equal(2 * time.Minute))
+
+		Expect(flags.Set("columns", "alert")).To(Succeed())
+		settings, err = config.LoadSettings(flags)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.Columns).To(Equal("alert"))
+		Expect(settings.IncidentsLimit).To(Equal(uint(20)))
+	})
+
+	It("tells where the settings come from", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey, Columns: "alert"})
+		os.Setenv("KITE_REFRESH_INTERVAL", "1m")
+
+		cfg, err := config.Read()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, source, err := cfg.Value("columns")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(source).To(Equal(config.SettingFromFile))
+
+		value, source, _ := cfg.Value("refresh_interval")
+		Expect(value).To(Equal("1m"))
+		Expect(source).To(ContainSubstring("KITE_REFRESH_INTERVAL"))
+
+		value, source, _ = cfg.Value("time_zone")
+		Expect(value).To(Equal("UTC"))
+		Expect(source).To(Equal(config.SettingFromDefault))
+
+		_, source, _ = cfg.Value("terminal")
+		Expect(source).To(Equal(config.SettingNotSet))
+
+		_, _, err = cfg.Value("colour")
+		Expect(err).To(MatchError(ContainSubstring("unknown setting")))
+	})
+
+	It("validates the settings", func() {
+		cfg := config.New()
+
+		Expect(cfg.Set("columns", "alert,cluster.name")).To(Succeed())
+		Expect(cfg.Set("columns", "alert,owner")).To(MatchError(ContainSubstring("unknown column 'owner'")))
+		Expect(cfg.Set("assigned_to", "everyone")).To(HaveOccurred())
+		Expect(cfg.Set("refresh_interval", "5s")).To(MatchError(ContainSubstring("at least 10s")))
+		Expect(cfg.Set("refresh_interval", "0")).To(Succeed())
+		Expect(cfg.Set("time_zone", "Mars/Base")).To(HaveOccurred())
+		Expect(cfg.Set("incidents_limit", "0")).To(HaveOccurred())
+		Expect(cfg.Set("incidents_limit", "101")).To(HaveOccurred())
+		Expect(cfg.Set("triggered_incidents_limit", "50")).To(Succeed())
+		Expect(cfg.Set("schedule_ids", "P995J2A,nope")).To(MatchError(ContainSubstring("invalid schedule ID 'nope'")))
+		Expect(cfg.Set("team_id", "PT4KHLK")).To(Succeed())
+		Expect(cfg.Set("api_key", "short")).To(MatchError(ContainSubstring("invalid API key")))
+
+		// An empty value restores the default
+		Expect(cfg.Set("columns", "")).To(Succeed())
+		value, _, _ := cfg.Value("columns")
+		Expect(value).To(Equal(constants.AlertColumns))
+	})
+
+	It("rejects the invalid settings of the config file and the environment", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey, RefreshInterval: "soon"})
+
+		_, err := config.Load()
+		Expect(err).To(MatchError(ContainSubstring("kite config set refresh_interval")))
+
+		writeConfig(config.Config{ApiKey: constants.SampleKey})
+		os.Setenv("KITE_COLUMNS", "owner")
+
+		_, err = config.LoadSettings(nil)
+		Expect(err).To(MatchError(ContainSubstring("KITE_COLUMNS")))
+	})
+
+	It("reads the defaults without a config file, but not with a malformed one", func() {
+		settings, err := config.LoadSettings(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.Columns).To(Equal(constants.AlertColumns))
+
+		Expect(os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(`{"columns": `), 0600)).To(Succeed())
+
+		_, err = config.LoadSettings(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("saves the settings to the config file", func() {
+		writeConfig(config.Config{ApiKey: constants.SampleKey})
+
+		cfg, err := config.Read()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Set("schedule_ids", "P995J2A, P4TU2IT")).To(Succeed())
+		Expect(cfg.Set("incidents_limit", "30")).To(Succeed())
+		Expect(config.Save(cfg)).To(Succeed())
+
+		cfg, err = config.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.ScheduleIDs).To(Equal([]string{"P995J2A", "P4TU2IT"}))
+		Expect(cfg.IncidentsLimit).To(Equal(uint(30)))
+	})
+
+	It("reads the team of the selected profile", func() {
+		writeConfig(config.Config{
+			ApiKey:   constants.SampleKey,
+			TeamID:   "PT4KHLK",
+			Profiles: map[string]*config.Profile{"staging": {TeamID: "PSTAGE1"}},
+		})
+		config.SetProfile("staging")
+
+		settings, err := config.LoadSettings(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.TeamID).To(Equal("PSTAGE1"))
+	})
+
+	It("formats the dates in the time zone", func() {
+		Expect(utils.FormatTimestamp("2021-10-25T03:30:00Z")).To(Equal("10-25-2021 03:30 UTC"))
+
+		zone, err := time.LoadLocation("Asia/Kolkata")
+		Expect(err).ToNot(HaveOccurred())
+		utils.TimeZone = zone
+
+		Expect(utils.FormatTimestamp("2021-10-25T03:30:00Z")).To(Equal("10-25-2021 09:00 IST"))
+	})
+})
@@ -0,0 +1,541 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/rivo/tview"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/sop"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/utils"
+)
+
+var _ = Describe("SOP repository mirror", func() {
+	var (
+		tmpDir   string
+		upstream string
+		remote   string
+	)
+
+	// git runs git in the upstream repository
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", upstream, "-c", "user.name=kite", "-c", "user.email=kite@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		Expect(err).ToNot(HaveOccurred(), string(output))
+	}
+
+	// commit adds the given file to the upstream repository
+	commit := func(path string, content string) {
+		file := filepath.Join(upstream, filepath.FromSlash(path))
+		Expect(os.MkdirAll(filepath.Dir(file), 0700)).To(Succeed())
+		Expect(os.WriteFile(file, []byte(content), 0600)).To(Succeed())
+		git("add", "-A")
+		git("commit", "-q", "-m", "Add "+path)
+	}
+
+	useTempConfig(&tmpDir, "config/config.json")
+
+	BeforeEach(func() {
+		upstream = filepath.Join(tmpDir, "upstream", "openshift", "ops-sop")
+		Expect(os.MkdirAll(upstream, 0700)).To(Succeed())
+		git("init", "-q", "-b", "master")
+		commit("v4/alerts/ClusterOperatorDown.md", "# ClusterOperatorDown\n\nCheck the operator status.\nThe etcd quorum may be lost.\n")
+		commit("v4/alerts/KubeAPIDown.md", "# KubeAPIDown\n\nThe API server is down, check etcd.\n")
+
+		remote = sop.RemoteFormat
+		sop.RemoteFormat = filepath.Join(tmpDir, "upstream", "%s", "%s")
+	})
+
+	AfterEach(func() {
+		sop.RemoteFormat = remote
+	})
+
+	When("the repository isn't synchronized", func() {
+		It("reports it", func() {
+			_, err := sop.Read(sop.DefaultOwner, sop.DefaultRepo, "v4/alerts/KubeAPIDown.md")
+			Expect(err).To(Equal(sop.ErrNotCached))
+		})
+	})
+
+	When("a GitHub token is given", func() {
+		It("passes it to git in its environment rather than in its arguments", func() {
+			realGit, err := exec.LookPath("git")
+			Expect(err).ToNot(HaveOccurred())
+
+			// The fake git logs its arguments and the configured header before running git
+			bin := filepath.Join(tmpDir, "bin")
+			Expect(os.MkdirAll(bin, 0700)).To(Succeed())
+			script := fmt.Sprintf("#!/bin/sh\necho \"$*\" >> %[1]s/args.log\necho \"$GIT_CONFIG_VALUE_0\" >> %[1]s/env.log\nexec %[2]s \"$@\"\n", bin, realGit)
+			Expect(os.WriteFile(filepath.Join(bin, "git"), []byte(script), 0700)).To(Succeed())
+
+			path := os.Getenv("PATH")
+			os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+			defer os.Setenv("PATH", path)
+
+			Expect(sop.Sync(sop.DefaultOwner, sop.DefaultRepo, "secret-token", io.Discard)).To(Succeed())
+			credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:secret-token"))
+
+			args, err := os.ReadFile(filepath.Join(bin, "args.log"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(args)).To(ContainSubstring("clone"))
+			Expect(string(args)).ToNot(ContainSubstring(credentials))
+
+			Expect(os.ReadFile(filepath.Join(bin, "env.log"))).To(ContainSubstring("Authorization: Basic " + credentials))
+		})
+	})
+
+	When("the repository is synchronized", func() {
+		BeforeEach(func() {
+			Expect(sop.Sync(sop.DefaultOwner, sop.DefaultRepo, "", io.Discard)).To(Succeed())
+		})
+
+		It("reads the SOPs offline", func() {
+			content, err := sop.Read(sop.DefaultOwner, sop.DefaultRepo, "v4/alerts/KubeAPIDown.md")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(ContainSubstring("The API server is down"))
+
+			_, err = sop.Read(sop.DefaultOwner, sop.DefaultRepo, "../../../config.json")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reads the SOPs of the synchronized branch only", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Get("ref")).To(Equal("release-4.14"))
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": "%s"}`, base64.StdEncoding.EncodeToString([]byte("# Release SOP\n")))
+			}))
+			defer server.Close()
+
+			gitHubAPI := utils.GitHubAPIURL
+			utils.GitHubAPIURL = server.URL + "/"
+			defer func() { utils.GitHubAPIURL = gitHubAPI }()
+
+			content, err := utils.FetchMarkdown("https://github.com/openshift/ops-sop/blob/master/v4/alerts/KubeAPIDown.md")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(ContainSubstring("The API server is down"))
+
+			content, err = utils.FetchMarkdown("https://github.com/openshift/ops-sop/blob/release-4.14/v4/alerts/KubeAPIDown.md")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(Equal("# Release SOP\n"))
+		})
+
+		It("updates the SOPs", func() {
+			commit("v4/alerts/etcdMembersDown.md", "# etcdMembersDown\n")
+			Expect(sop.Sync(sop.DefaultOwner, sop.DefaultRepo, "", io.Discard)).To(Succeed())
+
+			_, err := sop.Read(sop.DefaultOwner, sop.DefaultRepo, "v4/alerts/etcdMembersDown.md")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("finds the SOPs containing all the words searched", func() {
+			matches, err := sop.Search(sop.DefaultOwner, sop.DefaultRepo, "ETCD quorum", 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(matches).To(HaveLen(1))
+			Expect(matches[0].Path).To(Equal("v4/alerts/ClusterOperatorDown.md"))
+			Expect(matches[0].Line).To(Equal(4))
+			Expect(matches[0].Text).To(Equal("The etcd quorum may be lost."))
+
+			matches, err = sop.Search(sop.DefaultOwner, sop.DefaultRepo, "etcd", 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(matches).To(HaveLen(2))
+		})
+
+		It("finds the SOPs named after the alerts", func() {
+			candidates := sop.Resolve(sop.DefaultOwner, sop.DefaultRepo, sop.Alert{Name: "KubeAPIDown CRITICAL (1)"}, nil, 10)
+			Expect(candidates).ToNot(BeEmpty())
+			Expect(candidates[0].Path).To(Equal("v4/alerts/KubeAPIDown.md"))
+			Expect(candidates[0].Score).To(Equal(100))
+
+			// The alert name of the labels, and the names a few edits away are matched
+			candidates = sop.Resolve(sop.DefaultOwner, sop.DefaultRepo, sop.Alert{Name: "Cluster has issues", Labels: "alertname=ClusterOperatorDown"}, nil, 10)
+			Expect(candidates).To(HaveLen(1))
+			Expect(candidates[0].Path).To(Equal("v4/alerts/ClusterOperatorDown.md"))
+
+			candidates = sop.Resolve(sop.DefaultOwner, sop.DefaultRepo, sop.Alert{Name: "ClusterOperatorsDown"}, nil, 10)
+			Expect(candidates).To(HaveLen(1))
+			Expect(candidates[0].URL).To(Equal("https://github.com/openshift/ops-sop/blob/master/v4/alerts/ClusterOperatorDown.md"))
+
+			Expect(sop.Resolve(sop.DefaultOwner, sop.DefaultRepo, sop.Alert{Name: "NodeFilesystemFull"}, nil, 10)).To(BeEmpty())
+		})
+
+		It("maps the alerts to their SOP with the rules", func() {
+			rulesFile := filepath.Join(tmpDir, "config", sop.RulesFile)
+			Expect(os.MkdirAll(filepath.Dir(rulesFile), 0700)).To(Succeed())
+			Expect(os.WriteFile(rulesFile, []byte(`[
+				{"alert": "^KubeAPI", "labels": "namespace=openshift-kube-apiserver", "sop": "v4/alerts/ClusterOperatorDown.md"},
+				{"service": "etcd", "sop": "https://docs.example.com/etcd.html"},
+				{"alert": "^KubeAPI", "sop": "v4/alerts/KubeAPIDown.md"}
+			]`), 0600)).To(Succeed())
+
+			rules, err := sop.ReadRules()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rules).To(HaveLen(3))
+
+			alert := sop.Alert{Name: "KubeAPIDown", Service: "prod-etcd-cluster", Labels: "namespace=openshift-kube-apiserver"}
+			candidates := sop.Resolve(sop.DefaultOwner, sop.DefaultRepo, alert, rules, 10)
+
+			// The rules come first, the SOPs of the rules aren't repeated
+			Expect(candidates).To(HaveLen(3))
+			Expect(candidates[0]).To(Equal(sop.Candidate{
+				URL:  "https://github.com/openshift/ops-sop/blob/master/v4/alerts/ClusterOperatorDown.md",
+				Path: "v4/alerts/ClusterOperatorDown.md",
+				Rule: true,
+			}))
+			Expect(candidates[1]).To(Equal(sop.Candidate{URL: "https://docs.example.com/etcd.html", Rule: true}))
+			Expect(candidates[2].Path).To(Equal("v4/alerts/KubeAPIDown.md"))
+			Expect(candidates[2].Rule).To(BeTrue())
+
+			Expect(sop.Resolve(sop.DefaultOwner, sop.DefaultRepo, sop.Alert{Name: "KubeAPIDown"}, rules, 10)).To(HaveLen(1))
+		})
+
+		It("reads the SOP paths of the rules in the repository, the local files from their URL", func() {
+			rules := []sop.Rule{
+				{Alert: "^KubeAPI", SOP: "/v4/alerts/KubeAPIDown.md"},
+				{Alert: "^KubeAPI", SOP: "file:///srv/sop/KubeAPIDown.md"},
+			}
+
+			candidates := sop.Resolve(sop.DefaultOwner, sop.DefaultRepo, sop.Alert{Name: "KubeAPIDown"}, rules, 10)
+			Expect(candidates).To(HaveLen(2))
+			Expect(candidates[0].Path).To(Equal("v4/alerts/KubeAPIDown.md"))
+			Expect(candidates[0].URL).To(Equal("https://github.com/openshift/ops-sop/blob/master/v4/alerts/KubeAPIDown.md"))
+			Expect(candidates[1]).To(Equal(sop.Candidate{URL: "file:///srv/sop/KubeAPIDown.md", Rule: true}))
+		})
+
+		It("reports the invalid rules", func() {
+			rulesFile := filepath.Join(tmpDir, "config", sop.RulesFile)
+			Expect(os.MkdirAll(filepath.Dir(rulesFile), 0700)).To(Succeed())
+			Expect(os.WriteFile(rulesFile, []byte(`[{"alert": "(", "sop": "v4/alerts/KubeAPIDown.md"}]`), 0600)).To(Succeed())
+
+			_, err := sop.ReadRules()
+			Expect(err).To(MatchError(ContainSubstring("invalid pattern '('")))
+		})
+
+		It("links the SOPs to GitHub", func() {
+			Expect(sop.URL(sop.DefaultOwner, sop.DefaultRepo, "v4/alerts/KubeAPIDown.md")).
+				To(Equal("https://github.com/openshift/ops-sop/blob/master/v4/alerts/KubeAPIDown.md"))
+		})
+	})
+
+	When("a SOP is browsed", func() {
+		var (
+			tui     *ui.TUI
+			browser tview.Primitive
+			opened  string
+			path    string
+		)
+
+		const base = "https://github.com/openshift/ops-sop/blob/master/"
+
+		// press sends the key to kite, the keys which aren't bound are forwarded to the SOP view
+		press := func(key tcell.Key) {
+			event := tui.App.GetInputCapture()(tcell.NewEventKey(key, 0, tcell.ModNone))
+			if event != nil {
+				browser.InputHandler()(event, func(tview.Primitive) {})
+			}
+		}
+
+		// location returns the URL displayed, as saved in the session
+		location := func() string {
+			for _, slide := range tui.CurrentSession().Slides {
+				if slide.Layout.Type == ui.PaneSOP {
+					return slide.Layout.URL
+				}
+			}
+			return ""
+		}
+
+		highlighted := func() []string {
+			return browser.(interface{ GetHighlights() []string }).GetHighlights()
+		}
+
+		BeforeEach(func() {
+			commit("v4/alerts/KubeAPIDown.md", "# KubeAPIDown\n\nSee [the etcd SOP](../knowledge_base/etcd.md), [the checks](#checks) and [the console](https://console.example.com).\n\n## Checks\n\n```sh\noc get pods\n```\n\n```sh\noc get nodes\n```\n")
+			commit("v4/knowledge_base/etcd.md", "# etcd\n\nBack to [the checks](/v4/alerts/KubeAPIDown.md#checks).\n")
+			Expect(sop.Sync(sop.DefaultOwner, sop.DefaultRepo, "", io.Discard)).To(Succeed())
+
+			// The links which aren't SOPs are opened with xdg-open
+			bin := filepath.Join(tmpDir, "bin")
+			opened = filepath.Join(tmpDir, "opened")
+			Expect(os.MkdirAll(bin, 0700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(bin, "xdg-open"), []byte("#!/bin/sh\necho \"$1\" > "+opened+"\n"), 0700)).To(Succeed())
+			path = os.Getenv("PATH")
+			os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+
+			tui = &ui.TUI{}
+			tui.Init()
+			ui.ViewAlertSOP(tui, base+"v4/alerts/KubeAPIDown.md")
+			browser = tui.Mux.Active().View().FocusedPane().Primitive()
+		})
+
+		AfterEach(func() {
+			os.Setenv("PATH", path)
+		})
+
+		It("follows the relative links and keeps a history", func() {
+			Expect(location()).To(Equal(base + "v4/alerts/KubeAPIDown.md"))
+			Expect(highlighted()).To(Equal([]string{"0"}))
+
+			press(tcell.KeyEnter)
+			Expect(location()).To(Equal(base + "v4/knowledge_base/etcd.md"))
+
+			press(tcell.KeyLeft)
+			Expect(location()).To(Equal(base + "v4/alerts/KubeAPIDown.md"))
+
+			press(tcell.KeyRight)
+			Expect(location()).To(Equal(base + "v4/knowledge_base/etcd.md"))
+
+			// The links starting with a slash are relative to the repository
+			press(tcell.KeyEnter)
+			Expect(location()).To(Equal(base + "v4/alerts/KubeAPIDown.md#checks"))
+			Expect(highlighted()).To(Equal([]string{"h1"}))
+
+			press(tcell.KeyLeft)
+			press(tcell.KeyLeft)
+			Expect(location()).To(Equal(base + "v4/alerts/KubeAPIDown.md"))
+		})
+
+		It("scrolls to the headings of the anchors", func() {
+			press(tcell.KeyTab)
+			Expect(highlighted()).To(Equal([]string{"1"}))
+
+			press(tcell.KeyEnter)
+			Expect(location()).To(Equal(base + "v4/alerts/KubeAPIDown.md#checks"))
+			Expect(highlighted()).To(Equal([]string{"h1"}))
+
+			// The links are cycled from the first one after jumping to a heading
+			press(tcell.KeyBacktab)
+			Expect(highlighted()).To(Equal([]string{"2"}))
+		})
+
+		It("highlights the code blocks", func() {
+			cycle := tcell.NewEventKey(tcell.KeyRune, 'C', tcell.ModNone)
+
+			Expect(tui.App.GetInputCapture()(cycle)).To(BeNil())
+			Expect(highlighted()).To(Equal([]string{"c0"}))
+
+			tui.App.GetInputCapture()(cycle)
+			tui.App.GetInputCapture()(cycle)
+			Expect(highlighted()).To(Equal([]string{"c0"}))
+
+			// Without a terminal slide, the code block isn't sent anywhere
+			press(tcell.KeyEnter)
+			Expect(tui.Root.HasPage(ui.TerminalListPageTitle)).To(BeFalse())
+			Expect(location()).To(Equal(base + "v4/alerts/KubeAPIDown.md"))
+		})
+
+		It("opens the other links with the desktop", func() {
+			press(tcell.KeyBacktab)
+			press(tcell.KeyEnter)
+
+			Eventually(func() (string, error) {
+				content, err := os.ReadFile(opened)
+				return string(content), err
+			}).Should(Equal("https://console.example.com\n"))
+			Expect(location()).To(Equal(base + "v4/alerts/KubeAPIDown.md"))
+		})
+	})
+})
+
+var _ = Describe("SOP code blocks", func() {
+	var (
+		tmpDir string
+		app    *tview.Application
+		term   *ui.Terminal
+		output string
+	)
+
+	// received returns what the program received, the lines it read
+	received := func() string {
+		content, _ := os.ReadFile(output)
+		return string(content)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "kite-code-*.d")
+		Expect(err).ToNot(HaveOccurred())
+		output = filepath.Join(tmpDir, "output")
+
+		// cat doesn't enable the bracketed paste, like sh and bash without readline
+		app = tview.NewApplication().SetScreen(tcell.NewSimulationScreen("UTF-8"))
+		term = ui.NewTerminal(exec.Command("sh", "-c", "cat > "+output), &ui.TUI{App: app})
+		term.SetRect(0, 0, 78, 10)
+		app.SetRoot(term, false)
+
+		go app.Run()
+
+		// The program is started when the terminal is first drawn
+		drawn := make(chan struct{})
+		app.QueueUpdateDraw(func() {})
+		app.QueueUpdate(func() { close(drawn) })
+		Eventually(drawn, "5s").Should(BeClosed())
+	})
+
+	AfterEach(func() {
+		app.Stop()
+		term.Close()
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("sends a line to the program without running it", func() {
+		Expect(term.PasteCode("echo kite\n")).To(Succeed())
+		Consistently(received, "500ms").Should(BeEmpty())
+
+		term.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+		Eventually(received, "5s").Should(Equal("echo kite\n"))
+	})
+
+	It("doesn't send several lines to a program without the bracketed paste", func() {
+		Expect(term.PasteCode("echo one\necho two")).To(MatchError(ContainSubstring("bracketed paste")))
+
+		term.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+		Eventually(received, "5s").Should(Equal("\n"))
+	})
+})
+
+var _ = Describe("SOP links", func() {
+	const sopURL = "https://github.com/openshift/ops-sop/blob/master/v4/alerts/KubeAPIDown.md"
+
+	It("resolves the links against the SOP", func() {
+		Expect(utils.ResolveLink(sopURL, "../howto/etcd.md")).To(Equal("https://github.com/openshift/ops-sop/blob/master/v4/howto/etcd.md"))
+		Expect(utils.ResolveLink(sopURL, "/README.md")).To(Equal("https://github.com/openshift/ops-sop/blob/master/README.md"))
+		Expect(utils.ResolveLink(sopURL, "#troubleshooting")).To(Equal(sopURL + "#troubleshooting"))
+		Expect(utils.ResolveLink(sopURL, "https://docs.openshift.com")).To(Equal("https://docs.openshift.com"))
+	})
+
+	It("recognizes the markdown files on GitHub", func() {
+		Expect(utils.IsGitHubMarkdown(sopURL)).To(BeTrue())
+		Expect(utils.IsGitHubMarkdown("https://github.com/openshift/ops-sop/tree/master/v4/README.md")).To(BeTrue())
+		Expect(utils.IsGitHubMarkdown("https://github.com/openshift/ops-sop")).To(BeFalse())
+		Expect(utils.IsGitHubMarkdown("https://example.com/openshift/ops-sop/blob/master/README.md")).To(BeFalse())
+	})
+})
+
+var _ = Describe("SOP sources", func() {
+	var (
+		tmpDir   string
+		server   *httptest.Server
+		requests chan *http.Request
+	)
+
+	// configure writes the SOP hosts in the kite configuration
+	configure := func(hosts ...config.SOPHost) {
+		data, err := json.Marshal(config.Config{SOPHosts: hosts})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0600)).To(Succeed())
+	}
+
+	useTempConfig(&tmpDir, "config.json")
+
+	BeforeEach(func() {
+		requests = make(chan *http.Request, 1)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests <- r
+
+			switch {
+			case r.URL.Path == "/sop.md", strings.HasSuffix(r.URL.Path, "/raw"):
+				fmt.Fprint(w, "# Markdown SOP\n")
+			case strings.HasPrefix(r.URL.Path, "/api/v3/repos/"):
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": "%s"}`, base64.StdEncoding.EncodeToString([]byte("# Enterprise SOP\n")))
+			case r.URL.Path == "/wiki/ClusterDown":
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				fmt.Fprint(w, "<html><head><title>Wiki</title></head><body><h1>Cluster Down</h1><p>Check <b>etcd</b>.</p></body></html>")
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("fetches the documents served over HTTP", func() {
+		content, err := utils.FetchMarkdown(server.URL + "/sop.md")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal("# Markdown SOP\n"))
+
+		// The pages without extension are only fetched from the configured hosts
+		Expect(utils.IsSOP(server.URL + "/wiki/ClusterDown")).To(BeFalse())
+	})
+
+	It("converts the HTML pages of the configured hosts, with their token", func() {
+		configure(config.SOPHost{Host: strings.TrimPrefix(server.URL, "http://"), Type: utils.HTTPSOPHost, Token: "wiki-token"})
+
+		content, err := utils.FetchMarkdown(server.URL + "/wiki/ClusterDown")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal("# Cluster Down\n\nCheck **etcd**.\n"))
+		Expect((<-requests).Header.Get("Authorization")).To(Equal("Bearer wiki-token"))
+	})
+
+	It("fetches the files of GitLab with the API", func() {
+		configure(config.SOPHost{Host: strings.TrimPrefix(server.URL, "http://"), Type: utils.GitLabSOPHost, Token: "gitlab-token"})
+		sopURL := server.URL + "/sre/ops/sop/-/blob/main/v4/alerts/KubeAPIDown.md"
+
+		content, err := utils.FetchMarkdown(sopURL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal("# Markdown SOP\n"))
+
+		request := <-requests
+		Expect(request.URL.EscapedPath()).To(Equal("/api/v4/projects/sre%2Fops%2Fsop/repository/files/v4%2Falerts%2FKubeAPIDown.md/raw"))
+		Expect(request.URL.Query().Get("ref")).To(Equal("main"))
+		Expect(request.Header.Get("PRIVATE-TOKEN")).To(Equal("gitlab-token"))
+
+		// The links starting with a slash are relative to the project
+		Expect(utils.ResolveLink(sopURL, "/README.md")).To(Equal(server.URL + "/sre/ops/sop/-/blob/main/README.md"))
+	})
+
+	It("fetches the files of GitHub Enterprise with the API", func() {
+		configure(config.SOPHost{Host: strings.TrimPrefix(server.URL, "http://"), Type: utils.GitHubSOPHost, Token: "ghe-token"})
+
+		content, err := utils.FetchMarkdown(server.URL + "/sre/ops-sop/blob/main/v4/alerts/KubeAPIDown.md")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal("# Enterprise SOP\n"))
+
+		request := <-requests
+		Expect(request.URL.Path).To(Equal("/api/v3/repos/sre/ops-sop/contents/v4/alerts/KubeAPIDown.md"))
+		Expect(request.URL.Query().Get("ref")).To(Equal("main"))
+		Expect(request.Header.Get("Authorization")).To(Equal("Bearer ghe-token"))
+	})
+
+	It("reads the local files", func() {
+		file := filepath.Join(tmpDir, "runbook.adoc")
+		Expect(os.WriteFile(file, []byte("= Runbook\n\nRestart *the* pods.\n"), 0600)).To(Succeed())
+
+		for _, URL := range []string{file, "file://" + filepath.ToSlash(file)} {
+			content, err := utils.FetchMarkdown(URL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(Equal("= Runbook\n\nRestart \\*the\\* pods.\n"))
+		}
+	})
+
+	It("reports the documents without source", func() {
+		for _, URL := range []string{"https://x", "https://github.com/openshift", "https://console.example.com", "relative.md"} {
+			Expect(utils.IsSOP(URL)).To(BeFalse())
+
+			_, err := utils.FetchMarkdown(URL)
+			Expect(err).To(MatchError(ContainSubstring("no SOP source")))
+		}
+	})
+
+	It("reports the unknown host types", func() {
+		configure(config.SOPHost{Host: "wiki.example.com", Type: "confluence"})
+
+		_, err := utils.FetchMarkdown(server.URL + "/sop.md")
+		Expect(err).To(MatchError(ContainSubstring("unknown type 'confluence'")))
+	})
+})
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ui"
+)
+
+var _ = Describe("themes", func() {
+	var tmpDir string
+
+	useTempConfig(&tmpDir, "config.json")
+
+	BeforeEach(func() {
+		Expect(os.MkdirAll(filepath.Join(tmpDir, ui.ThemesDir), 0755)).To(Succeed())
+	})
+
+	When("a built-in theme is selected", func() {
+		It("returns the built-in theme", func() {
+			theme, err := ui.LoadTheme(ui.LightTheme)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(theme.Background).To(Equal(tcell.ColorWhite))
+		})
+	})
+
+	When("a user defined theme is selected", func() {
+		It("extends the base theme with the configured colors", func() {
+			themeFile := `{"base": "high-contrast", "colors": {"border": "#ff00ff", "severity_high": "orange"}}`
+			Expect(os.WriteFile(filepath.Join(tmpDir, ui.ThemesDir, "solarized.json"), []byte(themeFile), 0600)).To(Succeed())
+
+			theme, err := ui.LoadTheme("solarized")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(theme.Border).To(Equal(tcell.NewHexColor(0xff00ff)))
+			Expect(theme.SeverityHigh).To(Equal(tcell.ColorOrange))
+			Expect(theme.Title).To(Equal(ui.Themes[ui.HighContrastTheme].Title))
+		})
+	})
+
+	When("a user defined theme has an invalid color", func() {
+		It("throws an error", func() {
+			themeFile := `{"colors": {"border": "not-a-color"}}`
+			Expect(os.WriteFile(filepath.Join(tmpDir, ui.ThemesDir, "broken.json"), []byte(themeFile), 0600)).To(Succeed())
+
+			_, err := ui.LoadTheme("broken")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("a theme name is a path", func() {
+		It("throws an error", func() {
+			Expect(os.WriteFile(filepath.Join(tmpDir, "outside.json"), []byte(`{}`), 0600)).To(Succeed())
+
+			_, err := ui.LoadTheme("../outside")
+			Expect(err).To(MatchError(ContainSubstring("invalid theme name")))
+
+			_, err = ui.LoadTheme(".hidden")
+			Expect(err).To(MatchError(ContainSubstring("invalid theme name")))
+		})
+	})
+})
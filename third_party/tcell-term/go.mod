@@ -0,0 +1,16 @@
+module git.sr.ht/~rockorager/tcell-term
+
+go 1.16
+
+require (
+	github.com/creack/pty v1.1.17
+	github.com/gdamore/tcell/v2 v2.5.4
+	github.com/stretchr/testify v1.8.2
+	golang.org/x/term v0.0.0-20220722155259-a9ba230a4035 // indirect
+)
+
+require (
+	github.com/mattn/go-runewidth v0.0.14
+	golang.org/x/sys v0.6.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)